@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"go-crud-api/models"
 	"os"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -22,6 +24,58 @@ func ConnectDatabase() {
 		panic("Gagal koneksi ke database!")
 	}
 
-	database.AutoMigrate(&models.User{}, &models.Category{}, &models.Transaction{})
+	database.AutoMigrate(&models.User{}, &models.Category{}, &models.Tag{}, &models.Transaction{}, &models.Attachment{}, &models.Rule{}, &models.DuplicateDismissal{}, &models.Reconciliation{}, &models.Goal{}, &models.GoalContribution{}, &models.Debt{}, &models.DebtPayment{}, &models.Bill{}, &models.BillPayment{}, &models.CalendarFeed{}, &models.Asset{}, &models.AssetValuation{}, &models.NetWorthSnapshot{}, &models.Portfolio{}, &models.Holding{}, &models.InvestmentActivity{}, &models.InstrumentPrice{}, &models.EnvelopeCategory{}, &models.EnvelopeAssignment{}, &models.Notification{}, &models.NotificationSettings{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.DomainEvent{}, &models.EventOffset{}, &models.StreamTicket{})
+	// Transactions created before occurred_at existed happened when inserted
+	database.Model(&models.Transaction{}).Where("occurred_at IS NULL").Update("occurred_at", gorm.Expr("created_at"))
+	// Rows created before uuids existed get a v7 one dated at their creation
+	for _, model := range []interface{}{&models.User{}, &models.Transaction{}, &models.Category{}, &models.Tag{}} {
+		err = backfillUuids(database, model)
+		if err != nil {
+			panic(err)
+		}
+	}
 	DB = database
 }
+
+// uuidBackfillBatch rows are read at a time while backfilling uuids
+const uuidBackfillBatch = 500
+
+// backfillUuids gives the rows of the model without a uuid a version 7 one
+// whose timestamp is their created_at, in creation order, so old rows sort
+// and cluster the same way as rows created with a uuid.
+func backfillUuids(database *gorm.DB, model interface{}) error {
+	for {
+		var rows []struct {
+			Id        int
+			CreatedAt time.Time
+		}
+		err := database.Model(model).Select("id, created_at").Where("uuid IS NULL").Order("created_at, id").Limit(uuidBackfillBatch).Find(&rows).Error
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+
+		for _, row := range rows {
+			id, err := uuidV7At(row.CreatedAt)
+			if err != nil {
+				return err
+			}
+			err = database.Model(model).Where("id = ?", row.Id).Update("uuid", id).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// uuidV7At returns a version 7 UUID with t as its timestamp instead of now.
+func uuidV7At(t time.Time) (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	ms := uint64(t.UnixMilli())
+	for i := 0; i < 6; i++ {
+		id[i] = byte(ms >> (40 - 8*i))
+	}
+	return id.String(), nil
+}
@@ -0,0 +1,18 @@
+// config/jobs.go
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// JobInterval reads how often a background job runs from the env key, as a
+// Go duration such as "24h". Zero disables the job; an empty or invalid
+// value falls back to the default.
+func JobInterval(key string, fallback time.Duration) time.Duration {
+	interval, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || interval < 0 {
+		return fallback
+	}
+	return interval
+}
@@ -0,0 +1,27 @@
+// config/notify.go
+package config
+
+import (
+	"go-crud-api/notify"
+	"os"
+)
+
+// NewNotifyChannels returns the delivery channels keyed by the name users
+// pick in their notification settings. Email is only available when
+// SMTP_HOST is set.
+func NewNotifyChannels() map[string]notify.Channel {
+	channels := map[string]notify.Channel{
+		"webhook": notify.NewWebhookChannel(),
+	}
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		channels["email"] = notify.NewEmailChannel(notify.EmailConfig{
+			Host:     host,
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		})
+	}
+	return channels
+}
@@ -0,0 +1,33 @@
+// config/storage.go
+package config
+
+import (
+	"go-crud-api/storage"
+	"os"
+)
+
+// NewStorage picks the attachment storage from STORAGE_DRIVER: "local"
+// (default) writes under STORAGE_LOCAL_DIR, "s3" talks to any S3 compatible
+// server such as MinIO.
+func NewStorage() storage.Storage {
+	if os.Getenv("STORAGE_DRIVER") == "s3" {
+		s3Storage, err := storage.NewS3Storage(storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			UseSSL:    os.Getenv("S3_USE_SSL") == "true",
+		})
+		if err != nil {
+			panic("Gagal koneksi ke S3 storage: " + err.Error())
+		}
+		return s3Storage
+	}
+
+	root := os.Getenv("STORAGE_LOCAL_DIR")
+	if root == "" {
+		root = "uploads"
+	}
+	return storage.NewLocalStorage(root)
+}
@@ -0,0 +1,133 @@
+// Package events dispatches the domain events of the outbox to in-process
+// subscribers.
+package events
+
+import (
+	"fmt"
+	"go-crud-api/models"
+	"go-crud-api/repository"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// batchSize is how many events a subscriber is handed per query.
+const batchSize = 100
+
+// Handler handles one event. Returning an error stops the subscriber at
+// that event, which is handed to it again on the next run.
+type Handler func(event models.DomainEvent) error
+
+type subscriber struct {
+	Name    string
+	Handler Handler
+}
+
+// Dispatcher delivers every committed event to each subscriber in sequence
+// order, at least once. The offset of a subscriber is saved after each
+// handled event, so a crash in between hands that event over again.
+type Dispatcher struct {
+	Repository  repository.Repository
+	Db          *gorm.DB
+	subscribers []subscriber
+}
+
+func NewDispatcher(repository repository.Repository, db *gorm.DB) *Dispatcher {
+	return &Dispatcher{Repository: repository, Db: db}
+}
+
+// Subscribe registers a handler under a unique name, which keys its offset.
+// A subscriber seen for the first time starts after the latest event; older
+// events can be handed to it with a replay.
+func (d *Dispatcher) Subscribe(name string, handler Handler) {
+	d.subscribers = append(d.subscribers, subscriber{Name: name, Handler: handler})
+}
+
+// Dispatch sequences the newly committed events and hands them to every
+// subscriber. It is run by the scheduler; an instance that finds another
+// one dispatching skips the run.
+//
+// The transaction only holds the dispatch lock. Sequences and offsets are
+// committed as they are written, so an offset never points past a sequence
+// that could still be rolled back.
+func (d *Dispatcher) Dispatch() (err error) {
+	err = d.Db.Transaction(func(tx *gorm.DB) error {
+		locked, errTx := d.Repository.TryLockEventDispatch(tx)
+		if errTx != nil || !locked {
+			return errTx
+		}
+
+		errTx = d.Repository.SequenceDomainEvents(d.Db)
+		if errTx != nil {
+			return errTx
+		}
+		latest, errTx := d.Repository.GetLatestEventSequence(d.Db)
+		if errTx != nil {
+			return errTx
+		}
+
+		var failed []string
+		for _, sub := range d.subscribers {
+			errSub := d.Repository.InitEventOffset(d.Db, sub.Name, latest)
+			if errSub == nil {
+				errSub = d.dispatchTo(sub)
+			}
+			if errSub != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", sub.Name, errSub))
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("event dispatch failed for %d of %d subscribers: %s", len(failed), len(d.subscribers), strings.Join(failed, "; "))
+		}
+		return nil
+	})
+	return
+}
+
+// dispatchTo hands the subscriber every event after its offset, stopping at
+// the first one it fails on.
+func (d *Dispatcher) dispatchTo(sub subscriber) (err error) {
+	offset, err := d.Repository.GetEventOffset(d.Db, sub.Name)
+	if err != nil {
+		return
+	}
+
+	sequence := offset.Sequence
+	for {
+		events, errEvents := d.Repository.GetDomainEventsAfter(d.Db, sequence, batchSize)
+		if errEvents != nil {
+			return errEvents
+		}
+
+		for _, event := range events {
+			err = handle(sub.Handler, event)
+			if err != nil {
+				err = fmt.Errorf("event %d (%s): %w", *event.Sequence, event.Type, err)
+				return
+			}
+
+			sequence = *event.Sequence
+			err = d.Repository.SaveEventOffset(d.Db, sub.Name, sequence)
+			if err != nil {
+				return
+			}
+		}
+
+		if len(events) < batchSize {
+			return
+		}
+	}
+}
+
+// handle runs the handler, turning a panic into an error so one bad event
+// doesn't take the other subscribers down.
+func handle(handler Handler, event models.DomainEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	err = handler(event)
+	return
+}
@@ -0,0 +1,45 @@
+// Package graphql serves a GraphQL API over the service layer with
+// graph-gophers/graphql-go. The schema is in schema.graphql and its
+// resolvers in resolvers.go.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"go-crud-api/models"
+	"go-crud-api/services"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const (
+	// MaxDepth is how deeply a query may nest its selections.
+	MaxDepth = 10
+	// MaxQueryLength is the longest query accepted, in bytes.
+	MaxQueryLength = 10000
+	// maxParallelism bounds the resolvers of a request running at once.
+	maxParallelism = 10
+)
+
+//go:embed schema.graphql
+var sdl string
+
+var appSchema = graphql.MustParseSchema(sdl, &rootResolver{},
+	graphql.UseStringDescriptions(),
+	graphql.MaxDepth(MaxDepth),
+	graphql.MaxQueryLength(MaxQueryLength),
+	graphql.MaxParallelism(maxParallelism),
+)
+
+// Execute runs the query of the request as the user. Errors of the request
+// itself, like a syntax error or a query above the limits, come back
+// without data.
+func Execute(ctx context.Context, service services.Service, user models.User, req models.RequestGraphQL) *graphql.Response {
+	ctx = context.WithValue(ctx, requestKey{}, newRequest(service, user))
+	return appSchema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
+
+// SDL is the schema in the GraphQL schema definition language.
+func SDL() string {
+	return sdl
+}
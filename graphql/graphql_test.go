@@ -0,0 +1,159 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"go-crud-api/models"
+	"go-crud-api/services"
+	"strings"
+	"testing"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+// stubService answers the calls the resolvers make and records the
+// transaction filters they ask for. Other methods are not expected.
+type stubService struct {
+	services.Service
+	transactionRequests []models.RequestGetTransactions
+	userBatches         [][]int
+}
+
+func (s *stubService) GetAllUsers(req models.RequestGetAllUsers) (response models.ResponseUserList, err error) {
+	response.Data = []models.UserResponse{{Id: 1, Name: "Admin", Role: "admin"}, {Id: 2, Name: "Budi", Role: "user"}}
+	response.Count = 2
+	return
+}
+
+func (s *stubService) GetUsersByIds(ids []int) (users []models.UserResponse, err error) {
+	s.userBatches = append(s.userBatches, ids)
+	for _, id := range ids {
+		users = append(users, models.UserResponse{Id: id, Name: "User"})
+	}
+	return
+}
+
+func (s *stubService) GetTransactions(req models.RequestGetTransactions) (response models.ResponseTransactionList, err error) {
+	s.transactionRequests = append(s.transactionRequests, req)
+	response.Data = []models.TransactionResponse{
+		{Id: 10, User: models.UserSimpleResponse{Id: 2}},
+		{Id: 11, User: models.UserSimpleResponse{Id: 3}},
+		{Id: 12, User: models.UserSimpleResponse{Id: 2}},
+	}
+	response.Count = 3
+	return
+}
+
+var (
+	admin = models.User{Id: 1, Name: "Admin", Role: "admin"}
+	user  = models.User{Id: 2, Name: "Budi", Role: "user"}
+)
+
+func execute(t *testing.T, service services.Service, as models.User, query string) (data map[string]interface{}, errs []*gqlerrors.QueryError) {
+	t.Helper()
+	response := Execute(context.Background(), service, as, models.RequestGraphQL{Query: query})
+	if len(response.Data) > 0 {
+		if err := json.Unmarshal(response.Data, &data); err != nil {
+			t.Fatalf("unmarshal %s: %v", response.Data, err)
+		}
+	}
+	return data, response.Errors
+}
+
+func TestAdminOnlyFields(t *testing.T) {
+	for _, query := range []string{
+		`{ users { totalCount } }`,
+		`{ user(id: "1") { name } }`,
+	} {
+		_, errs := execute(t, &stubService{}, user, query)
+		if len(errs) != 1 || errs[0].Extensions["code"] != "FORBIDDEN" {
+			t.Errorf("%s as a user: expected FORBIDDEN, got %v", query, errs)
+		}
+	}
+
+	data, errs := execute(t, &stubService{}, admin, `{ users { totalCount nodes { name } } }`)
+	if len(errs) > 0 {
+		t.Fatalf("as an admin: unexpected errors %v", errs)
+	}
+	if data["users"].(map[string]interface{})["totalCount"] != float64(2) {
+		t.Fatalf("as an admin: unexpected data %v", data)
+	}
+}
+
+func TestForbiddenFieldKeepsOtherData(t *testing.T) {
+	data, errs := execute(t, &stubService{}, user, `{ me { name } user(id: "1") { name } }`)
+	if len(errs) != 1 || errs[0].Path[0] != "user" {
+		t.Fatalf("expected one error on user, got %v", errs)
+	}
+	if data["me"].(map[string]interface{})["name"] != "Budi" {
+		t.Fatalf("expected me to resolve, got %v", data)
+	}
+}
+
+func TestTransactionsScopedToUser(t *testing.T) {
+	service := &stubService{}
+	_, errs := execute(t, service, user, `{ transactions(filter: {userId: "1"}) { nodes { id user { id } } } }`)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if got := service.transactionRequests[0].UserId; got != user.Id {
+		t.Fatalf("a user asking for another user's transactions got user %d", got)
+	}
+
+	service = &stubService{}
+	execute(t, service, admin, `{ transactions { totalCount } }`)
+	if got := service.transactionRequests[0].UserId; got != 0 {
+		t.Fatalf("an admin without a filter should list every user, got user %d", got)
+	}
+
+	service = &stubService{}
+	execute(t, service, admin, `{ transactions(filter: {userId: "2"}) { totalCount } }`)
+	if got := service.transactionRequests[0].UserId; got != 2 {
+		t.Fatalf("an admin filtering on user 2 got user %d", got)
+	}
+}
+
+func TestUsersLoadedPerPage(t *testing.T) {
+	service := &stubService{}
+	data, errs := execute(t, service, admin, `{ transactions { nodes { id user { id } } } }`)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if len(service.userBatches) != 1 || len(service.userBatches[0]) != 2 {
+		t.Fatalf("expected the two users of the page in one call, got %v", service.userBatches)
+	}
+	nodes := data["transactions"].(map[string]interface{})["nodes"].([]interface{})
+	if nodes[1].(map[string]interface{})["user"].(map[string]interface{})["id"] != float64(3) {
+		t.Fatalf("unexpected nodes %v", nodes)
+	}
+}
+
+func TestRequestErrorsHaveNoData(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unknown field", `{ me { password } }`},
+		{"syntax error", `{ me { name }`},
+		{"too long", `{ me { name } }` + strings.Repeat(" ", MaxQueryLength)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := Execute(context.Background(), &stubService{}, user, models.RequestGraphQL{Query: test.query})
+			body, _ := json.Marshal(response)
+			if strings.Contains(string(body), `"data"`) || len(response.Errors) == 0 {
+				t.Fatalf("expected an error without data, got %s", body)
+			}
+		})
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	data, errs := execute(t, &stubService{}, user, `{ __type(name: "Transaction") { fields { name } } }`)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if data["__type"] == nil {
+		t.Fatalf("expected the Transaction type, got %v", data)
+	}
+}
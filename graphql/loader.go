@@ -0,0 +1,71 @@
+package graphql
+
+import "sync"
+
+// loader fetches the ids asked for by the nodes of a page in one call, like
+// a DataLoader: a page primes the ids of all its nodes, and the first node
+// to load a value fetches every queued id. A loader lives for one request
+// and keeps what it fetched.
+type loader[T any] struct {
+	fetch func(ids []int) (map[int]T, error)
+
+	mu      sync.Mutex
+	pending []int
+	known   map[int]bool
+	values  map[int]T
+	errs    map[int]error
+}
+
+func newLoader[T any](fetch func(ids []int) (map[int]T, error)) *loader[T] {
+	return &loader[T]{
+		fetch:  fetch,
+		known:  map[int]bool{},
+		values: map[int]T{},
+		errs:   map[int]error{},
+	}
+}
+
+// prime queues the ids to be fetched with the next load.
+func (l *loader[T]) prime(ids ...int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		if !l.known[id] {
+			l.known[id] = true
+			l.pending = append(l.pending, id)
+		}
+	}
+}
+
+// load returns the value of the id, fetching it with the queued ids when it
+// was not fetched yet. ok is false when nothing has the id.
+func (l *loader[T]) load(id int) (value T, ok bool, err error) {
+	l.prime(id)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.pending) > 0 {
+		l.dispatch()
+	}
+	if err = l.errs[id]; err != nil {
+		return
+	}
+	value, ok = l.values[id]
+	return
+}
+
+func (l *loader[T]) dispatch() {
+	ids := l.pending
+	l.pending = nil
+
+	values, err := l.fetch(ids)
+	for _, id := range ids {
+		if err != nil {
+			l.errs[id] = err
+			continue
+		}
+		if value, ok := values[id]; ok {
+			l.values[id] = value
+		}
+	}
+}
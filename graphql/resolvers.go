@@ -0,0 +1,602 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"go-crud-api/services"
+	"strconv"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+// timeLayout matches the dates of the transaction responses.
+const timeLayout = "2006-01-02 15:04:05"
+
+var errAdminOnly = errors.New("forbidden: insufficient permissions")
+
+type requestKey struct{}
+
+// request is what the resolvers of one GraphQL request share: the service,
+// the user asking and the loaders batching the lookups of their fields.
+type request struct {
+	service    services.Service
+	user       models.User
+	users      *loader[models.UserResponse]
+	categories *loader[models.Category]
+}
+
+func newRequest(service services.Service, user models.User) *request {
+	r := &request{service: service, user: user}
+	r.users = newLoader(func(ids []int) (map[int]models.UserResponse, error) {
+		users, err := service.GetUsersByIds(ids)
+		if err != nil {
+			return nil, err
+		}
+		byId := map[int]models.UserResponse{}
+		for _, user := range users {
+			byId[user.Id] = user
+		}
+		return byId, nil
+	})
+	r.categories = newLoader(func(ids []int) (map[int]models.Category, error) {
+		categories, err := service.GetCategoriesByIds(ids)
+		if err != nil {
+			return nil, err
+		}
+		byId := map[int]models.Category{}
+		for _, category := range categories {
+			byId[category.Id] = category
+		}
+		return byId, nil
+	})
+	return r
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+func (r *request) isAdmin() bool {
+	return r.user.Role == "admin"
+}
+
+// resolveId takes an integer id or a uuid, like the :id of the REST routes.
+func (r *request) resolveId(entity string, raw graphql.ID) (id int, err error) {
+	if services.IsUuid(string(raw)) {
+		return r.service.ResolvePublicId(entity, string(raw), r.user.Id)
+	}
+	id, err = strconv.Atoi(string(raw))
+	if err != nil || id < 1 {
+		err = errors.New("invalid id: use an integer id or a uuid")
+	}
+	return
+}
+
+// categoryIds resolves the uuids among the ids to the integer ids the
+// transaction filter takes.
+func (r *request) categoryIds(raws *[]graphql.ID) (ids []string, err error) {
+	if raws == nil {
+		return
+	}
+	for _, raw := range *raws {
+		id, errId := r.resolveId(services.PublicIdCategory, raw)
+		if errId != nil {
+			return nil, errId
+		}
+		ids = append(ids, strconv.Itoa(id))
+	}
+	return
+}
+
+// resolverError gives service errors the extensions.code matching the
+// status code of the REST handlers.
+type resolverError struct {
+	err error
+}
+
+func (e resolverError) Error() string {
+	return e.err.Error()
+}
+
+func (e resolverError) Extensions() map[string]interface{} {
+	if code := errorCode(e.err); code != "" {
+		return map[string]interface{}{"code": code}
+	}
+	return nil
+}
+
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return resolverError{err: err}
+}
+
+// errorCode classifies service errors the way the REST handlers pick their
+// status codes.
+func errorCode(err error) string {
+	message := err.Error()
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return "NOT_FOUND"
+	case strings.HasPrefix(message, "invalid "):
+		return "BAD_USER_INPUT"
+	case strings.HasPrefix(message, "unauthorized:"), strings.HasPrefix(message, "forbidden:"):
+		return "FORBIDDEN"
+	}
+	return ""
+}
+
+// pageArgs are the paging arguments of the list fields.
+type pageArgs struct {
+	First  int32
+	Cursor *string
+	Sort   *string
+}
+
+// pagination turns the paging arguments into the cursor pagination of the
+// list endpoints, which caps the page size.
+func (args pageArgs) pagination() models.RequestPagination {
+	first := int(args.First)
+	if first < 1 {
+		first = helper.DefaultPageSize
+	}
+	return models.RequestPagination{
+		Limit:     strconv.Itoa(first),
+		Sort:      value(args.Sort),
+		Cursor:    value(args.Cursor),
+		UseCursor: true,
+	}
+}
+
+func value[T any](p *T) (v T) {
+	if p != nil {
+		v = *p
+	}
+	return
+}
+
+// optional turns an empty string into null.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+type rootResolver struct{}
+
+func (rootResolver) Me(ctx context.Context) *userResolver {
+	user := requestFrom(ctx).user
+	return &userResolver{models.UserResponse{
+		Id:       user.Id,
+		Uuid:     user.Uuid,
+		Name:     user.Name,
+		Username: user.Username,
+		Role:     user.Role,
+	}}
+}
+
+func (rootResolver) User(ctx context.Context, args struct{ Id graphql.ID }) (*userResolver, error) {
+	r := requestFrom(ctx)
+	if !r.isAdmin() {
+		return nil, wrapError(errAdminOnly)
+	}
+	id, err := r.resolveId(services.PublicIdUser, args.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	user, ok, err := r.users.load(id)
+	if !ok || err != nil {
+		return nil, wrapError(err)
+	}
+	return &userResolver{user}, nil
+}
+
+func (rootResolver) Users(ctx context.Context, args pageArgs) (*userConnection, error) {
+	r := requestFrom(ctx)
+	if !r.isAdmin() {
+		return nil, wrapError(errAdminOnly)
+	}
+	users, err := r.service.GetAllUsers(models.RequestGetAllUsers{RequestPagination: args.pagination()})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &userConnection{users.Data, pageInfo{users.Count, users.NextCursor, users.PrevCursor}}, nil
+}
+
+func (rootResolver) Category(ctx context.Context, args struct{ Id graphql.ID }) (*categoryResolver, error) {
+	r := requestFrom(ctx)
+	id, err := r.resolveId(services.PublicIdCategory, args.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	category, ok, err := r.categories.load(id)
+	if !ok || err != nil {
+		return nil, wrapError(err)
+	}
+	return &categoryResolver{category}, nil
+}
+
+func (rootResolver) Categories(ctx context.Context, args struct {
+	First  int32
+	Cursor *string
+	Sort   *string
+	Search *string
+}) (*categoryConnection, error) {
+	r := requestFrom(ctx)
+	categories, err := r.service.GetCategories(models.RequestGetCategories{
+		Name:              value(args.Search),
+		RequestPagination: pageArgs{args.First, args.Cursor, args.Sort}.pagination(),
+	})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &categoryConnection{categories.Data, pageInfo{categories.Count, categories.NextCursor, categories.PrevCursor}}, nil
+}
+
+func (rootResolver) Transaction(ctx context.Context, args struct{ Id graphql.ID }) (*transactionResolver, error) {
+	r := requestFrom(ctx)
+	id, err := r.resolveId(services.PublicIdTransaction, args.Id)
+	if err == nil {
+		var transaction models.TransactionResponse
+		transaction, err = r.service.GetTransactionById(models.RequestGetTransactionById{Id: id}, r.user.Id)
+		if err == nil {
+			return &transactionResolver{transaction}, nil
+		}
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return nil, wrapError(err)
+}
+
+// transactionFilter is the TransactionFilter input.
+type transactionFilter struct {
+	UserId             *graphql.ID
+	CategoryIds        *[]graphql.ID
+	ExcludeCategoryIds *[]graphql.ID
+	Types              *[]string
+	ExcludeTypes       *[]string
+	MinAmount          *float64
+	MaxAmount          *float64
+	Search             *string
+	Tags               *[]string
+	ExcludeTags        *[]string
+	Statuses           *[]string
+	StartDate          *string
+	EndDate            *string
+}
+
+func (rootResolver) Transactions(ctx context.Context, args struct {
+	First  int32
+	Cursor *string
+	Sort   *string
+	Filter *transactionFilter
+}) (*transactionConnection, error) {
+	r := requestFrom(ctx)
+	req := models.RequestGetTransactions{
+		RequestPagination: pageArgs{args.First, args.Cursor, args.Sort}.pagination(),
+		UserId:            r.user.Id,
+	}
+
+	// Users only see their own transactions; admins see every user's unless
+	// they pick one
+	filter := value(args.Filter)
+	if r.isAdmin() {
+		req.UserId = 0
+		if filter.UserId != nil {
+			id, err := r.resolveId(services.PublicIdUser, *filter.UserId)
+			if err != nil {
+				return nil, wrapError(err)
+			}
+			req.UserId = id
+		}
+	}
+
+	var err error
+	if req.CategoryIds, err = r.categoryIds(filter.CategoryIds); err != nil {
+		return nil, wrapError(err)
+	}
+	if req.ExcludeCategoryIds, err = r.categoryIds(filter.ExcludeCategoryIds); err != nil {
+		return nil, wrapError(err)
+	}
+	if filter.MinAmount != nil {
+		req.MinAmount = strconv.FormatFloat(*filter.MinAmount, 'f', -1, 64)
+	}
+	if filter.MaxAmount != nil {
+		req.MaxAmount = strconv.FormatFloat(*filter.MaxAmount, 'f', -1, 64)
+	}
+	req.Types = value(filter.Types)
+	req.ExcludeTypes = value(filter.ExcludeTypes)
+	req.Search = value(filter.Search)
+	req.Tags = value(filter.Tags)
+	req.ExcludeTags = value(filter.ExcludeTags)
+	req.Statuses = value(filter.Statuses)
+	req.StartDate = value(filter.StartDate)
+	req.EndDate = value(filter.EndDate)
+
+	transactions, err := r.service.GetTransactions(req)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	// The users and categories of the page are fetched together
+	for _, transaction := range transactions.Data {
+		r.users.prime(transaction.User.Id)
+		r.categories.prime(transaction.Category.Id)
+	}
+	return &transactionConnection{transactions.Data, pageInfo{transactions.Count, transactions.NextCursor, transactions.PrevCursor}}, nil
+}
+
+func (rootResolver) Balance(ctx context.Context, args struct {
+	StartDate *string
+	EndDate   *string
+}) (*balanceResolver, error) {
+	r := requestFrom(ctx)
+	balance, err := r.service.GetBalance(models.RequestGetBalance{
+		UserId:    r.user.Id,
+		StartDate: value(args.StartDate),
+		EndDate:   value(args.EndDate),
+	})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &balanceResolver{balance}, nil
+}
+
+func (rootResolver) CreateTransaction(ctx context.Context, args struct {
+	Input struct {
+		Uuid       *graphql.ID
+		Amount     float64
+		Type       string
+		CategoryId graphql.ID
+		Note       *string
+		Payee      *string
+		OccurredAt *string
+		Tags       *[]string
+	}
+}) (*transactionResolver, error) {
+	r := requestFrom(ctx)
+	input := args.Input
+
+	categoryId, err := r.resolveId(services.PublicIdCategory, input.CategoryId)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	transaction, err := r.service.CreateTransaction(r.user.Id, models.RequestCreateTransaction{
+		Uuid:       string(value(input.Uuid)),
+		Amount:     input.Amount,
+		Type:       input.Type,
+		CategoryId: categoryId,
+		Note:       value(input.Note),
+		Payee:      value(input.Payee),
+		OccurredAt: value(input.OccurredAt),
+		Tags:       value(input.Tags),
+	})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &transactionResolver{transaction}, nil
+}
+
+func (rootResolver) UpdateTransaction(ctx context.Context, args struct {
+	Id    graphql.ID
+	Input struct {
+		Amount     graphql.NullFloat
+		Type       graphql.NullString
+		CategoryId graphql.NullID
+		Note       graphql.NullString
+		Payee      graphql.NullString
+		OccurredAt graphql.NullString
+		Tags       *[]string
+	}
+}) (*transactionResolver, error) {
+	r := requestFrom(ctx)
+	id, err := r.resolveId(services.PublicIdTransaction, args.Id)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	input := args.Input
+
+	req := models.RequestPatchTransaction{
+		Amount:     nullable(input.Amount.Set, input.Amount.Value),
+		Type:       nullable(input.Type.Set, input.Type.Value),
+		Note:       nullable(input.Note.Set, input.Note.Value),
+		Payee:      nullable(input.Payee.Set, input.Payee.Value),
+		OccurredAt: nullable(input.OccurredAt.Set, input.OccurredAt.Value),
+	}
+	if input.CategoryId.Set {
+		req.CategoryId.Set, req.CategoryId.Null = true, input.CategoryId.Value == nil
+		if input.CategoryId.Value != nil {
+			req.CategoryId.Value, err = r.resolveId(services.PublicIdCategory, *input.CategoryId.Value)
+			if err != nil {
+				return nil, wrapError(err)
+			}
+		}
+	}
+	if input.Tags != nil {
+		req.Tags = models.Nullable[[]string]{Set: true, Value: *input.Tags}
+	}
+
+	transaction, err := r.service.PatchTransaction(id, r.user.Id, req)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &transactionResolver{transaction}, nil
+}
+
+func (rootResolver) DeleteTransaction(ctx context.Context, args struct{ Id graphql.ID }) (bool, error) {
+	r := requestFrom(ctx)
+	id, err := r.resolveId(services.PublicIdTransaction, args.Id)
+	if err != nil {
+		return false, wrapError(err)
+	}
+	err = r.service.DeleteTransaction(id, r.user.Id)
+	return err == nil, wrapError(err)
+}
+
+// nullable reads an input field the way a JSON merge patch field is read:
+// left out, null or a value.
+func nullable[T any](set bool, v *T) (n models.Nullable[T]) {
+	n.Set, n.Null = set, set && v == nil
+	n.Value = value(v)
+	return
+}
+
+type userResolver struct {
+	user models.UserResponse
+}
+
+func (u *userResolver) Id() int32        { return int32(u.user.Id) }
+func (u *userResolver) Uuid() graphql.ID { return graphql.ID(u.user.Uuid) }
+func (u *userResolver) Name() string     { return u.user.Name }
+func (u *userResolver) Username() string { return u.user.Username }
+func (u *userResolver) Role() string     { return u.user.Role }
+
+type categoryResolver struct {
+	category models.Category
+}
+
+func (c *categoryResolver) Id() int32         { return int32(c.category.Id) }
+func (c *categoryResolver) Uuid() graphql.ID  { return graphql.ID(c.category.Uuid) }
+func (c *categoryResolver) Name() string      { return c.category.Name }
+func (c *categoryResolver) CreatedAt() string { return c.category.CreatedAt.Format(timeLayout) }
+func (c *categoryResolver) UpdatedAt() string { return c.category.UpdatedAt.Format(timeLayout) }
+
+type tagResolver struct {
+	tag models.TagSimpleResponse
+}
+
+func (t *tagResolver) Id() int32    { return int32(t.tag.Id) }
+func (t *tagResolver) Name() string { return t.tag.Name }
+
+type transactionResolver struct {
+	transaction models.TransactionResponse
+}
+
+func (t *transactionResolver) Id() int32              { return int32(t.transaction.Id) }
+func (t *transactionResolver) Uuid() graphql.ID       { return graphql.ID(t.transaction.Uuid) }
+func (t *transactionResolver) Amount() float64        { return t.transaction.Amount }
+func (t *transactionResolver) Type() string           { return t.transaction.Type }
+func (t *transactionResolver) Note() string           { return t.transaction.Note }
+func (t *transactionResolver) Payee() string          { return t.transaction.Payee }
+func (t *transactionResolver) OccurredAt() string     { return t.transaction.OccurredAt }
+func (t *transactionResolver) Status() string         { return t.transaction.Status }
+func (t *transactionResolver) BankReference() *string { return optional(t.transaction.BankReference) }
+func (t *transactionResolver) CreatedAt() string      { return t.transaction.CreatedAt }
+func (t *transactionResolver) UpdatedAt() string      { return t.transaction.UpdatedAt }
+
+func (t *transactionResolver) ReconciliationId() *int32 {
+	if t.transaction.ReconciliationId == nil {
+		return nil
+	}
+	id := int32(*t.transaction.ReconciliationId)
+	return &id
+}
+
+func (t *transactionResolver) Tags() []*tagResolver {
+	tags := []*tagResolver{}
+	for _, tag := range t.transaction.Tags {
+		tags = append(tags, &tagResolver{tag})
+	}
+	return tags
+}
+
+func (t *transactionResolver) User(ctx context.Context) (*userResolver, error) {
+	user, ok, err := requestFrom(ctx).users.load(t.transaction.User.Id)
+	if !ok || err != nil {
+		return nil, wrapError(err)
+	}
+	return &userResolver{user}, nil
+}
+
+func (t *transactionResolver) Category(ctx context.Context) (*categoryResolver, error) {
+	category, ok, err := requestFrom(ctx).categories.load(t.transaction.Category.Id)
+	if !ok || err != nil {
+		return nil, wrapError(err)
+	}
+	return &categoryResolver{category}, nil
+}
+
+type balanceResolver struct {
+	balance models.ResponseBalance
+}
+
+func (b *balanceResolver) UserId() int32         { return int32(b.balance.UserId) }
+func (b *balanceResolver) TotalIncome() float64  { return b.balance.TotalIncome }
+func (b *balanceResolver) TotalExpense() float64 { return b.balance.TotalExpense }
+func (b *balanceResolver) Balance() float64      { return b.balance.Balance }
+func (b *balanceResolver) StartDate() string     { return b.balance.StartDate }
+func (b *balanceResolver) EndDate() string       { return b.balance.EndDate }
+
+// pageInfo is the count and cursors of a page of a list field.
+type pageInfo struct {
+	count      int64
+	nextCursor string
+	prevCursor string
+}
+
+func (p pageInfo) HasNextPage() bool     { return p.nextCursor != "" }
+func (p pageInfo) HasPreviousPage() bool { return p.prevCursor != "" }
+func (p pageInfo) NextCursor() *string   { return optional(p.nextCursor) }
+func (p pageInfo) PrevCursor() *string   { return optional(p.prevCursor) }
+
+type userConnection struct {
+	nodes []models.UserResponse
+	page  pageInfo
+}
+
+func (c *userConnection) TotalCount() int32   { return int32(c.page.count) }
+func (c *userConnection) PageInfo() *pageInfo { return &c.page }
+
+func (c *userConnection) Nodes() []*userResolver {
+	nodes := []*userResolver{}
+	for _, user := range c.nodes {
+		nodes = append(nodes, &userResolver{user})
+	}
+	return nodes
+}
+
+type categoryConnection struct {
+	nodes []models.Category
+	page  pageInfo
+}
+
+func (c *categoryConnection) TotalCount() int32   { return int32(c.page.count) }
+func (c *categoryConnection) PageInfo() *pageInfo { return &c.page }
+
+func (c *categoryConnection) Nodes() []*categoryResolver {
+	nodes := []*categoryResolver{}
+	for _, category := range c.nodes {
+		nodes = append(nodes, &categoryResolver{category})
+	}
+	return nodes
+}
+
+type transactionConnection struct {
+	nodes []models.TransactionResponse
+	page  pageInfo
+}
+
+func (c *transactionConnection) TotalCount() int32   { return int32(c.page.count) }
+func (c *transactionConnection) PageInfo() *pageInfo { return &c.page }
+
+func (c *transactionConnection) Nodes() []*transactionResolver {
+	nodes := []*transactionResolver{}
+	for _, transaction := range c.nodes {
+		nodes = append(nodes, &transactionResolver{transaction})
+	}
+	return nodes
+}
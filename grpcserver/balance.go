@@ -0,0 +1,32 @@
+package grpcserver
+
+import (
+	"context"
+	"go-crud-api/models"
+	financev1 "go-crud-api/proto/finance/v1"
+)
+
+type balanceServer struct {
+	financev1.UnimplementedBalanceServiceServer
+	*server
+}
+
+func (s *balanceServer) GetBalance(ctx context.Context, req *financev1.GetBalanceRequest) (*financev1.GetBalanceResponse, error) {
+	balance, err := s.service.GetBalance(models.RequestGetBalance{
+		UserId:    currentUser(ctx).Id,
+		StartDate: req.GetStartDate(),
+		EndDate:   req.GetEndDate(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &financev1.GetBalanceResponse{Balance: &financev1.Balance{
+		UserId:       int64(balance.UserId),
+		TotalIncome:  balance.TotalIncome,
+		TotalExpense: balance.TotalExpense,
+		Balance:      balance.Balance,
+		StartDate:    balance.StartDate,
+		EndDate:      balance.EndDate,
+	}}, nil
+}
@@ -0,0 +1,91 @@
+package grpcserver
+
+import (
+	"context"
+	"go-crud-api/models"
+	financev1 "go-crud-api/proto/finance/v1"
+	"go-crud-api/services"
+)
+
+// timeLayout matches the dates of the REST responses.
+const timeLayout = "2006-01-02 15:04:05"
+
+type categoryServer struct {
+	financev1.UnimplementedCategoryServiceServer
+	*server
+}
+
+func (s *categoryServer) ListCategories(ctx context.Context, req *financev1.ListCategoriesRequest) (*financev1.ListCategoriesResponse, error) {
+	categories, err := s.service.GetCategories(models.RequestGetCategories{Name: req.GetSearch(), RequestPagination: pagination(req.GetPage())})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &financev1.ListCategoriesResponse{PageInfo: pageInfo(categories.Count, categories.NextCursor, categories.PrevCursor)}
+	for _, category := range categories.Data {
+		response.Categories = append(response.Categories, categoryMessage(category))
+	}
+	return response, nil
+}
+
+func (s *categoryServer) GetCategory(ctx context.Context, req *financev1.GetCategoryRequest) (*financev1.GetCategoryResponse, error) {
+	id, err := s.resolveId(ctx, services.PublicIdCategory, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.service.GetCategoryById(models.RequestGetCategoryById{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.GetCategoryResponse{Category: categoryMessage(category)}, nil
+}
+
+func (s *categoryServer) CreateCategory(ctx context.Context, req *financev1.CreateCategoryRequest) (*financev1.CreateCategoryResponse, error) {
+	category, err := s.service.CreateCategory(models.RequestCreateCategory{Uuid: req.GetUuid(), Name: req.GetName()})
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.CreateCategoryResponse{Category: categoryMessage(category)}, nil
+}
+
+func (s *categoryServer) UpdateCategory(ctx context.Context, req *financev1.UpdateCategoryRequest) (*financev1.UpdateCategoryResponse, error) {
+	id, err := s.resolveId(ctx, services.PublicIdCategory, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.service.UpdateCategory(id, models.RequestUpdateCategory{Name: req.GetName()})
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.service.GetCategoryById(models.RequestGetCategoryById{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.UpdateCategoryResponse{Category: categoryMessage(category)}, nil
+}
+
+func (s *categoryServer) DeleteCategory(ctx context.Context, req *financev1.DeleteCategoryRequest) (*financev1.DeleteCategoryResponse, error) {
+	id, err := s.resolveId(ctx, services.PublicIdCategory, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.service.DeleteCategory(id)
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.DeleteCategoryResponse{}, nil
+}
+
+func categoryMessage(category models.Category) *financev1.Category {
+	return &financev1.Category{
+		Id:        int64(category.Id),
+		Uuid:      category.Uuid,
+		Name:      category.Name,
+		CreatedAt: category.CreatedAt.Format(timeLayout),
+		UpdatedAt: category.UpdatedAt.Format(timeLayout),
+	}
+}
@@ -0,0 +1,187 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"go-crud-api/helper"
+	"go-crud-api/middleware"
+	"go-crud-api/models"
+	financev1 "go-crud-api/proto/finance/v1"
+	"go-crud-api/services"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// DefaultPort is the port of the gRPC server when GRPC_PORT is not set.
+const DefaultPort = "9090"
+
+// publicMethods need no token.
+var publicMethods = map[string]bool{
+	financev1.UserService_SignUp_FullMethodName: true,
+	financev1.UserService_Login_FullMethodName:  true,
+}
+
+// adminMethods are the methods behind the admin-only REST routes.
+var adminMethods = map[string]bool{
+	financev1.UserService_ListUsers_FullMethodName:          true,
+	financev1.UserService_CreateUser_FullMethodName:         true,
+	financev1.UserService_UpdateUser_FullMethodName:         true,
+	financev1.UserService_DeleteUser_FullMethodName:         true,
+	financev1.CategoryService_CreateCategory_FullMethodName: true,
+	financev1.CategoryService_UpdateCategory_FullMethodName: true,
+	financev1.CategoryService_DeleteCategory_FullMethodName: true,
+}
+
+type currentUserKey struct{}
+
+// server holds what the gRPC services share. Each service embeds it next
+// to its Unimplemented server.
+type server struct {
+	service services.Service
+	auth    middleware.AuthMiddleware
+}
+
+// NewServer registers the user, category, transaction and balance services
+// on a gRPC server that authenticates calls with the JWT of Login.
+func NewServer(service services.Service, auth middleware.AuthMiddleware) *grpc.Server {
+	s := &server{service: service, auth: auth}
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	)
+	financev1.RegisterUserServiceServer(grpcServer, &userServer{server: s})
+	financev1.RegisterCategoryServiceServer(grpcServer, &categoryServer{server: s})
+	financev1.RegisterTransactionServiceServer(grpcServer, &transactionServer{server: s})
+	financev1.RegisterBalanceServiceServer(grpcServer, &balanceServer{server: s})
+	return grpcServer
+}
+
+// Listen opens the port of the gRPC server, DefaultPort when it is empty.
+// It is called before the HTTP server starts so a busy port fails at
+// startup.
+func Listen(port string) (net.Listener, error) {
+	if port == "" {
+		port = DefaultPort
+	}
+	return net.Listen("tcp", ":"+port)
+}
+
+func (s *server) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	return resp, statusError(err)
+}
+
+func (s *server) streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return statusError(handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx}))
+}
+
+// authenticate reads the "authorization: Bearer <token>" metadata, checks
+// the token like the REST routes do and puts the user in the context.
+func (s *server) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	user, err := s.auth.Authenticate(s.service, strings.TrimPrefix(values[0], "Bearer "))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if adminMethods[method] && user.Role != "admin" {
+		return nil, status.Error(codes.PermissionDenied, "forbidden: insufficient permissions")
+	}
+
+	return context.WithValue(ctx, currentUserKey{}, user), nil
+}
+
+// authenticatedStream passes the context with the user to stream handlers.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func currentUser(ctx context.Context) models.User {
+	user, _ := ctx.Value(currentUserKey{}).(models.User)
+	return user
+}
+
+// statusError gives service errors the status codes matching the status
+// codes of the REST handlers.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	message := err.Error()
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return status.Error(codes.NotFound, message)
+	case strings.HasPrefix(message, "invalid "):
+		return status.Error(codes.InvalidArgument, message)
+	case strings.HasPrefix(message, "unauthorized:"), strings.HasPrefix(message, "forbidden:"):
+		return status.Error(codes.PermissionDenied, message)
+	}
+	return status.Error(codes.Internal, message)
+}
+
+// resolveId takes an integer id or a uuid, like the :id of the REST routes.
+func (s *server) resolveId(ctx context.Context, entity string, raw string) (id int, err error) {
+	if services.IsUuid(raw) {
+		return s.service.ResolvePublicId(entity, raw, currentUser(ctx).Id)
+	}
+	id, err = strconv.Atoi(raw)
+	if err != nil || id < 1 {
+		err = errors.New("invalid id: use an integer id or a uuid")
+	}
+	return
+}
+
+// pagination turns a PageRequest into the cursor pagination of the list
+// endpoints.
+func pagination(page *financev1.PageRequest) models.RequestPagination {
+	limit := int(page.GetLimit())
+	if limit == 0 {
+		limit = helper.DefaultPageSize
+	}
+	return models.RequestPagination{
+		Limit:     strconv.Itoa(limit),
+		Sort:      page.GetSort(),
+		Cursor:    page.GetCursor(),
+		UseCursor: true,
+	}
+}
+
+func pageInfo(count int64, nextCursor string, prevCursor string) *financev1.PageInfo {
+	return &financev1.PageInfo{TotalCount: count, NextCursor: nextCursor, PrevCursor: prevCursor}
+}
@@ -0,0 +1,254 @@
+package grpcserver
+
+import (
+	"context"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	financev1 "go-crud-api/proto/finance/v1"
+	"go-crud-api/services"
+	"strconv"
+
+	"google.golang.org/grpc"
+)
+
+type transactionServer struct {
+	financev1.UnimplementedTransactionServiceServer
+	*server
+}
+
+func (s *transactionServer) CreateTransaction(ctx context.Context, req *financev1.CreateTransactionRequest) (*financev1.CreateTransactionResponse, error) {
+	categoryId, err := s.resolveId(ctx, services.PublicIdCategory, req.GetCategoryId())
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.service.CreateTransaction(currentUser(ctx).Id, models.RequestCreateTransaction{
+		Uuid:       req.GetUuid(),
+		Amount:     req.GetAmount(),
+		Type:       req.GetType(),
+		CategoryId: categoryId,
+		Note:       req.GetNote(),
+		Payee:      req.GetPayee(),
+		OccurredAt: req.GetOccurredAt(),
+		Tags:       req.GetTags(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.CreateTransactionResponse{Transaction: transactionMessage(transaction)}, nil
+}
+
+func (s *transactionServer) GetTransaction(ctx context.Context, req *financev1.GetTransactionRequest) (*financev1.GetTransactionResponse, error) {
+	id, err := s.resolveId(ctx, services.PublicIdTransaction, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.service.GetTransactionById(models.RequestGetTransactionById{Id: id}, currentUser(ctx).Id)
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.GetTransactionResponse{Transaction: transactionMessage(transaction)}, nil
+}
+
+func (s *transactionServer) ListTransactions(ctx context.Context, req *financev1.ListTransactionsRequest) (*financev1.ListTransactionsResponse, error) {
+	filter, err := s.transactionFilter(ctx, req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	filter.RequestPagination = pagination(req.GetPage())
+
+	transactions, err := s.service.GetTransactions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &financev1.ListTransactionsResponse{PageInfo: pageInfo(transactions.Count, transactions.NextCursor, transactions.PrevCursor)}
+	for _, transaction := range transactions.Data {
+		response.Transactions = append(response.Transactions, transactionMessage(transaction))
+	}
+	return response, nil
+}
+
+// StreamTransactions walks the listing with the largest page size and the
+// cursor of each page, sending the transactions as they are read.
+func (s *transactionServer) StreamTransactions(req *financev1.StreamTransactionsRequest, stream grpc.ServerStreamingServer[financev1.StreamTransactionsResponse]) error {
+	ctx := stream.Context()
+
+	filter, err := s.transactionFilter(ctx, req.GetFilter())
+	if err != nil {
+		return err
+	}
+	filter.RequestPagination = models.RequestPagination{
+		Limit:     strconv.Itoa(helper.MaxPageSize),
+		Sort:      req.GetSort(),
+		UseCursor: true,
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		transactions, err := s.service.GetTransactions(filter)
+		if err != nil {
+			return err
+		}
+		for _, transaction := range transactions.Data {
+			err = stream.Send(&financev1.StreamTransactionsResponse{Transaction: transactionMessage(transaction)})
+			if err != nil {
+				return err
+			}
+		}
+
+		if transactions.NextCursor == "" {
+			return nil
+		}
+		filter.Cursor = transactions.NextCursor
+	}
+}
+
+func (s *transactionServer) UpdateTransaction(ctx context.Context, req *financev1.UpdateTransactionRequest) (*financev1.UpdateTransactionResponse, error) {
+	id, err := s.resolveId(ctx, services.PublicIdTransaction, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	var patch models.RequestPatchTransaction
+	if req.Amount != nil {
+		patch.Amount = models.Nullable[float64]{Set: true, Value: req.GetAmount()}
+	}
+	if req.Type != nil {
+		patch.Type = models.Nullable[string]{Set: true, Value: req.GetType()}
+	}
+	if req.CategoryId != nil {
+		categoryId, err := s.resolveId(ctx, services.PublicIdCategory, req.GetCategoryId())
+		if err != nil {
+			return nil, err
+		}
+		patch.CategoryId = models.Nullable[int]{Set: true, Value: categoryId}
+	}
+	if req.Note != nil {
+		patch.Note = models.Nullable[string]{Set: true, Value: req.GetNote()}
+	}
+	if req.Payee != nil {
+		patch.Payee = models.Nullable[string]{Set: true, Value: req.GetPayee()}
+	}
+	if req.OccurredAt != nil {
+		patch.OccurredAt = models.Nullable[string]{Set: true, Value: req.GetOccurredAt()}
+	}
+	if req.Tags != nil {
+		patch.Tags = models.Nullable[[]string]{Set: true, Value: req.Tags.GetNames()}
+	}
+
+	transaction, err := s.service.PatchTransaction(id, currentUser(ctx).Id, patch)
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.UpdateTransactionResponse{Transaction: transactionMessage(transaction)}, nil
+}
+
+func (s *transactionServer) DeleteTransaction(ctx context.Context, req *financev1.DeleteTransactionRequest) (*financev1.DeleteTransactionResponse, error) {
+	id, err := s.resolveId(ctx, services.PublicIdTransaction, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.service.DeleteTransaction(id, currentUser(ctx).Id)
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.DeleteTransactionResponse{}, nil
+}
+
+// transactionFilter turns the filter into the request of GET /transactions.
+// Users only see their own transactions; admins see every user's unless
+// they pick one.
+func (s *transactionServer) transactionFilter(ctx context.Context, filter *financev1.TransactionFilter) (req models.RequestGetTransactions, err error) {
+	user := currentUser(ctx)
+	if filter == nil {
+		filter = &financev1.TransactionFilter{}
+	}
+
+	req.UserId = user.Id
+	if user.Role == "admin" {
+		req.UserId = 0
+		if filter.GetUserId() != "" {
+			req.UserId, err = s.resolveId(ctx, services.PublicIdUser, filter.GetUserId())
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	req.CategoryIds, err = s.categoryIds(ctx, filter.GetCategoryIds())
+	if err != nil {
+		return
+	}
+	req.ExcludeCategoryIds, err = s.categoryIds(ctx, filter.GetExcludeCategoryIds())
+	if err != nil {
+		return
+	}
+	if filter.MinAmount != nil {
+		req.MinAmount = strconv.FormatFloat(filter.GetMinAmount(), 'f', -1, 64)
+	}
+	if filter.MaxAmount != nil {
+		req.MaxAmount = strconv.FormatFloat(filter.GetMaxAmount(), 'f', -1, 64)
+	}
+	req.Types = filter.GetTypes()
+	req.ExcludeTypes = filter.GetExcludeTypes()
+	req.Search = filter.GetSearch()
+	req.Tags = filter.GetTags()
+	req.ExcludeTags = filter.GetExcludeTags()
+	req.Statuses = filter.GetStatuses()
+	req.StartDate = filter.GetStartDate()
+	req.EndDate = filter.GetEndDate()
+	return
+}
+
+// categoryIds resolves the uuids among the ids to the integer ids the
+// transaction filter takes.
+func (s *transactionServer) categoryIds(ctx context.Context, raws []string) (ids []string, err error) {
+	for _, raw := range raws {
+		id, errId := s.resolveId(ctx, services.PublicIdCategory, raw)
+		if errId != nil {
+			return nil, errId
+		}
+		ids = append(ids, strconv.Itoa(id))
+	}
+	return
+}
+
+func transactionMessage(transaction models.TransactionResponse) *financev1.Transaction {
+	message := &financev1.Transaction{
+		Id:   int64(transaction.Id),
+		Uuid: transaction.Uuid,
+		User: &financev1.TransactionUser{
+			Id:   int64(transaction.User.Id),
+			Uuid: transaction.User.Uuid,
+			Name: transaction.User.Name,
+		},
+		Amount: transaction.Amount,
+		Type:   transaction.Type,
+		Category: &financev1.TransactionCategory{
+			Id:   int64(transaction.Category.Id),
+			Uuid: transaction.Category.Uuid,
+			Name: transaction.Category.Name,
+		},
+		Note:          transaction.Note,
+		Payee:         transaction.Payee,
+		OccurredAt:    transaction.OccurredAt,
+		Status:        transaction.Status,
+		BankReference: transaction.BankReference,
+		CreatedAt:     transaction.CreatedAt,
+		UpdatedAt:     transaction.UpdatedAt,
+	}
+	for _, tag := range transaction.Tags {
+		message.Tags = append(message.Tags, &financev1.TransactionTag{Id: int64(tag.Id), Name: tag.Name})
+	}
+	if transaction.ReconciliationId != nil {
+		reconciliationId := int64(*transaction.ReconciliationId)
+		message.ReconciliationId = &reconciliationId
+	}
+	return message
+}
@@ -0,0 +1,116 @@
+package grpcserver
+
+import (
+	"context"
+	"go-crud-api/models"
+	financev1 "go-crud-api/proto/finance/v1"
+	"go-crud-api/services"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type userServer struct {
+	financev1.UnimplementedUserServiceServer
+	*server
+}
+
+func (s *userServer) SignUp(ctx context.Context, req *financev1.SignUpRequest) (*financev1.SignUpResponse, error) {
+	user, err := s.service.CreateUser(models.RequestSignUp{
+		Uuid:     req.GetUuid(),
+		Name:     req.GetName(),
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.SignUpResponse{User: userMessage(user)}, nil
+}
+
+func (s *userServer) Login(ctx context.Context, req *financev1.LoginRequest) (*financev1.LoginResponse, error) {
+	login, err := s.service.Login(models.RequestLogin{Username: req.GetUsername(), Password: req.GetPassword()})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return &financev1.LoginResponse{User: userMessage(login.User), Token: login.Token}, nil
+}
+
+func (s *userServer) GetMe(ctx context.Context, req *financev1.GetMeRequest) (*financev1.GetMeResponse, error) {
+	return &financev1.GetMeResponse{User: userMessage(currentUser(ctx))}, nil
+}
+
+func (s *userServer) ListUsers(ctx context.Context, req *financev1.ListUsersRequest) (*financev1.ListUsersResponse, error) {
+	users, err := s.service.GetAllUsers(models.RequestGetAllUsers{RequestPagination: pagination(req.GetPage())})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &financev1.ListUsersResponse{PageInfo: pageInfo(users.Count, users.NextCursor, users.PrevCursor)}
+	for _, user := range users.Data {
+		response.Users = append(response.Users, &financev1.User{
+			Id:       int64(user.Id),
+			Uuid:     user.Uuid,
+			Name:     user.Name,
+			Username: user.Username,
+			Role:     user.Role,
+		})
+	}
+	return response, nil
+}
+
+func (s *userServer) CreateUser(ctx context.Context, req *financev1.CreateUserRequest) (*financev1.CreateUserResponse, error) {
+	user, err := s.service.AdminCreateUser(models.RequestCreateUser{
+		Uuid:     req.GetUuid(),
+		Name:     req.GetName(),
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+		Role:     req.GetRole(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.CreateUserResponse{User: userMessage(user)}, nil
+}
+
+func (s *userServer) UpdateUser(ctx context.Context, req *financev1.UpdateUserRequest) (*financev1.UpdateUserResponse, error) {
+	id, err := s.resolveId(ctx, services.PublicIdUser, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.service.AdminUpdateUser(id, models.RequestUpdateUser{
+		Name:     req.GetName(),
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+		Role:     req.GetRole(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.UpdateUserResponse{User: userMessage(user)}, nil
+}
+
+func (s *userServer) DeleteUser(ctx context.Context, req *financev1.DeleteUserRequest) (*financev1.DeleteUserResponse, error) {
+	id, err := s.resolveId(ctx, services.PublicIdUser, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.service.AdminDeleteUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return &financev1.DeleteUserResponse{}, nil
+}
+
+// userMessage leaves out the password hash, like the REST user responses.
+func userMessage(user models.User) *financev1.User {
+	return &financev1.User{
+		Id:       int64(user.Id),
+		Uuid:     user.Uuid,
+		Name:     user.Name,
+		Username: user.Username,
+		Role:     user.Role,
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"fmt"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func (h *Handler) UploadAttachment(c *gin.Context) {
+	var id models.RequestGetTransactionById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		errorMessage := gin.H{"errors": "file is required"}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+	defer file.Close()
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	attachment, err := h.Service.UploadAttachment(id.Id, currentUser.Id, models.RequestUploadAttachment{
+		FileName: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		Content:  file,
+	})
+	if err != nil {
+		statusCode := attachmentErrorStatus(err, http.StatusBadRequest)
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, attachment)
+}
+
+func (h *Handler) GetAttachments(c *gin.Context) {
+	var id models.RequestGetTransactionById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	attachments, err := h.Service.GetAttachments(id.Id, currentUser.Id)
+	if err != nil {
+		statusCode := attachmentErrorStatus(err, http.StatusInternalServerError)
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, attachments)
+}
+
+// DownloadAttachment streams the file, or its thumbnail with ?thumbnail=true.
+func (h *Handler) DownloadAttachment(c *gin.Context) {
+	var request models.RequestGetAttachment
+
+	err := c.ShouldBindUri(&request)
+	if err == nil {
+		err = c.ShouldBindQuery(&request)
+	}
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	content, err := h.Service.GetAttachmentContent(request, currentUser.Id)
+	if err != nil {
+		statusCode := attachmentErrorStatus(err, http.StatusInternalServerError)
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+	defer content.Content.Close()
+
+	fileName := content.Attachment.FileName
+	if request.Thumbnail {
+		fileName = "thumb_" + fileName
+	}
+	headers := map[string]string{
+		"Content-Disposition": fmt.Sprintf("inline; filename=%q", fileName),
+	}
+	c.DataFromReader(http.StatusOK, content.Size, content.ContentType, content.Content, headers)
+}
+
+func (h *Handler) DeleteAttachment(c *gin.Context) {
+	var request models.RequestGetAttachment
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeleteAttachment(request.TransactionId, request.AttachmentId, currentUser.Id)
+	if err != nil {
+		statusCode := attachmentErrorStatus(err, http.StatusInternalServerError)
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "attachment deleted successfully"})
+}
+
+// attachmentErrorStatus maps ownership, lock and not-found errors, falling
+// back to the given status for everything else.
+func attachmentErrorStatus(err error, fallback int) int {
+	switch {
+	case err.Error() == "unauthorized: transaction does not belong to this user":
+		return http.StatusForbidden
+	case err.Error() == "transaction is reconciled and locked: unlock it before editing":
+		return http.StatusConflict
+	case err == gorm.ErrRecordNotFound, err.Error() == "attachment has no thumbnail":
+		return http.StatusNotFound
+	}
+	return fallback
+}
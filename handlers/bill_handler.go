@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func (h *Handler) CreateBill(c *gin.Context) {
+	var request models.RequestCreateBill
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	bill, err := h.Service.CreateBill(currentUser.Id, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, bill)
+}
+
+func (h *Handler) GetBills(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	bills, err := h.Service.GetBills(currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, bills)
+}
+
+func (h *Handler) GetBillById(c *gin.Context) {
+	var request models.RequestGetBillById
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	bill, err := h.Service.GetBillById(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: bill does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, bill)
+}
+
+func (h *Handler) UpdateBill(c *gin.Context) {
+	var request models.RequestUpdateBill
+	var id models.RequestGetBillById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	bill, err := h.Service.UpdateBill(id.Id, currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: bill does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, bill)
+}
+
+func (h *Handler) DeleteBill(c *gin.Context) {
+	var id models.RequestGetBillById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeleteBill(id.Id, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "unauthorized: bill does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "bill deleted successfully"})
+}
+
+func (h *Handler) GetBillOccurrences(c *gin.Context) {
+	var request models.RequestGetBillOccurrences
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	request.StartDate = c.Query("start_date")
+	request.EndDate = c.Query("end_date")
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	occurrences, err := h.Service.GetBillOccurrences(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: bill does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, occurrences)
+}
+
+func (h *Handler) GetUpcomingBills(c *gin.Context) {
+	// Number of days ahead to look, 30 by default
+	days := 0
+	if daysQuery := c.Query("days"); daysQuery != "" {
+		var err error
+		days, err = strconv.Atoi(daysQuery)
+		if err != nil || days < 1 {
+			errorMessage := gin.H{"errors": "days must be a positive number"}
+			response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+			c.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	occurrences, err := h.Service.GetUpcomingBills(currentUser.Id, days)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, occurrences)
+}
+
+func (h *Handler) CreateBillPayment(c *gin.Context) {
+	var request models.RequestCreateBillPayment
+	var id models.RequestGetBillById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	payment, err := h.Service.CreateBillPayment(id.Id, currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: bill does not belong to this user" || err.Error() == "unauthorized: transaction does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "bill occurrence is already paid" || err.Error() == "transaction is already linked to a bill payment" {
+			statusCode = http.StatusConflict
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, payment)
+}
+
+func (h *Handler) DeleteBillPayment(c *gin.Context) {
+	var request models.RequestGetBillPayment
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeleteBillPayment(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: bill does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "payment deleted successfully"})
+}
+
+// CreateCalendarToken returns the subscription URL of the user's bill
+// calendar. Calling it again revokes the previous URL.
+func (h *Handler) CreateCalendarToken(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	token, err := h.Service.CreateCalendarToken(currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	helper.ResponseSuccess(c, models.ResponseCalendarToken{
+		Token: token,
+		Url:   scheme + "://" + c.Request.Host + "/api/v1/calendar/" + token + "/bills.ics",
+	})
+}
+
+func (h *Handler) DeleteCalendarToken(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err := h.Service.DeleteCalendarToken(currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "calendar token revoked successfully"})
+}
+
+// GetBillCalendar serves the iCalendar feed. It is public: the token in the
+// URL is the credential, so calendar apps can subscribe without a JWT.
+func (h *Handler) GetBillCalendar(c *gin.Context) {
+	calendar, err := h.Service.GetBillCalendar(c.Param("token"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "calendar not found" {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="bills.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar)
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func (h *Handler) CreateDebt(c *gin.Context) {
+	var request models.RequestCreateDebt
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	debt, err := h.Service.CreateDebt(currentUser.Id, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, debt)
+}
+
+func (h *Handler) GetDebts(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	debts, err := h.Service.GetDebts(currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, debts)
+}
+
+func (h *Handler) GetDebtById(c *gin.Context) {
+	var request models.RequestGetDebtById
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	debt, err := h.Service.GetDebtById(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: debt does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, debt)
+}
+
+func (h *Handler) UpdateDebt(c *gin.Context) {
+	var request models.RequestUpdateDebt
+	var id models.RequestGetDebtById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	debt, err := h.Service.UpdateDebt(id.Id, currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: debt does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, debt)
+}
+
+func (h *Handler) DeleteDebt(c *gin.Context) {
+	var id models.RequestGetDebtById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeleteDebt(id.Id, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "unauthorized: debt does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "debt deleted successfully"})
+}
+
+func (h *Handler) GetDebtSchedule(c *gin.Context) {
+	var request models.RequestGetDebtById
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	schedule, err := h.Service.GetDebtSchedule(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: debt does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, schedule)
+}
+
+func (h *Handler) GetDebtSummary(c *gin.Context) {
+	var request models.RequestGetDebtById
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	// Number of unpaid installments to list, 3 by default
+	upcoming := 0
+	if upcomingQuery := c.Query("upcoming"); upcomingQuery != "" {
+		upcoming, err = strconv.Atoi(upcomingQuery)
+		if err != nil || upcoming < 1 {
+			errorMessage := gin.H{"errors": "upcoming must be a positive number"}
+			response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+			c.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	summary, err := h.Service.GetDebtSummary(request, currentUser.Id, upcoming)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: debt does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, summary)
+}
+
+func (h *Handler) CreateDebtPayment(c *gin.Context) {
+	var request models.RequestCreateDebtPayment
+	var id models.RequestGetDebtById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	payment, err := h.Service.CreateDebtPayment(id.Id, currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: debt does not belong to this user" || err.Error() == "unauthorized: transaction does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "transaction is already linked to a debt payment" {
+			statusCode = http.StatusConflict
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, payment)
+}
+
+func (h *Handler) GetDebtPayments(c *gin.Context) {
+	var id models.RequestGetDebtById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	payments, err := h.Service.GetDebtPayments(id.Id, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: debt does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, payments)
+}
+
+func (h *Handler) DeleteDebtPayment(c *gin.Context) {
+	var request models.RequestGetDebtPayment
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeleteDebtPayment(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: debt does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "payment deleted successfully"})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetDuplicates(c *gin.Context) {
+	var request models.RequestGetDuplicates
+
+	currentUser := c.MustGet("current_user").(models.User)
+	request.UserId = currentUser.Id
+	request.Window = c.Query("window")
+	request.PayeeSimilarity = c.Query("payee_similarity")
+	request.StartDate = c.Query("start_date")
+	request.EndDate = c.Query("end_date")
+
+	duplicates, err := h.Service.GetDuplicates(request)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			statusCode = http.StatusBadRequest
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, duplicates)
+}
+
+func (h *Handler) MergeDuplicate(c *gin.Context) {
+	var request models.RequestMergeDuplicate
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	transaction, err := h.Service.MergeDuplicate(currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: transaction does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, transaction)
+}
+
+func (h *Handler) DismissDuplicate(c *gin.Context) {
+	var request models.RequestDismissDuplicate
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	dismissal, err := h.Service.DismissDuplicate(currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: transaction does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, dismissal)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetEnvelopes(c *gin.Context) {
+	var request models.RequestGetEnvelopes
+
+	request.Date = c.Query("date")
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	envelopes, err := h.Service.GetEnvelopes(request, currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, envelopes)
+}
+
+func (h *Handler) SetEnvelopeCategory(c *gin.Context) {
+	var request models.RequestSetEnvelopeCategory
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	// The body is optional: rollover defaults to true
+	if c.Request.ContentLength != 0 {
+		err = c.ShouldBindJSON(&request)
+		if err != nil {
+			errorMessage := gin.H{"errors": err.Error()}
+			response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+			return
+		}
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	envelope, err := h.Service.SetEnvelopeCategory(request, currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, envelope)
+}
+
+func (h *Handler) DeleteEnvelopeCategory(c *gin.Context) {
+	var request models.RequestGetEnvelopeCategory
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeleteEnvelopeCategory(request, currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "envelope deleted successfully"})
+}
+
+func (h *Handler) AssignEnvelope(c *gin.Context) {
+	var request models.RequestAssignEnvelope
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	assignment, err := h.Service.AssignEnvelope(request, currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, assignment)
+}
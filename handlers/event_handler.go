@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func (h *Handler) GetDomainEvents(c *gin.Context) {
+	var request models.RequestGetDomainEvents
+
+	request.Type = c.Query("type")
+	request.Limit = c.Query("limit")
+	request.Page = c.Query("page")
+	request.Sort = c.Query("sort")
+	request.Cursor, request.UseCursor = c.GetQuery("cursor")
+
+	events, err := h.Service.GetDomainEvents(request)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			statusCode = http.StatusBadRequest
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, events)
+}
+
+func (h *Handler) GetEventSubscribers(c *gin.Context) {
+	subscribers, err := h.Service.GetEventSubscribers()
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, subscribers)
+}
+
+func (h *Handler) ReplayEvents(c *gin.Context) {
+	var request models.RequestReplayEvents
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	subscriber, err := h.Service.ReplayEvents(request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "events are being dispatched") {
+			statusCode = http.StatusConflict
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, subscriber)
+}
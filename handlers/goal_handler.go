@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) CreateGoal(c *gin.Context) {
+	var request models.RequestCreateGoal
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	goal, err := h.Service.CreateGoal(currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: tag does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, goal)
+}
+
+func (h *Handler) GetGoals(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	goals, err := h.Service.GetGoals(currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, goals)
+}
+
+func (h *Handler) GetGoalById(c *gin.Context) {
+	var request models.RequestGetGoalById
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	goal, err := h.Service.GetGoalById(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: goal does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, goal)
+}
+
+func (h *Handler) UpdateGoal(c *gin.Context) {
+	var request models.RequestUpdateGoal
+	var id models.RequestGetGoalById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	goal, err := h.Service.UpdateGoal(id.Id, currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: goal does not belong to this user" || err.Error() == "unauthorized: tag does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, goal)
+}
+
+func (h *Handler) DeleteGoal(c *gin.Context) {
+	var id models.RequestGetGoalById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeleteGoal(id.Id, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "unauthorized: goal does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "goal deleted successfully"})
+}
+
+func (h *Handler) GetGoalProgress(c *gin.Context) {
+	var request models.RequestGetGoalById
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	progress, err := h.Service.GetGoalProgress(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: goal does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, progress)
+}
+
+func (h *Handler) CreateGoalContribution(c *gin.Context) {
+	var request models.RequestCreateGoalContribution
+	var id models.RequestGetGoalById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	contribution, err := h.Service.CreateGoalContribution(id.Id, currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: goal does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, contribution)
+}
+
+func (h *Handler) GetGoalContributions(c *gin.Context) {
+	var id models.RequestGetGoalById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	contributions, err := h.Service.GetGoalContributions(id.Id, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: goal does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, contributions)
+}
+
+func (h *Handler) DeleteGoalContribution(c *gin.Context) {
+	var request models.RequestGetGoalContribution
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeleteGoalContribution(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: goal does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "contribution deleted successfully"})
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"go-crud-api/graphql"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQL runs a query or mutation as the current user. Its response is the
+// GraphQL {"data", "errors"} object, which GraphQL clients expect with a 200
+// even when there are errors.
+func (h *Handler) GraphQL(c *gin.Context) {
+	var request models.RequestGraphQL
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	c.JSON(http.StatusOK, graphql.Execute(c.Request.Context(), h.Service, currentUser, request))
+}
+
+// GraphQLSchema returns the schema in SDL for clients and code generators.
+func (h *Handler) GraphQLSchema(c *gin.Context) {
+	c.String(http.StatusOK, graphql.SDL())
+}
@@ -3,19 +3,22 @@ package handlers
 import (
 	"go-crud-api/helper"
 	"go-crud-api/models"
+	"go-crud-api/realtime"
 	"go-crud-api/services"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
 	Service services.Service
+	Stream  *realtime.Broker
 }
 
-func NewHandler(service services.Service) *Handler {
-	return &Handler{Service: service}
+func NewHandler(service services.Service, stream *realtime.Broker) *Handler {
+	return &Handler{Service: service, Stream: stream}
 }
 
 func (h *Handler) fetchUser(c *gin.Context) {
@@ -50,14 +53,19 @@ func (h *Handler) CreateUser(c *gin.Context) {
 	if err != nil {
 		errorMessage := gin.H{"errors": err.Error()}
 
-		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		statusCode := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "forbidden:") {
+			statusCode = http.StatusForbidden
+		}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
 
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		c.AbortWithStatusJSON(statusCode, response)
 		return
 	}
 
 	userResponse := models.UserResponse{
 		Id:       user.Id,
+		Uuid:     user.Uuid,
 		Name:     user.Name,
 		Username: user.Username,
 	}
@@ -71,6 +79,7 @@ func (h *Handler) GetUserById(c *gin.Context) {
 
 	userResponse := models.UserResponse{
 		Id:       currentUser.Id,
+		Uuid:     currentUser.Uuid,
 		Name:     currentUser.Name,
 		Username: currentUser.Username,
 	}
@@ -141,12 +150,18 @@ func (h *Handler) GetCategories(c *gin.Context) {
 	request.Name = c.Query("q")
 	request.Limit = c.Query("limit")
 	request.Page = c.Query("page")
+	request.Sort = c.Query("sort")
+	request.Cursor, request.UseCursor = c.GetQuery("cursor")
 
 	categories, err := h.Service.GetCategories(request)
 	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			statusCode = http.StatusBadRequest
+		}
 		errorMessage := gin.H{"errors": err.Error()}
-		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
 		return
 	}
 
@@ -206,6 +221,37 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 	helper.ResponseSuccess(c, gin.H{"message": "category updated successfully"})
 }
 
+func (h *Handler) PatchCategory(c *gin.Context) {
+	var request models.RequestPatchCategory
+	var id models.RequestGetCategoryById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	category, err := h.Service.PatchCategory(id.Id, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, category)
+}
+
 func (h *Handler) DeleteCategory(c *gin.Context) {
 	var id models.RequestGetCategoryById
 
@@ -276,18 +322,32 @@ func (h *Handler) GetTransactions(c *gin.Context) {
 		request.UserId = currentUser.Id
 	}
 
-	request.CategoryId = c.Query("category_id")
-	request.Type = c.Query("type")
+	request.CategoryIds = c.QueryArray("category_id")
+	request.ExcludeCategoryIds = c.QueryArray("exclude_category_id")
+	request.Types = c.QueryArray("type")
+	request.ExcludeTypes = c.QueryArray("exclude_type")
+	request.MinAmount = c.Query("min_amount")
+	request.MaxAmount = c.Query("max_amount")
+	request.Search = c.Query("q")
+	request.Tags = c.QueryArray("tag")
+	request.ExcludeTags = c.QueryArray("exclude_tag")
+	request.Statuses = c.QueryArray("status")
 	request.StartDate = c.Query("start_date")
 	request.EndDate = c.Query("end_date")
 	request.Limit = c.Query("limit")
 	request.Page = c.Query("page")
+	request.Sort = c.Query("sort")
+	request.Cursor, request.UseCursor = c.GetQuery("cursor")
 
 	transactions, err := h.Service.GetTransactions(request)
 	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			statusCode = http.StatusBadRequest
+		}
 		errorMessage := gin.H{"errors": err.Error()}
-		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
 		return
 	}
 	helper.ResponseSuccess(c, transactions)
@@ -352,6 +412,49 @@ func (h *Handler) UpdateTransaction(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "unauthorized: transaction does not belong to this user" {
 			statusCode = http.StatusForbidden
+		} else if err.Error() == "transaction is reconciled and locked: unlock it before editing" {
+			statusCode = http.StatusConflict
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, transaction)
+}
+
+func (h *Handler) PatchTransaction(c *gin.Context) {
+	var request models.RequestPatchTransaction
+	var id models.RequestGetTransactionById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	// Get userId from JWT token
+	currentUser := c.MustGet("current_user").(models.User)
+	userId := currentUser.Id
+
+	transaction, err := h.Service.PatchTransaction(id.Id, userId, request)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "unauthorized: transaction does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err.Error() == "transaction is reconciled and locked: unlock it before editing" {
+			statusCode = http.StatusConflict
 		}
 		errorMessage := gin.H{"errors": err.Error()}
 		response := helper.ResponseFormater(statusCode, "error", errorMessage)
@@ -382,6 +485,8 @@ func (h *Handler) DeleteTransaction(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "unauthorized: transaction does not belong to this user" {
 			statusCode = http.StatusForbidden
+		} else if err.Error() == "transaction is reconciled and locked: unlock it before editing" {
+			statusCode = http.StatusConflict
 		}
 		errorMessage := gin.H{"errors": err.Error()}
 		response := helper.ResponseFormater(statusCode, "error", errorMessage)
@@ -392,6 +497,39 @@ func (h *Handler) DeleteTransaction(c *gin.Context) {
 	helper.ResponseSuccess(c, gin.H{"message": "transaction deleted successfully"})
 }
 
+func (h *Handler) BulkTransactions(c *gin.Context) {
+	var request models.RequestBulkTransactions
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	// Get userId from JWT token
+	currentUser := c.MustGet("current_user").(models.User)
+	userId := currentUser.Id
+
+	result, err := h.Service.BulkTransactions(userId, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Atomic batches that were rolled back still report the per-item results
+	if !result.Committed {
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", result)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, result)
+}
+
 func (h *Handler) GetBalance(c *gin.Context) {
 	var request models.RequestGetBalance
 
@@ -416,12 +554,18 @@ func (h *Handler) GetAllUsers(c *gin.Context) {
 	var request models.RequestGetAllUsers
 	request.Limit = c.Query("limit")
 	request.Page = c.Query("page")
+	request.Sort = c.Query("sort")
+	request.Cursor, request.UseCursor = c.GetQuery("cursor")
 
 	users, err := h.Service.GetAllUsers(request)
 	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			statusCode = http.StatusBadRequest
+		}
 		errorMessage := gin.H{"errors": err.Error()}
-		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
 		return
 	}
 
@@ -491,6 +635,42 @@ func (h *Handler) AdminUpdateUser(c *gin.Context) {
 	})
 }
 
+func (h *Handler) AdminPatchUser(c *gin.Context) {
+	var id models.RequestDeleteUser
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	var request models.RequestPatchUser
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	user, err := h.Service.AdminPatchUser(id.Id, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{
+		"id":       user.Id,
+		"name":     user.Name,
+		"username": user.Username,
+		"role":     user.Role,
+	})
+}
+
 func (h *Handler) AdminDeleteUser(c *gin.Context) {
 	var id models.RequestDeleteUser
 
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportTransactions accepts a multipart upload with the statement in
+// "file" and the options as form fields.
+func (h *Handler) ImportTransactions(c *gin.Context) {
+	var request models.RequestImportTransactions
+
+	err := c.ShouldBind(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		errorMessage := gin.H{"errors": "file is required"}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+	defer file.Close()
+
+	request.FileName = fileHeader.Filename
+	request.Content = file
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	result, err := h.Service.ImportTransactions(currentUser.Id, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, result)
+}
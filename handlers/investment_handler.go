@@ -0,0 +1,488 @@
+package handlers
+
+import (
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func (h *Handler) CreatePortfolio(c *gin.Context) {
+	var request models.RequestCreatePortfolio
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	portfolio, err := h.Service.CreatePortfolio(currentUser.Id, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, portfolio)
+}
+
+func (h *Handler) GetPortfolios(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	portfolios, err := h.Service.GetPortfolios(currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, portfolios)
+}
+
+func (h *Handler) GetPortfolioById(c *gin.Context) {
+	var request models.RequestGetPortfolioById
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	portfolio, err := h.Service.GetPortfolioById(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: portfolio does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, portfolio)
+}
+
+func (h *Handler) UpdatePortfolio(c *gin.Context) {
+	var request models.RequestUpdatePortfolio
+	var id models.RequestGetPortfolioById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	portfolio, err := h.Service.UpdatePortfolio(id.Id, currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: portfolio does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, portfolio)
+}
+
+func (h *Handler) DeletePortfolio(c *gin.Context) {
+	var id models.RequestGetPortfolioById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeletePortfolio(id.Id, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "unauthorized: portfolio does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "portfolio deleted successfully"})
+}
+
+func (h *Handler) GetPortfolioValuation(c *gin.Context) {
+	var request models.RequestGetPortfolioValuation
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	request.Date = c.Query("date")
+	request.Method = c.Query("method")
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	valuation, err := h.Service.GetPortfolioValuation(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: portfolio does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, valuation)
+}
+
+func (h *Handler) CreateHolding(c *gin.Context) {
+	var request models.RequestCreateHolding
+	var id models.RequestGetPortfolioById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	holding, err := h.Service.CreateHolding(id.Id, currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: portfolio does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, holding)
+}
+
+func (h *Handler) GetHoldings(c *gin.Context) {
+	var id models.RequestGetPortfolioById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	holdings, err := h.Service.GetHoldings(id.Id, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: portfolio does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, holdings)
+}
+
+func (h *Handler) DeleteHolding(c *gin.Context) {
+	var request models.RequestGetHolding
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeleteHolding(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "unauthorized: portfolio does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "holding deleted successfully"})
+}
+
+func (h *Handler) CreateInvestmentActivity(c *gin.Context) {
+	var request models.RequestCreateInvestmentActivity
+	var holding models.RequestGetHolding
+
+	err := c.ShouldBindUri(&holding)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	activity, err := h.Service.CreateInvestmentActivity(holding, currentUser.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: portfolio does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, activity)
+}
+
+func (h *Handler) GetInvestmentActivities(c *gin.Context) {
+	var request models.RequestGetHolding
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	activities, err := h.Service.GetInvestmentActivities(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: portfolio does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, activities)
+}
+
+func (h *Handler) DeleteInvestmentActivity(c *gin.Context) {
+	var request models.RequestGetInvestmentActivity
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeleteInvestmentActivity(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err.Error() == "unauthorized: portfolio does not belong to this user" {
+			statusCode = http.StatusForbidden
+		} else if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "activity deleted successfully"})
+}
+
+func (h *Handler) CreatePrice(c *gin.Context) {
+	var request models.RequestCreatePrice
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	price, err := h.Service.CreatePrice(currentUser.Id, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, price)
+}
+
+func (h *Handler) GetPrices(c *gin.Context) {
+	var request models.RequestGetPrices
+
+	request.Symbol = c.Query("symbol")
+	request.StartDate = c.Query("start_date")
+	request.EndDate = c.Query("end_date")
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	prices, err := h.Service.GetPrices(currentUser.Id, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, prices)
+}
+
+func (h *Handler) DeletePrice(c *gin.Context) {
+	var request models.RequestGetPriceById
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err = h.Service.DeletePrice(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: price does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "price deleted successfully"})
+}
+
+// ImportPrices accepts a multipart upload with the price CSV in "file".
+func (h *Handler) ImportPrices(c *gin.Context) {
+	var request models.RequestImportPrices
+
+	err := c.ShouldBind(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		errorMessage := gin.H{"errors": "file is required"}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+	defer file.Close()
+
+	request.Content = file
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	result, err := h.Service.ImportPrices(currentUser.Id, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, result)
+}
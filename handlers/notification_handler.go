@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetNotifications(c *gin.Context) {
+	var request models.RequestGetNotifications
+
+	currentUser := c.MustGet("current_user").(models.User)
+	request.UserId = currentUser.Id
+	request.Unread = c.Query("unread")
+	request.Limit = c.Query("limit")
+	request.Page = c.Query("page")
+	request.Sort = c.Query("sort")
+	request.Cursor, request.UseCursor = c.GetQuery("cursor")
+
+	notifications, err := h.Service.GetNotifications(request)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			statusCode = http.StatusBadRequest
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, notifications)
+}
+
+func (h *Handler) MarkNotificationRead(c *gin.Context) {
+	var request models.RequestGetNotificationById
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	notification, err := h.Service.MarkNotificationRead(request, currentUser.Id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "unauthorized: notification does not belong to this user" {
+			statusCode = http.StatusForbidden
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, notification)
+}
+
+func (h *Handler) MarkAllNotificationsRead(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	err := h.Service.MarkAllNotificationsRead(currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "notifications marked as read"})
+}
+
+func (h *Handler) GetNotificationSettings(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	settings, err := h.Service.GetNotificationSettings(currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, settings)
+}
+
+func (h *Handler) UpdateNotificationSettings(c *gin.Context) {
+	var request models.RequestUpdateNotificationSettings
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("current_user").(models.User)
+
+	settings, err := h.Service.UpdateNotificationSettings(currentUser.Id, request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, settings)
+}
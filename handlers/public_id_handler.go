@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"go-crud-api/services"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PublicId lets the :id of the route be the entity's uuid. The uuid is
+// swapped for the integer id before the handler runs; integer ids pass
+// through unchanged.
+func (h *Handler) PublicId(entity string) gin.HandlerFunc {
+	return h.PublicIdParam(entity, "id")
+}
+
+// PublicIdParam is PublicId for a route parameter other than :id.
+func (h *Handler) PublicIdParam(entity string, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.Param(param)
+		if !services.IsUuid(raw) {
+			c.Next()
+			return
+		}
+
+		currentUser := c.MustGet("current_user").(models.User)
+		id, err := h.Service.ResolvePublicId(entity, raw, currentUser.Id)
+		if err != nil {
+			abortPublicId(c, err)
+			return
+		}
+
+		for i, p := range c.Params {
+			if p.Key == param {
+				c.Params[i].Value = strconv.Itoa(id)
+			}
+		}
+		c.Next()
+	}
+}
+
+// PublicIdFields lets id fields of the JSON body hold uuids. fields maps a
+// field name, matched in nested objects and lists too, to its entity; the
+// uuids in those fields, alone or in a list, are swapped for integer ids
+// before the handler binds the body.
+func (h *Handler) PublicIdFields(fields map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		// A malformed body is left for the handler to report
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var document interface{}
+		if decoder.Decode(&document) != nil {
+			c.Next()
+			return
+		}
+
+		currentUser := c.MustGet("current_user").(models.User)
+		err = h.swapPublicIds(document, fields, currentUser.Id)
+		if err != nil {
+			abortPublicId(c, err)
+			return
+		}
+
+		body, err = json.Marshal(document)
+		if err != nil {
+			c.Next()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Request.ContentLength = int64(len(body))
+		c.Next()
+	}
+}
+
+// swapPublicIds replaces the uuids of the fields in the objects within
+// value, in place.
+func (h *Handler) swapPublicIds(value interface{}, fields map[string]string, userId int) (err error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if entity, ok := fields[key]; ok {
+				v[key], err = h.resolvePublicIds(entity, item, userId)
+			} else {
+				err = h.swapPublicIds(item, fields, userId)
+			}
+			if err != nil {
+				return
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			err = h.swapPublicIds(item, fields, userId)
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (h *Handler) resolvePublicIds(entity string, value interface{}, userId int) (resolved interface{}, err error) {
+	switch v := value.(type) {
+	case string:
+		if !services.IsUuid(v) {
+			return v, nil
+		}
+		return h.Service.ResolvePublicId(entity, v, userId)
+	case []interface{}:
+		for i, item := range v {
+			v[i], err = h.resolvePublicIds(entity, item, userId)
+			if err != nil {
+				return
+			}
+		}
+	}
+	return value, nil
+}
+
+func abortPublicId(c *gin.Context, err error) {
+	statusCode := http.StatusInternalServerError
+	if err == gorm.ErrRecordNotFound {
+		statusCode = http.StatusNotFound
+	}
+	errorMessage := gin.H{"errors": err.Error()}
+	response := helper.ResponseFormater(statusCode, "error", errorMessage)
+	c.AbortWithStatusJSON(statusCode, response)
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"go-crud-api/realtime"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// streamHeartbeat keeps idle connections open through proxies
+	streamHeartbeat = 25 * time.Second
+	// streamWriteTimeout drops a client that stopped reading
+	streamWriteTimeout = 10 * time.Second
+	// streamRetry is the reconnect delay suggested to SSE clients, in ms
+	streamRetry = 3000
+)
+
+// CreateStreamTicket issues the single-use ticket that EventSource and
+// browser WebSocket clients pass as ?ticket= to open a stream.
+func (h *Handler) CreateStreamTicket(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	ticket, err := h.Service.CreateStreamTicket(currentUser.Id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, ticket)
+}
+
+// StreamEvents pushes the user's transaction, category and balance changes
+// as Server-Sent Events. A reconnecting client sends Last-Event-ID (or
+// last_event_id) to get the events it missed.
+func (h *Handler) StreamEvents(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	lastEventId, err := parseLastEventId(c)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	client := h.Stream.Subscribe(currentUser)
+	defer h.Stream.Unsubscribe(client)
+
+	var replay [][]realtime.Event
+	if lastEventId > 0 {
+		replay, err = h.Stream.Replay(client, lastEventId)
+		if err != nil {
+			errorMessage := gin.H{"errors": err.Error()}
+			response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	controller := http.NewResponseController(c.Writer)
+	write := func(send func(w io.Writer) error) bool {
+		controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if send(c.Writer) != nil {
+			return false
+		}
+		return controller.Flush() == nil
+	}
+
+	if !write(func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+		return err
+	}) {
+		return
+	}
+
+	sent := lastEventId
+	sendBatch := func(batch []realtime.Event) bool {
+		// Replayed events may come again live
+		if len(batch) == 0 || batch[0].Id <= sent && batch[0].Type != realtime.EventReset {
+			return true
+		}
+		sent = batch[0].Id
+		return write(func(w io.Writer) error {
+			for _, event := range batch {
+				_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, event.Data)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	for _, batch := range replay {
+		if !sendBatch(batch) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-client.Dropped():
+			// The client reconnects and catches up by replay
+			return
+		case batch := <-client.Events():
+			if !sendBatch(batch) {
+				return
+			}
+		case <-heartbeat.C:
+			if !write(func(w io.Writer) error {
+				_, err := io.WriteString(w, ": ping\n\n")
+				return err
+			}) {
+				return
+			}
+		}
+	}
+}
+
+// StreamEventsWebSocket is StreamEvents over a WebSocket. Each message is a
+// JSON object with id, event and data; a heartbeat is an event "ping"
+// without id.
+func (h *Handler) StreamEventsWebSocket(c *gin.Context) {
+	currentUser := c.MustGet("current_user").(models.User)
+
+	lastEventId, err := parseLastEventId(c)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		client := h.Stream.Subscribe(currentUser)
+		defer h.Stream.Unsubscribe(client)
+
+		// Incoming messages are ignored; reading notices the close
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var message string
+			for websocket.Message.Receive(conn, &message) == nil {
+			}
+		}()
+
+		send := func(v interface{}) bool {
+			conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			return websocket.JSON.Send(conn, v) == nil
+		}
+
+		sent := lastEventId
+		sendBatch := func(batch []realtime.Event) bool {
+			if len(batch) == 0 || batch[0].Id <= sent && batch[0].Type != realtime.EventReset {
+				return true
+			}
+			sent = batch[0].Id
+			for _, event := range batch {
+				if !send(event) {
+					return false
+				}
+			}
+			return true
+		}
+
+		if lastEventId > 0 {
+			replay, errReplay := h.Stream.Replay(client, lastEventId)
+			if errReplay != nil {
+				return
+			}
+			for _, batch := range replay {
+				if !sendBatch(batch) {
+					return
+				}
+			}
+		}
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-closed:
+				return
+			case <-client.Dropped():
+				return
+			case batch := <-client.Events():
+				if !sendBatch(batch) {
+					return
+				}
+			case <-heartbeat.C:
+				if !send(gin.H{"event": "ping"}) {
+					return
+				}
+			}
+		}
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func parseLastEventId(c *gin.Context) (id int64, err error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return
+	}
+
+	id, err = strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		err = errors.New("invalid Last-Event-ID: must be an event id")
+	}
+	return
+}
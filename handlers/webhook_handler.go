@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func (h *Handler) CreateWebhookSubscription(c *gin.Context) {
+	var request models.RequestCreateWebhookSubscription
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	subscription, err := h.Service.CreateWebhookSubscription(request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusBadRequest, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, subscription)
+}
+
+func (h *Handler) GetWebhookSubscriptions(c *gin.Context) {
+	subscriptions, err := h.Service.GetWebhookSubscriptions()
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusInternalServerError, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, subscriptions)
+}
+
+func (h *Handler) GetWebhookSubscriptionById(c *gin.Context) {
+	var request models.RequestGetWebhookSubscriptionById
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	subscription, err := h.Service.GetWebhookSubscriptionById(request)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, subscription)
+}
+
+func (h *Handler) UpdateWebhookSubscription(c *gin.Context) {
+	var request models.RequestUpdateWebhookSubscription
+	var id models.RequestGetWebhookSubscriptionById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	subscription, err := h.Service.UpdateWebhookSubscription(id.Id, request)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, subscription)
+}
+
+func (h *Handler) DeleteWebhookSubscription(c *gin.Context) {
+	var id models.RequestGetWebhookSubscriptionById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	err = h.Service.DeleteWebhookSubscription(id.Id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, gin.H{"message": "webhook subscription deleted successfully"})
+}
+
+func (h *Handler) GetWebhookDeliveries(c *gin.Context) {
+	var request models.RequestGetWebhookDeliveries
+	var id models.RequestGetWebhookSubscriptionById
+
+	err := c.ShouldBindUri(&id)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	request.SubscriptionId = id.Id
+	request.Status = c.Query("status")
+	request.Limit = c.Query("limit")
+	request.Page = c.Query("page")
+	request.Sort = c.Query("sort")
+	request.Cursor, request.UseCursor = c.GetQuery("cursor")
+
+	deliveries, err := h.Service.GetWebhookDeliveries(request)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "invalid ") {
+			statusCode = http.StatusBadRequest
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, deliveries)
+}
+
+func (h *Handler) RedeliverWebhook(c *gin.Context) {
+	var request models.RequestGetWebhookDelivery
+
+	err := c.ShouldBindUri(&request)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(http.StatusUnprocessableEntity, "error", errorMessage)
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	delivery, err := h.Service.RedeliverWebhook(request)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == gorm.ErrRecordNotFound {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "delivery is still pending" {
+			statusCode = http.StatusConflict
+		}
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.ResponseFormater(statusCode, "error", errorMessage)
+		c.AbortWithStatusJSON(statusCode, response)
+		return
+	}
+
+	helper.ResponseSuccess(c, delivery)
+}
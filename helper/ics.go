@@ -0,0 +1,85 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CalendarEvent is an all-day event of an iCalendar (RFC 5545) feed.
+type CalendarEvent struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	// AlarmDaysBefore adds a reminder that many days before the event when
+	// zero or more; a negative value means no reminder.
+	AlarmDaysBefore int
+}
+
+// BuildCalendar renders the events as an iCalendar document with CRLF line
+// endings and long lines folded as the RFC requires.
+func BuildCalendar(name string, events []CalendarEvent, now time.Time) []byte {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		b.WriteString(foldICSLine(fmt.Sprintf(format, args...)))
+		b.WriteString("\r\n")
+	}
+
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//go-crud-api//Bills//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:%s", escapeICSText(name))
+	for _, event := range events {
+		line("BEGIN:VEVENT")
+		line("UID:%s", event.UID)
+		line("DTSTAMP:%s", stamp)
+		line("DTSTART;VALUE=DATE:%s", event.Date.Format("20060102"))
+		line("DTEND;VALUE=DATE:%s", event.Date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:%s", escapeICSText(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION:%s", escapeICSText(event.Description))
+		}
+		line("TRANSP:TRANSPARENT")
+		if event.AlarmDaysBefore >= 0 {
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("DESCRIPTION:%s", escapeICSText(event.Summary))
+			line("TRIGGER:-P%dD", event.AlarmDaysBefore)
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	return []byte(b.String())
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICSText(text string) string {
+	return icsTextEscaper.Replace(text)
+}
+
+// foldICSLine splits a content line into chunks of at most 75 octets, each
+// continuation starting with a space, without cutting a UTF-8 character.
+func foldICSLine(content string) string {
+	const maxOctets = 75
+
+	var b strings.Builder
+	width := 0
+	for _, r := range content {
+		size := len(string(r))
+		if width+size > maxOctets {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -1,33 +1,185 @@
 package helper
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"go-crud-api/models"
+	"slices"
 	"strconv"
+	"strings"
 )
 
-func SetPaginationFromQuery(queryLimit string, queryPage string) models.QueryPagination {
-
-	limit := -1
-	page := -1
-	offset := -1
-
-	if queryLimit != "" {
-		limit, _ = strconv.Atoi(queryLimit)
-	}
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
 
-	if queryPage != "" {
-		page, _ = strconv.Atoi(queryPage)
+// SetPaginationFromQuery turns the limit and page query params into an
+// offset page. A missing or non-positive limit is DefaultPageSize and a
+// larger one is capped at MaxPageSize; a missing page is the first.
+func SetPaginationFromQuery(queryLimit string, queryPage string) models.QueryPagination {
+	limit, _ := strconv.Atoi(queryLimit)
+	if limit < 1 {
+		limit = DefaultPageSize
 	}
+	limit = min(limit, MaxPageSize)
 
-	if limit != -1 || page != -1 {
-		offset = (page - 1) * limit
+	page, _ := strconv.Atoi(queryPage)
+	if page < 1 {
+		page = 1
 	}
 
 	pagination := models.QueryPagination{
 		Limit:  limit,
-		Offset: offset,
+		Offset: (page - 1) * limit,
 		Page:   page,
 	}
 
 	return pagination
 }
+
+// ParsePagination validates the pagination query params and the sort against
+// the allowed columns. Both modes default to DefaultPageSize and cap the
+// limit at MaxPageSize.
+func ParsePagination(req models.RequestPagination, sortColumns []string, defaultSort string) (pagination models.QueryPagination, err error) {
+	if req.Limit != "" {
+		limit, errLimit := strconv.Atoi(req.Limit)
+		if errLimit != nil || limit < 1 {
+			err = errors.New("invalid limit: must be a positive number")
+			return
+		}
+	}
+
+	sort, err := ParseSort(req.Sort, sortColumns, defaultSort)
+	if err != nil {
+		return
+	}
+
+	if !req.UseCursor {
+		if req.Page != "" {
+			page, errPage := strconv.Atoi(req.Page)
+			if errPage != nil || page < 1 {
+				err = errors.New("invalid page: must be a positive number")
+				return
+			}
+		}
+
+		pagination = SetPaginationFromQuery(req.Limit, req.Page)
+		pagination.Sort = sort
+		return
+	}
+
+	pagination = models.QueryPagination{
+		Limit:     DefaultPageSize,
+		Offset:    -1,
+		Sort:      sort,
+		UseCursor: true,
+	}
+	if limit, _ := strconv.Atoi(req.Limit); limit > 0 {
+		pagination.Limit = min(limit, MaxPageSize)
+	}
+
+	if req.Cursor != "" {
+		cursor, errCursor := DecodeCursor(req.Cursor)
+		if errCursor != nil {
+			err = errCursor
+			return
+		}
+		if cursor.Sort != sort.Column || cursor.Desc != sort.Desc {
+			err = errors.New("invalid cursor: does not match sort")
+			return
+		}
+		pagination.Cursor = &cursor
+	}
+	return
+}
+
+// ParseSort parses "column" or "column:asc|desc", only accepting whitelisted
+// columns. An empty value falls back to defaultSort.
+func ParseSort(querySort string, sortColumns []string, defaultSort string) (sort models.QuerySort, err error) {
+	if querySort == "" {
+		querySort = defaultSort
+	}
+
+	column, direction, _ := strings.Cut(querySort, ":")
+	if !slices.Contains(sortColumns, column) {
+		err = errors.New("invalid sort: allowed fields are " + strings.Join(sortColumns, ", "))
+		return
+	}
+
+	switch strings.ToLower(direction) {
+	case "", "asc":
+	case "desc":
+		sort.Desc = true
+	default:
+		err = errors.New("invalid sort direction: must be asc or desc")
+		return
+	}
+
+	sort.Column = column
+	return
+}
+
+func EncodeCursor(cursor models.Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(encoded string) (cursor models.Cursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err == nil {
+		err = json.Unmarshal(data, &cursor)
+	}
+	if err != nil || cursor.Id < 1 {
+		err = errors.New("invalid cursor")
+	}
+	return
+}
+
+// TrimCursorPage drops the extra look-ahead row the repository fetches in
+// cursor mode and restores display order for backward pages.
+func TrimCursorPage[T any](items []T, pagination models.QueryPagination) (page []T, hasMore bool) {
+	if !pagination.UseCursor {
+		return items, false
+	}
+
+	if len(items) > pagination.Limit {
+		items = items[:pagination.Limit]
+		hasMore = true
+	}
+	if pagination.Cursor != nil && pagination.Cursor.Backward {
+		slices.Reverse(items)
+	}
+	return items, hasMore
+}
+
+// CursorLinks builds the next/prev cursors of a non-empty page from the sort
+// value and id of its first and last rows.
+func CursorLinks(pagination models.QueryPagination, hasMore bool, firstValue interface{}, firstId int, lastValue interface{}, lastId int) (next string, prev string) {
+	newCursor := func(value interface{}, id int, backward bool) string {
+		return EncodeCursor(models.Cursor{
+			Sort:     pagination.Sort.Column,
+			Desc:     pagination.Sort.Desc,
+			Value:    value,
+			Id:       id,
+			Backward: backward,
+		})
+	}
+
+	if pagination.Cursor != nil && pagination.Cursor.Backward {
+		next = newCursor(lastValue, lastId, false)
+		if hasMore {
+			prev = newCursor(firstValue, firstId, true)
+		}
+		return
+	}
+
+	if hasMore {
+		next = newCursor(lastValue, lastId, false)
+	}
+	if pagination.Cursor != nil {
+		prev = newCursor(firstValue, firstId, true)
+	}
+	return
+}
@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"go-crud-api/models"
+	"testing"
+)
+
+func TestParsePaginationLimits(t *testing.T) {
+	tests := []struct {
+		req        models.RequestPagination
+		wantLimit  int
+		wantOffset int
+	}{
+		{models.RequestPagination{}, DefaultPageSize, 0},
+		{models.RequestPagination{Page: "3"}, DefaultPageSize, 2 * DefaultPageSize},
+		{models.RequestPagination{Limit: "5", Page: "2"}, 5, 5},
+		{models.RequestPagination{Limit: "1000", Page: "2"}, MaxPageSize, MaxPageSize},
+		{models.RequestPagination{UseCursor: true}, DefaultPageSize, -1},
+		{models.RequestPagination{Limit: "1000", UseCursor: true}, MaxPageSize, -1},
+	}
+	for _, test := range tests {
+		pagination, err := ParsePagination(test.req, []string{"id"}, "id")
+		if err != nil {
+			t.Fatalf("%+v: %v", test.req, err)
+		}
+		if pagination.Limit != test.wantLimit || pagination.Offset != test.wantOffset {
+			t.Errorf("%+v: expected limit %d offset %d, got limit %d offset %d", test.req, test.wantLimit, test.wantOffset, pagination.Limit, pagination.Offset)
+		}
+	}
+}
+
+func TestParsePaginationRejectsInvalidLimit(t *testing.T) {
+	for _, limit := range []string{"0", "-1", "abc"} {
+		if _, err := ParsePagination(models.RequestPagination{Limit: limit}, []string{"id"}, "id"); err == nil {
+			t.Errorf("limit %q: expected an error", limit)
+		}
+	}
+}
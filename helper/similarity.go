@@ -0,0 +1,34 @@
+package helper
+
+import "strings"
+
+// StringSimilarity returns how alike two strings are from 0 to 1, based on
+// the Levenshtein distance of their trimmed lowercase forms.
+func StringSimilarity(a string, b string) float64 {
+	first := []rune(strings.ToLower(strings.TrimSpace(a)))
+	second := []rune(strings.ToLower(strings.TrimSpace(b)))
+
+	longest := max(len(first), len(second))
+	if longest == 0 {
+		return 1
+	}
+
+	previous := make([]int, len(second)+1)
+	current := make([]int, len(second)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(first); i++ {
+		current[0] = i
+		for j := 1; j <= len(second); j++ {
+			cost := 1
+			if first[i-1] == second[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return 1 - float64(previous[len(second)])/float64(longest)
+}
@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// MaxThumbnailPixels is the largest image, in pixels, that is decoded for a
+// thumbnail. The size is read from the header first, so a small file that
+// claims a huge canvas is refused before anything is allocated.
+const MaxThumbnailPixels = 40_000_000
+
+// GenerateThumbnail decodes a JPEG, PNG or GIF image and returns a JPEG that
+// fits inside maxSize x maxSize, keeping the aspect ratio. Images already
+// smaller than maxSize are only re-encoded.
+func GenerateThumbnail(content []byte, maxSize int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxThumbnailPixels {
+		return nil, fmt.Errorf("image is too large for a thumbnail: maximum is %d megapixels", MaxThumbnailPixels/1_000_000)
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			height = max(1, height*maxSize/width)
+			width = maxSize
+		} else {
+			width = max(1, width*maxSize/height)
+			height = maxSize
+		}
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(thumbnail, thumbnail.Bounds(), source, bounds, draw.Src, nil)
+
+	var buffer bytes.Buffer
+	err = jpeg.Encode(&buffer, thumbnail, &jpeg.Options{Quality: 80})
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
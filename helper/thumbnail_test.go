@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func encodePng(t *testing.T, width, height int) []byte {
+	t.Helper()
+	source := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			source.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, source); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestGenerateThumbnailScalesDown(t *testing.T) {
+	thumbnail, err := GenerateThumbnail(encodePng(t, 400, 200), 100)
+	if err != nil {
+		t.Fatalf("GenerateThumbnail: %v", err)
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(thumbnail))
+	if err != nil {
+		t.Fatalf("decode thumbnail: %v", err)
+	}
+	if size := decoded.Bounds().Size(); size.X != 100 || size.Y != 50 {
+		t.Fatalf("expected 100x50, got %dx%d", size.X, size.Y)
+	}
+}
+
+// TestGenerateThumbnailRefusesHugeCanvas rewrites the header of a small PNG
+// to claim 100000x100000 pixels, like a decompression bomb.
+func TestGenerateThumbnailRefusesHugeCanvas(t *testing.T) {
+	content := encodePng(t, 8, 8)
+	// The IHDR chunk follows the 8 byte signature: length, type, data, crc
+	ihdr := content[8+8 : 8+8+13]
+	binary.BigEndian.PutUint32(ihdr[0:4], 100000)
+	binary.BigEndian.PutUint32(ihdr[4:8], 100000)
+	binary.BigEndian.PutUint32(content[8+8+13:], crc32.ChecksumIEEE(content[8+4:8+8+13]))
+
+	_, err := GenerateThumbnail(content, 100)
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Fatalf("expected the image to be refused, got %v", err)
+	}
+}
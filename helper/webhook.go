@@ -0,0 +1,19 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// SignWebhook returns the X-Webhook-Signature header of a payload sent at
+// the unix timestamp: "t=<timestamp>,v1=<hex HMAC-SHA256>" over
+// "<timestamp>.<payload>". Receivers recompute it with the shared secret
+// and should reject old timestamps to stop replays.
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,111 @@
+package importer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// camt053Importer reads ISO 20022 CAMT.053 bank-to-customer statements.
+type camt053Importer struct{}
+
+type camtDocument struct {
+	Statements []struct {
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtEntry struct {
+	Amount              string `xml:"Amt"`
+	CreditDebit         string `xml:"CdtDbtInd"`
+	BookingDate         string `xml:"BookgDt>Dt"`
+	BookingDateTime     string `xml:"BookgDt>DtTm"`
+	ValueDate           string `xml:"ValDt>Dt"`
+	AccountServicerRef  string `xml:"AcctSvcrRef"`
+	AdditionalEntryInfo string `xml:"AddtlNtryInf"`
+	TransactionDetails  []struct {
+		AccountServicerRef string   `xml:"Refs>AcctSvcrRef"`
+		EndToEndId         string   `xml:"Refs>EndToEndId"`
+		CreditorName       string   `xml:"RltdPties>Cdtr>Nm"`
+		CreditorPartyName  string   `xml:"RltdPties>Cdtr>Pty>Nm"`
+		DebtorName         string   `xml:"RltdPties>Dbtr>Nm"`
+		DebtorPartyName    string   `xml:"RltdPties>Dbtr>Pty>Nm"`
+		Unstructured       []string `xml:"RmtInf>Ustrd"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+func (camt053Importer) Parse(content io.Reader) (transactions []Transaction, err error) {
+	var document camtDocument
+	err = xml.NewDecoder(content).Decode(&document)
+	if err != nil {
+		err = errors.New("invalid CAMT.053 XML: " + err.Error())
+		return
+	}
+
+	references := rowReferences{}
+	for _, statement := range document.Statements {
+		for i, entry := range statement.Entries {
+			var transaction Transaction
+
+			date := firstNonEmpty(entry.BookingDate, entry.BookingDateTime, entry.ValueDate)
+			if len(date) < 10 {
+				err = fmt.Errorf("entry %d: missing booking date", i+1)
+				return
+			}
+			transaction.Date, err = parseDate(date[:10], "2006-01-02")
+			if err != nil {
+				err = fmt.Errorf("entry %d: %w", i+1, err)
+				return
+			}
+
+			amount, errAmount := parseAmount(entry.Amount)
+			if errAmount != nil {
+				err = fmt.Errorf("entry %d: %w", i+1, errAmount)
+				return
+			}
+			transaction.Amount, transaction.Sign = signedAmount(amount)
+			if strings.EqualFold(entry.CreditDebit, "DBIT") {
+				transaction.Sign = -1
+			}
+
+			transaction.Memo = entry.AdditionalEntryInfo
+			reference := entry.AccountServicerRef
+			if len(entry.TransactionDetails) > 0 {
+				details := entry.TransactionDetails[0]
+				// The counterparty is the creditor of a debit and the debtor of a credit
+				if transaction.Sign < 0 {
+					transaction.Payee = firstNonEmpty(details.CreditorName, details.CreditorPartyName)
+				} else {
+					transaction.Payee = firstNonEmpty(details.DebtorName, details.DebtorPartyName)
+				}
+				if len(details.Unstructured) > 0 {
+					transaction.Memo = strings.Join(details.Unstructured, " ")
+				}
+				if reference == "" {
+					reference = details.AccountServicerRef
+				}
+				if reference == "" && details.EndToEndId != "NOTPROVIDED" {
+					reference = details.EndToEndId
+				}
+			}
+
+			transaction.ReferenceId = strings.TrimSpace(reference)
+			if transaction.ReferenceId == "" {
+				transaction.ReferenceId = references.next("camt", transaction)
+			}
+			transactions = append(transactions, transaction)
+		}
+	}
+	return
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
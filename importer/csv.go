@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// csvImporter reads a CSV with a header row. Recognized columns are date,
+// amount, payee, memo (or description/note) and reference (or id); the
+// delimiter may be a comma or a semicolon.
+type csvImporter struct{}
+
+var csvColumns = map[string]string{
+	"date":        "date",
+	"amount":      "amount",
+	"payee":       "payee",
+	"memo":        "memo",
+	"description": "memo",
+	"note":        "memo",
+	"reference":   "reference",
+	"id":          "reference",
+}
+
+func (csvImporter) Parse(content io.Reader) (transactions []Transaction, err error) {
+	reader := newCSVReader(content)
+
+	header, err := reader.Read()
+	if err != nil {
+		err = errors.New("csv file is empty")
+		return
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		if column, ok := csvColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[column] = i
+		}
+	}
+	if _, ok := columns["date"]; !ok {
+		err = errors.New("csv header needs a date column")
+		return
+	}
+	if _, ok := columns["amount"]; !ok {
+		err = errors.New("csv header needs an amount column")
+		return
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	references := rowReferences{}
+	for line := 2; ; line++ {
+		record, errRead := reader.Read()
+		if errRead == io.EOF {
+			break
+		}
+		if errRead != nil {
+			err = fmt.Errorf("line %d: %w", line, errRead)
+			return
+		}
+
+		var transaction Transaction
+		transaction.Date, err = parseDate(field(record, "date"), "2006-01-02", "02/01/2006", "02-01-2006", "2006/01/02")
+		if err != nil {
+			err = fmt.Errorf("line %d: %w", line, err)
+			return
+		}
+
+		amount, errAmount := parseAmount(field(record, "amount"))
+		if errAmount != nil {
+			err = fmt.Errorf("line %d: %w", line, errAmount)
+			return
+		}
+		transaction.Amount, transaction.Sign = signedAmount(amount)
+		transaction.Payee = field(record, "payee")
+		transaction.Memo = field(record, "memo")
+
+		transaction.ReferenceId = field(record, "reference")
+		if transaction.ReferenceId == "" {
+			transaction.ReferenceId = references.next("csv", transaction)
+		}
+		transactions = append(transactions, transaction)
+	}
+	return
+}
+
+// newCSVReader reads comma or semicolon separated content, picking the
+// delimiter that appears more often in the first line.
+func newCSVReader(content io.Reader) *csv.Reader {
+	buffered := bufio.NewReader(content)
+	firstLine, _ := buffered.Peek(4096)
+
+	reader := csv.NewReader(buffered)
+	if strings.Count(string(firstLine), ";") > strings.Count(string(firstLine), ",") {
+		reader.Comma = ';'
+	}
+	reader.TrimLeadingSpace = true
+	return reader
+}
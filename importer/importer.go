@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatOFX     = "ofx"
+	FormatQIF     = "qif"
+	FormatCAMT053 = "camt053"
+)
+
+// Transaction is a statement row normalized across formats. Amount is
+// always positive; Sign is +1 for money in and -1 for money out.
+type Transaction struct {
+	Date        time.Time
+	Amount      float64
+	Sign        int
+	Payee       string
+	Memo        string
+	ReferenceId string // bank reference, or a hash of the row when the format has none
+}
+
+// Importer parses one statement format.
+type Importer interface {
+	Parse(content io.Reader) (transactions []Transaction, err error)
+}
+
+// ForFormat returns the importer of a format name.
+func ForFormat(format string) (Importer, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return csvImporter{}, nil
+	case FormatOFX, "qfx":
+		return ofxImporter{}, nil
+	case FormatQIF:
+		return qifImporter{}, nil
+	case FormatCAMT053, "camt.053", "camt":
+		return camt053Importer{}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q: use csv, ofx, qif or camt053", format)
+}
+
+// DetectFormat guesses the format from the file extension.
+func DetectFormat(fileName string) (string, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".ofx", ".qfx":
+		return FormatOFX, nil
+	case ".qif":
+		return FormatQIF, nil
+	case ".xml", ".camt", ".053":
+		return FormatCAMT053, nil
+	}
+	return "", errors.New("cannot detect the file format: send format as csv, ofx, qif or camt053")
+}
+
+// signedAmount splits a signed amount into the positive amount and sign.
+func signedAmount(amount float64) (float64, int) {
+	if amount < 0 {
+		return -amount, -1
+	}
+	return amount, 1
+}
+
+// parseAmount accepts "1234.56", "-1,234.56" and "1.234,56" style numbers.
+func parseAmount(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	value = strings.ReplaceAll(value, " ", "")
+	if comma, dot := strings.LastIndex(value, ","), strings.LastIndex(value, "."); comma > dot {
+		// Decimal comma: drop thousand dots and use a dot for decimals
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.Replace(value, ",", ".", 1)
+	} else {
+		value = strings.ReplaceAll(value, ",", "")
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// parseDate tries the date layouts in order.
+func parseDate(value string, layouts ...string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		date, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// rowReferences builds stable reference ids for formats without a bank
+// reference: a hash of the row plus how often the same row appeared
+// before, so identical rows on one statement stay distinct.
+type rowReferences map[string]int
+
+func (r rowReferences) next(prefix string, transaction Transaction) string {
+	key := fmt.Sprintf("%s|%.2f|%d|%s|%s", transaction.Date.Format("2006-01-02"), transaction.Amount, transaction.Sign, transaction.Payee, transaction.Memo)
+	r[key]++
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d", key, r[key])))
+	return prefix + ":" + hex.EncodeToString(sum[:10])
+}
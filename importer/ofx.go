@@ -0,0 +1,73 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ofxImporter reads OFX 1.x (SGML, with unclosed value tags) and OFX 2.x
+// (XML) bank and credit card statements.
+type ofxImporter struct{}
+
+var (
+	ofxTransactionBlock = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	ofxField            = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+)
+
+func (ofxImporter) Parse(content io.Reader) (transactions []Transaction, err error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return
+	}
+
+	blocks := ofxTransactionBlock.FindAllStringSubmatch(string(data), -1)
+	if len(blocks) == 0 && !strings.Contains(strings.ToUpper(string(data)), "<OFX>") {
+		err = errors.New("not an OFX file")
+		return
+	}
+
+	references := rowReferences{}
+	for i, block := range blocks {
+		fields := map[string]string{}
+		for _, match := range ofxField.FindAllStringSubmatch(block[1], -1) {
+			fields[strings.ToUpper(match[1])] = html.UnescapeString(strings.TrimSpace(match[2]))
+		}
+
+		var transaction Transaction
+		// DTPOSTED is YYYYMMDD[HHMMSS[.XXX][[gmt offset:tz name]]]
+		posted := fields["DTPOSTED"]
+		if len(posted) < 8 {
+			err = fmt.Errorf("transaction %d: missing DTPOSTED", i+1)
+			return
+		}
+		transaction.Date, err = parseDate(posted[:8], "20060102")
+		if err != nil {
+			err = fmt.Errorf("transaction %d: %w", i+1, err)
+			return
+		}
+
+		amount, errAmount := parseAmount(fields["TRNAMT"])
+		if errAmount != nil {
+			err = fmt.Errorf("transaction %d: %w", i+1, errAmount)
+			return
+		}
+		transaction.Amount, transaction.Sign = signedAmount(amount)
+
+		transaction.Payee = fields["NAME"]
+		if transaction.Payee == "" {
+			transaction.Payee = fields["PAYEE"]
+		}
+		transaction.Memo = fields["MEMO"]
+
+		transaction.ReferenceId = fields["FITID"]
+		if transaction.ReferenceId == "" {
+			transaction.ReferenceId = references.next("ofx", transaction)
+		}
+		transactions = append(transactions, transaction)
+	}
+	return
+}
@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Price is one row of an instrument price history.
+type Price struct {
+	Symbol string
+	Date   time.Time
+	Price  float64
+}
+
+var priceColumns = map[string]string{
+	"symbol": "symbol",
+	"ticker": "symbol",
+	"code":   "symbol",
+	"date":   "date",
+	"price":  "price",
+	"close":  "price",
+	"nav":    "price",
+}
+
+// ParsePrices reads a price history CSV with a header row holding date,
+// price (or close/nav) and, unless every row is of defaultSymbol, symbol.
+func ParsePrices(content io.Reader, defaultSymbol string) (prices []Price, err error) {
+	reader := newCSVReader(content)
+
+	header, err := reader.Read()
+	if err != nil {
+		err = errors.New("csv file is empty")
+		return
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		if column, ok := priceColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[column] = i
+		}
+	}
+	for _, column := range []string{"date", "price"} {
+		if _, ok := columns[column]; !ok {
+			err = fmt.Errorf("csv header needs a %s column", column)
+			return
+		}
+	}
+	if _, ok := columns["symbol"]; !ok && defaultSymbol == "" {
+		err = errors.New("csv header needs a symbol column when no symbol is given")
+		return
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	for line := 2; ; line++ {
+		record, errRead := reader.Read()
+		if errRead == io.EOF {
+			break
+		}
+		if errRead != nil {
+			err = fmt.Errorf("line %d: %w", line, errRead)
+			return
+		}
+
+		price := Price{Symbol: field(record, "symbol")}
+		if price.Symbol == "" {
+			price.Symbol = defaultSymbol
+		}
+		price.Date, err = parseDate(field(record, "date"), "2006-01-02", "02/01/2006", "02-01-2006", "2006/01/02")
+		if err != nil {
+			err = fmt.Errorf("line %d: %w", line, err)
+			return
+		}
+		price.Price, err = parseAmount(field(record, "price"))
+		if err != nil {
+			err = fmt.Errorf("line %d: %w", line, err)
+			return
+		}
+		prices = append(prices, price)
+	}
+	return
+}
@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// qifImporter reads QIF bank and cash account exports. QIF has no bank
+// reference, so the check number is used when present and a row hash
+// otherwise.
+type qifImporter struct{}
+
+func (qifImporter) Parse(content io.Reader) (transactions []Transaction, err error) {
+	scanner := bufio.NewScanner(content)
+	references := rowReferences{}
+
+	var transaction Transaction
+	var number string
+	hasDate, hasAmount := false, false
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+
+		code, value := text[0], strings.TrimSpace(text[1:])
+		switch code {
+		case '!':
+			// Header such as !Type:Bank
+		case 'D':
+			// Quicken writes 12/31'25 for years after 1999
+			transaction.Date, err = parseDate(strings.ReplaceAll(value, "'", "/"),
+				"1/2/2006", "01/02/2006", "1/2/06", "2006-01-02", "02.01.2006")
+			if err != nil {
+				err = fmt.Errorf("line %d: %w", line, err)
+				return
+			}
+			hasDate = true
+		case 'T', 'U':
+			amount, errAmount := parseAmount(value)
+			if errAmount != nil {
+				err = fmt.Errorf("line %d: %w", line, errAmount)
+				return
+			}
+			transaction.Amount, transaction.Sign = signedAmount(amount)
+			hasAmount = true
+		case 'P':
+			transaction.Payee = value
+		case 'M':
+			transaction.Memo = value
+		case 'N':
+			number = value
+		case '^':
+			if !hasDate || !hasAmount {
+				err = fmt.Errorf("line %d: record without date or amount", line)
+				return
+			}
+			if number != "" {
+				transaction.ReferenceId = "qif:" + number
+			} else {
+				transaction.ReferenceId = references.next("qif", transaction)
+			}
+			transactions = append(transactions, transaction)
+
+			transaction, number = Transaction{}, ""
+			hasDate, hasAmount = false, false
+		}
+	}
+	err = scanner.Err()
+	return
+}
@@ -0,0 +1,38 @@
+// Package jobs runs periodic background work inside the API process.
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+// Every runs fn once at start and then every interval in its own goroutine.
+// Errors are logged and do not stop the job. A zero interval disables it.
+func Every(name string, interval time.Duration, fn func() error) {
+	if interval <= 0 {
+		log.Printf("job %s: disabled", name)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			run(name, fn)
+			<-ticker.C
+		}
+	}()
+}
+
+func run(name string, fn func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("job %s: panic: %v", name, r)
+		}
+	}()
+
+	if err := fn(); err != nil {
+		log.Printf("job %s: %v", name, err)
+	}
+}
@@ -3,10 +3,17 @@ package main
 import (
 	"go-crud-api/config"
 	_ "go-crud-api/docs"
+	"go-crud-api/events"
+	"go-crud-api/grpcserver"
 	"go-crud-api/handlers"
+	"go-crud-api/jobs"
 	"go-crud-api/middleware"
+	"go-crud-api/realtime"
 	"go-crud-api/repository"
 	"go-crud-api/services"
+	"log"
+	"os"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -22,8 +29,21 @@ func main() {
 
 	router := gin.Default()
 	repo := repository.NewRepository()
-	service := services.NewService(repo, config.DB)
-	handler := handlers.NewHandler(service)
+	service := services.NewService(repo, config.DB, config.NewStorage(), config.NewNotifyChannels())
+	stream := realtime.NewBroker(repo, config.DB)
+	handler := handlers.NewHandler(service, stream)
+
+	// In-process subscribers of the domain events in the outbox
+	dispatcher := events.NewDispatcher(repo, config.DB)
+	dispatcher.Subscribe("notifications", service.HandleNotificationEvent)
+	dispatcher.Subscribe("webhooks", service.HandleWebhookEvent)
+
+	// Background jobs
+	jobs.Every("event dispatcher", config.JobInterval("EVENT_DISPATCH_INTERVAL", time.Second), dispatcher.Dispatch)
+	jobs.Every("realtime stream", config.JobInterval("STREAM_POLL_INTERVAL", time.Second), stream.Poll)
+	jobs.Every("net worth snapshots", config.JobInterval("NET_WORTH_SNAPSHOT_INTERVAL", 24*time.Hour), service.CreateScheduledNetWorthSnapshots)
+	jobs.Every("bill reminders", config.JobInterval("BILL_REMINDER_INTERVAL", time.Hour), service.CreateBillReminders)
+	jobs.Every("webhook deliveries", config.JobInterval("WEBHOOK_DELIVERY_INTERVAL", 10*time.Second), service.ProcessWebhookDeliveries)
 	mid := middleware.NewAuthMiddleware()
 
 	auth := mid.ValidateToken(service)
@@ -34,6 +54,25 @@ func main() {
 	corsConfig.AllowHeaders = []string{"authorization", "content-type"}
 	router.Use(cors.New(corsConfig))
 
+	// Body fields that take a uuid in place of the integer id
+	categoryFields := handler.PublicIdFields(map[string]string{"category_id": services.PublicIdCategory})
+	transactionFields := handler.PublicIdFields(map[string]string{
+		"ids":            services.PublicIdTransaction,
+		"category_id":    services.PublicIdCategory,
+		"transaction_id": services.PublicIdTransaction,
+		"keep_id":        services.PublicIdTransaction,
+		"duplicate_id":   services.PublicIdTransaction,
+	})
+	goalFields := handler.PublicIdFields(map[string]string{
+		"category_id": services.PublicIdCategory,
+		"tag_id":      services.PublicIdTag,
+	})
+	ruleFields := handler.PublicIdFields(map[string]string{"set_category_id": services.PublicIdCategory})
+
+	// GraphQL over the same services and role rules
+	router.POST("/graphql", auth, handler.GraphQL)
+	router.GET("/graphql/schema", auth, handler.GraphQLSchema)
+
 	// Routes API
 	v1 := router.Group("/api/v1")
 	{
@@ -43,26 +82,182 @@ func main() {
 
 		// Category routes - admin can CRUD, users can only read
 		v1.GET("/categories", auth, handler.GetCategories)
-		v1.GET("/categories/:id", auth, handler.GetCategoryById)
+		v1.GET("/categories/:id", auth, handler.PublicId(services.PublicIdCategory), handler.GetCategoryById)
 		v1.POST("/categories", auth, adminOnly, handler.CreateCategory)
-		v1.PUT("/categories/:id", auth, adminOnly, handler.UpdateCategory)
-		v1.DELETE("/categories/:id", auth, adminOnly, handler.DeleteCategory)
+		v1.PUT("/categories/:id", auth, adminOnly, handler.PublicId(services.PublicIdCategory), handler.UpdateCategory)
+		v1.PATCH("/categories/:id", auth, adminOnly, handler.PublicId(services.PublicIdCategory), handler.PatchCategory)
+		v1.DELETE("/categories/:id", auth, adminOnly, handler.PublicId(services.PublicIdCategory), handler.DeleteCategory)
 
 		// Transaction routes - users can CRUD their own, admin can see all
-		v1.POST("/transactions", auth, handler.CreateTransaction)
+		v1.POST("/transactions", auth, transactionFields, handler.CreateTransaction)
+		v1.POST("/transactions/bulk", auth, transactionFields, handler.BulkTransactions)
+		v1.POST("/transactions/import", auth, handler.ImportTransactions)
 		v1.GET("/transactions", auth, handler.GetTransactions)
-		v1.GET("/transactions/:id", auth, handler.GetTransactionById)
-		v1.PUT("/transactions/:id", auth, handler.UpdateTransaction)
-		v1.DELETE("/transactions/:id", auth, handler.DeleteTransaction)
+		v1.GET("/transactions/duplicates", auth, handler.GetDuplicates)
+		v1.POST("/transactions/duplicates/merge", auth, transactionFields, handler.MergeDuplicate)
+		v1.POST("/transactions/duplicates/dismiss", auth, transactionFields, handler.DismissDuplicate)
+		v1.GET("/transactions/:id", auth, handler.PublicId(services.PublicIdTransaction), handler.GetTransactionById)
+		v1.PUT("/transactions/:id", auth, handler.PublicId(services.PublicIdTransaction), handler.UpdateTransaction)
+		v1.PATCH("/transactions/:id", auth, handler.PublicId(services.PublicIdTransaction), transactionFields, handler.PatchTransaction)
+		v1.DELETE("/transactions/:id", auth, handler.PublicId(services.PublicIdTransaction), handler.DeleteTransaction)
+		v1.PATCH("/transactions/:id/status", auth, handler.PublicId(services.PublicIdTransaction), handler.UpdateTransactionStatus)
+		v1.POST("/transactions/:id/unlock", auth, handler.PublicId(services.PublicIdTransaction), handler.UnlockTransaction)
+
+		// Receipt attachments of a transaction
+		v1.POST("/transactions/:id/attachments", auth, handler.PublicId(services.PublicIdTransaction), handler.UploadAttachment)
+		v1.GET("/transactions/:id/attachments", auth, handler.PublicId(services.PublicIdTransaction), handler.GetAttachments)
+		v1.GET("/transactions/:id/attachments/:attachmentId", auth, handler.PublicId(services.PublicIdTransaction), handler.DownloadAttachment)
+		v1.DELETE("/transactions/:id/attachments/:attachmentId", auth, handler.PublicId(services.PublicIdTransaction), handler.DeleteAttachment)
 
 		v1.GET("/balance", auth, handler.GetBalance)
 
+		// Delta sync for offline clients
+		v1.GET("/sync", auth, handler.PullSync)
+		v1.POST("/sync", auth, handler.PushSync)
+
+		// Real-time updates of transactions, categories and balance
+		v1.POST("/stream/ticket", auth, handler.CreateStreamTicket)
+		v1.GET("/stream", mid.ValidateStreamTicket(service, "ticket"), handler.StreamEvents)
+		v1.GET("/stream/ws", mid.ValidateStreamTicket(service, "ticket"), handler.StreamEventsWebSocket)
+
+		// Tag routes - each user manages their own tags
+		v1.GET("/tags", auth, handler.GetTags)
+		v1.GET("/tags/report", auth, handler.GetTagReport)
+		v1.GET("/tags/:id", auth, handler.PublicId(services.PublicIdTag), handler.GetTagById)
+		v1.POST("/tags", auth, handler.CreateTag)
+		v1.PUT("/tags/:id", auth, handler.PublicId(services.PublicIdTag), handler.UpdateTag)
+		v1.DELETE("/tags/:id", auth, handler.PublicId(services.PublicIdTag), handler.DeleteTag)
+
+		// Bank reconciliation sessions
+		v1.GET("/reconciliations", auth, handler.GetReconciliations)
+		v1.GET("/reconciliations/:id", auth, handler.GetReconciliationById)
+		v1.POST("/reconciliations", auth, handler.CreateReconciliation)
+		v1.POST("/reconciliations/:id/finalize", auth, handler.FinalizeReconciliation)
+		v1.DELETE("/reconciliations/:id", auth, handler.DeleteReconciliation)
+
+		// Savings goals
+		v1.GET("/goals", auth, handler.GetGoals)
+		v1.GET("/goals/:id", auth, handler.GetGoalById)
+		v1.POST("/goals", auth, goalFields, handler.CreateGoal)
+		v1.PUT("/goals/:id", auth, goalFields, handler.UpdateGoal)
+		v1.DELETE("/goals/:id", auth, handler.DeleteGoal)
+		v1.GET("/goals/:id/progress", auth, handler.GetGoalProgress)
+		v1.GET("/goals/:id/contributions", auth, handler.GetGoalContributions)
+		v1.POST("/goals/:id/contributions", auth, handler.CreateGoalContribution)
+		v1.DELETE("/goals/:id/contributions/:contributionId", auth, handler.DeleteGoalContribution)
+
+		// Debts and loans
+		v1.GET("/debts", auth, handler.GetDebts)
+		v1.GET("/debts/:id", auth, handler.GetDebtById)
+		v1.POST("/debts", auth, handler.CreateDebt)
+		v1.PUT("/debts/:id", auth, handler.UpdateDebt)
+		v1.DELETE("/debts/:id", auth, handler.DeleteDebt)
+		v1.GET("/debts/:id/schedule", auth, handler.GetDebtSchedule)
+		v1.GET("/debts/:id/summary", auth, handler.GetDebtSummary)
+		v1.GET("/debts/:id/payments", auth, handler.GetDebtPayments)
+		v1.POST("/debts/:id/payments", auth, transactionFields, handler.CreateDebtPayment)
+		v1.DELETE("/debts/:id/payments/:paymentId", auth, handler.DeleteDebtPayment)
+
+		// Bills and calendar feed
+		v1.GET("/bills", auth, handler.GetBills)
+		v1.GET("/bills/upcoming", auth, handler.GetUpcomingBills)
+		v1.GET("/bills/:id", auth, handler.GetBillById)
+		v1.POST("/bills", auth, categoryFields, handler.CreateBill)
+		v1.PUT("/bills/:id", auth, categoryFields, handler.UpdateBill)
+		v1.DELETE("/bills/:id", auth, handler.DeleteBill)
+		v1.GET("/bills/:id/occurrences", auth, handler.GetBillOccurrences)
+		v1.POST("/bills/:id/payments", auth, transactionFields, handler.CreateBillPayment)
+		v1.DELETE("/bills/:id/payments/:paymentId", auth, handler.DeleteBillPayment)
+		v1.POST("/calendar/token", auth, handler.CreateCalendarToken)
+		v1.DELETE("/calendar/token", auth, handler.DeleteCalendarToken)
+		v1.GET("/calendar/:token/bills.ics", handler.GetBillCalendar)
+
+		// Net worth
+		v1.GET("/assets", auth, handler.GetAssets)
+		v1.GET("/assets/:id", auth, handler.GetAssetById)
+		v1.POST("/assets", auth, handler.CreateAsset)
+		v1.PUT("/assets/:id", auth, handler.UpdateAsset)
+		v1.DELETE("/assets/:id", auth, handler.DeleteAsset)
+		v1.GET("/assets/:id/valuations", auth, handler.GetAssetValuations)
+		v1.POST("/assets/:id/valuations", auth, handler.CreateAssetValuation)
+		v1.DELETE("/assets/:id/valuations/:valuationId", auth, handler.DeleteAssetValuation)
+		v1.GET("/net-worth", auth, handler.GetNetWorth)
+		v1.GET("/net-worth/snapshots", auth, handler.GetNetWorthSeries)
+		v1.POST("/net-worth/snapshots", auth, handler.CreateNetWorthSnapshot)
+
+		// Investments
+		v1.GET("/portfolios", auth, handler.GetPortfolios)
+		v1.GET("/portfolios/:id", auth, handler.GetPortfolioById)
+		v1.POST("/portfolios", auth, handler.CreatePortfolio)
+		v1.PUT("/portfolios/:id", auth, handler.UpdatePortfolio)
+		v1.DELETE("/portfolios/:id", auth, handler.DeletePortfolio)
+		v1.GET("/portfolios/:id/valuation", auth, handler.GetPortfolioValuation)
+		v1.GET("/portfolios/:id/holdings", auth, handler.GetHoldings)
+		v1.POST("/portfolios/:id/holdings", auth, handler.CreateHolding)
+		v1.DELETE("/portfolios/:id/holdings/:holdingId", auth, handler.DeleteHolding)
+		v1.GET("/portfolios/:id/holdings/:holdingId/activities", auth, handler.GetInvestmentActivities)
+		v1.POST("/portfolios/:id/holdings/:holdingId/activities", auth, categoryFields, handler.CreateInvestmentActivity)
+		v1.DELETE("/portfolios/:id/holdings/:holdingId/activities/:activityId", auth, handler.DeleteInvestmentActivity)
+		v1.GET("/prices", auth, handler.GetPrices)
+		v1.POST("/prices", auth, handler.CreatePrice)
+		v1.POST("/prices/import", auth, handler.ImportPrices)
+		v1.DELETE("/prices/:id", auth, handler.DeletePrice)
+
+		// Envelope budgeting
+		v1.GET("/envelopes", auth, handler.GetEnvelopes)
+		v1.PUT("/envelopes/categories/:categoryId", auth, handler.PublicIdParam(services.PublicIdCategory, "categoryId"), handler.SetEnvelopeCategory)
+		v1.DELETE("/envelopes/categories/:categoryId", auth, handler.PublicIdParam(services.PublicIdCategory, "categoryId"), handler.DeleteEnvelopeCategory)
+		v1.PUT("/envelopes/assignments", auth, categoryFields, handler.AssignEnvelope)
+
+		// Notification inbox and alert settings
+		v1.GET("/notifications", auth, handler.GetNotifications)
+		v1.POST("/notifications/read", auth, handler.MarkAllNotificationsRead)
+		v1.GET("/notifications/settings", auth, handler.GetNotificationSettings)
+		v1.PUT("/notifications/settings", auth, handler.UpdateNotificationSettings)
+		v1.POST("/notifications/:id/read", auth, handler.MarkNotificationRead)
+
+		// Auto-categorization rules - each user manages their own rules
+		v1.GET("/rules", auth, handler.GetRules)
+		v1.POST("/rules/apply", auth, handler.ApplyRules)
+		v1.GET("/rules/:id", auth, handler.GetRuleById)
+		v1.POST("/rules", auth, ruleFields, handler.CreateRule)
+		v1.PUT("/rules/:id", auth, ruleFields, handler.UpdateRule)
+		v1.DELETE("/rules/:id", auth, handler.DeleteRule)
+
 		// Admin user management routes
 		v1.GET("/admin/users", auth, adminOnly, handler.GetAllUsers)
 		v1.POST("/admin/users", auth, adminOnly, handler.AdminCreateUser)
-		v1.PUT("/admin/users/:id", auth, adminOnly, handler.AdminUpdateUser)
-		v1.DELETE("/admin/users/:id", auth, adminOnly, handler.AdminDeleteUser)
+		v1.PUT("/admin/users/:id", auth, adminOnly, handler.PublicId(services.PublicIdUser), handler.AdminUpdateUser)
+		v1.PATCH("/admin/users/:id", auth, adminOnly, handler.PublicId(services.PublicIdUser), handler.AdminPatchUser)
+		v1.DELETE("/admin/users/:id", auth, adminOnly, handler.PublicId(services.PublicIdUser), handler.AdminDeleteUser)
+
+		// Outgoing webhooks - admin only
+		v1.GET("/admin/webhooks", auth, adminOnly, handler.GetWebhookSubscriptions)
+		v1.POST("/admin/webhooks", auth, adminOnly, handler.CreateWebhookSubscription)
+		v1.GET("/admin/webhooks/:id", auth, adminOnly, handler.GetWebhookSubscriptionById)
+		v1.PUT("/admin/webhooks/:id", auth, adminOnly, handler.UpdateWebhookSubscription)
+		v1.DELETE("/admin/webhooks/:id", auth, adminOnly, handler.DeleteWebhookSubscription)
+		v1.GET("/admin/webhooks/:id/deliveries", auth, adminOnly, handler.GetWebhookDeliveries)
+		v1.POST("/admin/webhooks/:id/deliveries/:deliveryId/redeliver", auth, adminOnly, handler.RedeliverWebhook)
+
+		// Domain event outbox - admin only
+		v1.GET("/admin/events", auth, adminOnly, handler.GetDomainEvents)
+		v1.GET("/admin/events/subscribers", auth, adminOnly, handler.GetEventSubscribers)
+		v1.POST("/admin/events/subscribers/:subscriber/replay", auth, adminOnly, handler.ReplayEvents)
+	}
+
+	// gRPC API on its own port, with the same services and token checks
+	grpcListener, err := grpcserver.Listen(os.Getenv("GRPC_PORT"))
+	if err != nil {
+		panic(err)
 	}
+	grpcServer := grpcserver.NewServer(service, mid)
+	go func() {
+		err := grpcServer.Serve(grpcListener)
+		if err != nil {
+			log.Printf("grpc: %v", err)
+		}
+	}()
 
 	router.Run()
 }
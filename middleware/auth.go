@@ -18,7 +18,9 @@ import (
 
 type AuthMiddleware interface {
 	ValidateToken(service services.Service) gin.HandlerFunc
+	ValidateStreamTicket(service services.Service, param string) gin.HandlerFunc
 	RequireRole(roles ...string) gin.HandlerFunc
+	Authenticate(service services.Service, tokenString string) (user models.User, err error)
 }
 
 type authMiddleware struct {
@@ -58,9 +60,9 @@ func (a authMiddleware) ValidateToken(service services.Service) gin.HandlerFunc
 			tokenString = arrayToken[1]
 		}
 
-		token, err := tokenValidator(tokenString)
+		user, err := a.Authenticate(service, tokenString)
 		if err != nil {
-			errorMessage := gin.H{"errors": errors.New("invalid token").Error()}
+			errorMessage := gin.H{"errors": err.Error()}
 
 			response := helper.ResponseFormater(http.StatusUnauthorized, "error", errorMessage)
 
@@ -68,30 +70,52 @@ func (a authMiddleware) ValidateToken(service services.Service) gin.HandlerFunc
 			return
 		}
 
-		claim, ok := token.Claims.(jwt.MapClaims)
-		if !ok || !token.Valid {
-			errorMessage := gin.H{"errors": errors.New("invalid token").Error()}
+		c.Set("current_user", user)
+	}
+}
 
-			response := helper.ResponseFormater(http.StatusUnauthorized, "error", errorMessage)
+// Authenticate returns the user of a JWT. It is shared by ValidateToken and
+// the gRPC interceptors, so both check tokens the same way.
+func (a authMiddleware) Authenticate(service services.Service, tokenString string) (user models.User, err error) {
+	token, err := tokenValidator(tokenString)
+	if err != nil {
+		return user, errors.New("invalid token")
+	}
 
-			c.AbortWithStatusJSON(http.StatusUnauthorized, response)
-			return
-		}
+	claim, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return user, errors.New("invalid token")
+	}
 
-		idString := fmt.Sprintf("%v", claim["id"])
-		id, _ := strconv.Atoi(idString)
+	idString := fmt.Sprintf("%v", claim["id"])
+	id, _ := strconv.Atoi(idString)
 
-		user, err := service.GetUserById(models.RequestGetUserById{Id: id})
-		if err != nil {
-			errorMessage := gin.H{"errors": errors.New("invalid token").Error()}
+	user, err = service.GetUserById(models.RequestGetUserById{Id: id})
+	if err != nil || user.Id == 0 {
+		return models.User{}, errors.New("invalid token")
+	}
 
-			response := helper.ResponseFormater(http.StatusUnauthorized, "error", errorMessage)
+	return user, nil
+}
 
-			c.AbortWithStatusJSON(http.StatusUnauthorized, response)
+// ValidateStreamTicket lets clients that cannot set headers, like
+// EventSource and browser WebSockets, authenticate with a single-use ticket
+// from POST /stream/ticket in the query string. Requests with an
+// Authorization header are checked like ValidateToken. The JWT itself is
+// never accepted in the query, where access logs would keep it.
+func (a authMiddleware) ValidateStreamTicket(service services.Service, param string) gin.HandlerFunc {
+	validateToken := a.ValidateToken(service)
+
+	return func(c *gin.Context) {
+		ticket := c.Query(param)
+		if ticket == "" || c.GetHeader("Authorization") != "" {
+			validateToken(c)
 			return
 		}
-		if user.Id == 0 {
-			errorMessage := gin.H{"errors": errors.New("invalid token").Error()}
+
+		user, err := service.RedeemStreamTicket(ticket)
+		if err != nil || user.Id == 0 {
+			errorMessage := gin.H{"errors": errors.New("invalid ticket").Error()}
 
 			response := helper.ResponseFormater(http.StatusUnauthorized, "error", errorMessage)
 
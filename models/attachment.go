@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type Attachment struct {
+	Id            int       `json:"id"`
+	TransactionId int       `json:"transaction_id" gorm:"index"`
+	UserId        int       `json:"user_id"`
+	FileName      string    `json:"file_name"`
+	ContentType   string    `json:"content_type"`
+	Size          int64     `json:"size"`
+	StorageKey    string    `json:"-"`
+	ThumbnailKey  string    `json:"-"` // empty when no thumbnail was generated
+	ThumbnailSize int64     `json:"-"`
+	HasThumbnail  bool      `json:"has_thumbnail"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
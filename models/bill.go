@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Bill is a recurring payment such as electricity or a credit card. Its due
+// dates repeat every Interval weeks, months or years from FirstDueDate, or
+// happen only once.
+type Bill struct {
+	Id               int        `json:"id"`
+	UserId           int        `json:"user_id" gorm:"index"`
+	Name             string     `json:"name"`
+	Amount           float64    `json:"amount"`
+	AmountType       string     `json:"amount_type"` // fixed or estimated
+	CategoryId       *int       `json:"category_id"`
+	Frequency        string     `json:"frequency"` // once, weekly, monthly or yearly
+	Interval         int        `json:"interval"`
+	FirstDueDate     time.Time  `json:"first_due_date" gorm:"type:date"`
+	EndDate          *time.Time `json:"end_date" gorm:"type:date"`
+	RemindDaysBefore int        `json:"remind_days_before"`
+	Note             string     `json:"note"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// BillPayment marks one occurrence of a bill as paid. Occurrences without a
+// payment are unpaid.
+type BillPayment struct {
+	Id            int       `json:"id"`
+	BillId        int       `json:"bill_id" gorm:"uniqueIndex:idx_bill_payment_due"`
+	UserId        int       `json:"user_id"`
+	DueDate       time.Time `json:"due_date" gorm:"type:date;uniqueIndex:idx_bill_payment_due"`
+	Amount        float64   `json:"amount"`
+	PaidAt        time.Time `json:"paid_at"`
+	TransactionId *int      `json:"transaction_id" gorm:"uniqueIndex"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// BillOccurrence is one due date of a bill with its payment status.
+type BillOccurrence struct {
+	BillId        int      `json:"bill_id"`
+	Name          string   `json:"name"`
+	DueDate       string   `json:"due_date"`
+	Amount        float64  `json:"amount"`
+	AmountType    string   `json:"amount_type"`
+	Status        string   `json:"status"` // paid, unpaid or overdue
+	PaymentId     *int     `json:"payment_id"`
+	PaidAmount    *float64 `json:"paid_amount"`
+	TransactionId *int     `json:"transaction_id"`
+}
+
+// CalendarFeed holds the secret of a user's subscribable calendar. Only the
+// SHA-256 hash of the token is stored.
+type CalendarFeed struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"user_id" gorm:"uniqueIndex"`
+	TokenHash string    `json:"-" gorm:"uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -4,6 +4,7 @@ import "time"
 
 type Category struct {
 	Id        int       `json:"id"`
+	Uuid      string    `json:"uuid" gorm:"type:uuid;uniqueIndex"`
 	Name      string    `json:"name"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
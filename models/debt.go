@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Debt is a loan the user took (borrowed) or gave (lent), repaid in
+// monthly installments. The first installment is due one month after
+// StartDate.
+type Debt struct {
+	Id                 int       `json:"id"`
+	UserId             int       `json:"user_id" gorm:"index"`
+	Name               string    `json:"name"`
+	Direction          string    `json:"direction"` // borrowed or lent
+	Counterparty       string    `json:"counterparty"`
+	Principal          float64   `json:"principal"`
+	AnnualInterestRate float64   `json:"annual_interest_rate"` // percent per year
+	InterestMethod     string    `json:"interest_method"`      // annuity or flat
+	TermMonths         int       `json:"term_months"`
+	StartDate          time.Time `json:"start_date" gorm:"type:date"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// DebtPayment is money paid toward a debt, optionally linked to the
+// transaction that moved it.
+type DebtPayment struct {
+	Id            int       `json:"id"`
+	DebtId        int       `json:"debt_id" gorm:"index"`
+	UserId        int       `json:"user_id"`
+	Amount        float64   `json:"amount"`
+	PaidAt        time.Time `json:"paid_at"`
+	TransactionId *int      `json:"transaction_id" gorm:"uniqueIndex"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Installment is one row of an amortization schedule. Paid and Status come
+// from applying the recorded payments to the installments in order.
+type Installment struct {
+	Number    int     `json:"number"`
+	DueDate   string  `json:"due_date"`
+	Payment   float64 `json:"payment"`
+	Principal float64 `json:"principal"`
+	Interest  float64 `json:"interest"`
+	Balance   float64 `json:"balance"` // principal left after this installment
+	Paid      float64 `json:"paid"`
+	Status    string  `json:"status"` // paid, partial, overdue or upcoming
+}
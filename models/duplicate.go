@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// DuplicateDismissal records that the user marked a duplicate candidate
+// pair as not being a duplicate. TransactionId is always the lower id.
+type DuplicateDismissal struct {
+	Id            int       `json:"id"`
+	UserId        int       `json:"user_id" gorm:"index"`
+	TransactionId int       `json:"transaction_id" gorm:"uniqueIndex:idx_duplicate_dismissal_pair"`
+	DuplicateId   int       `json:"duplicate_id" gorm:"uniqueIndex:idx_duplicate_dismissal_pair"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DuplicatePair is a candidate pair found by the duplicate detector.
+type DuplicatePair struct {
+	TransactionId int
+	DuplicateId   int
+}
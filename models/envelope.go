@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// EnvelopeCategory turns a category into an envelope for the user. With
+// Rollover the unspent amount of a cycle carries into the next one;
+// without it the leftover returns to the money available to assign.
+// Overspending always carries forward as a deficit.
+type EnvelopeCategory struct {
+	Id         int       `json:"id"`
+	UserId     int       `json:"user_id" gorm:"uniqueIndex:idx_envelope_user_category"`
+	CategoryId int       `json:"category_id" gorm:"uniqueIndex:idx_envelope_user_category"`
+	Category   Category  `json:"category" gorm:"foreignKey:CategoryId"`
+	Rollover   bool      `json:"rollover"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// EnvelopeAssignment is the money assigned to an envelope for the cycle
+// starting on CycleStart.
+type EnvelopeAssignment struct {
+	Id         int       `json:"id"`
+	UserId     int       `json:"user_id" gorm:"uniqueIndex:idx_envelope_assignment"`
+	CategoryId int       `json:"category_id" gorm:"uniqueIndex:idx_envelope_assignment"`
+	CycleStart time.Time `json:"cycle_start" gorm:"type:date;uniqueIndex:idx_envelope_assignment"`
+	Amount     float64   `json:"amount"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CategoryTotal is the sum of one transaction type in one category.
+type CategoryTotal struct {
+	CategoryId int
+	Type       string
+	Total      float64
+}
+
+// Envelope is the state of one envelope in a cycle. Available is what was
+// carried in plus what was assigned plus the activity (income minus
+// expense in the category).
+type Envelope struct {
+	CategoryId   int     `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Rollover     bool    `json:"rollover"`
+	CarriedIn    float64 `json:"carried_in"`
+	Assigned     float64 `json:"assigned"`
+	Activity     float64 `json:"activity"`
+	Available    float64 `json:"available"`
+}
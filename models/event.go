@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DomainEvent is a change recorded in the outbox within the same DB
+// transaction as the change itself. Sequence is assigned by the dispatcher
+// once the event is committed, so it follows commit order and is what
+// subscribers track; it is nil until then.
+type DomainEvent struct {
+	Id          int             `json:"id"`
+	Sequence    *int64          `json:"sequence" gorm:"uniqueIndex"`
+	Type        string          `json:"type" gorm:"index"`
+	UserId      int             `json:"user_id"` // owner of the changed data, 0 for shared data such as categories
+	AggregateId int             `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload" gorm:"type:text;serializer:json"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// EventOffset is the last sequence an in-process subscriber has handled.
+type EventOffset struct {
+	Id         int       `json:"id"`
+	Subscriber string    `json:"subscriber" gorm:"uniqueIndex"`
+	Sequence   int64     `json:"sequence"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// EventSubscriber is the progress of a subscriber; Lag is the number of
+// sequenced events it has not handled yet.
+type EventSubscriber struct {
+	Subscriber string    `json:"subscriber"`
+	Sequence   int64     `json:"sequence"`
+	Latest     int64     `json:"latest"`
+	Lag        int64     `json:"lag"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// StreamTicket lets a stream client that cannot send headers, like
+// EventSource, authenticate in the query string without exposing its JWT.
+// Only the hash of the ticket is stored; it is deleted when used.
+type StreamTicket struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"user_id" gorm:"index"`
+	TokenHash string    `json:"-" gorm:"uniqueIndex"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ResponseStreamTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
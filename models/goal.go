@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Goal is a savings target. Besides manual contributions, the user's
+// transactions in the linked category or with the linked tag count toward
+// it from StartDate on.
+type Goal struct {
+	Id           int        `json:"id"`
+	UserId       int        `json:"user_id" gorm:"index"`
+	Name         string     `json:"name"`
+	TargetAmount float64    `json:"target_amount"`
+	TargetDate   *time.Time `json:"target_date" gorm:"type:date"`
+	StartDate    time.Time  `json:"start_date" gorm:"type:date"`
+	CategoryId   *int       `json:"category_id"`
+	TagId        *int       `json:"tag_id"`
+	Account      string     `json:"account"` // label only, transactions have no account
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// GoalContribution is money put toward (or, when negative, taken out of) a
+// goal by hand.
+type GoalContribution struct {
+	Id            int       `json:"id"`
+	GoalId        int       `json:"goal_id" gorm:"index"`
+	UserId        int       `json:"user_id"`
+	Amount        float64   `json:"amount"`
+	Note          string    `json:"note"`
+	ContributedAt time.Time `json:"contributed_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Portfolio groups holdings. CostMethod decides how sells are matched to
+// buys when gains are calculated.
+type Portfolio struct {
+	Id         int       `json:"id"`
+	UserId     int       `json:"user_id" gorm:"index"`
+	Name       string    `json:"name"`
+	CostMethod string    `json:"cost_method"` // fifo or average
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Holding is an instrument held in a portfolio. Its units and cost basis
+// follow from its activities.
+type Holding struct {
+	Id          int       `json:"id"`
+	PortfolioId int       `json:"portfolio_id" gorm:"uniqueIndex:idx_holding_portfolio_symbol"`
+	UserId      int       `json:"user_id"`
+	Symbol      string    `json:"symbol" gorm:"uniqueIndex:idx_holding_portfolio_symbol"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"` // mutual_fund, stock, gold, bond, crypto or other
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// InvestmentActivity is a buy, sell or dividend of a holding. Amount is
+// the cash moved: units × price plus the fee for a buy, minus the fee for a
+// sell. TransactionId is the transaction created for it, if any.
+type InvestmentActivity struct {
+	Id            int       `json:"id"`
+	HoldingId     int       `json:"holding_id" gorm:"index"`
+	UserId        int       `json:"user_id"`
+	Type          string    `json:"type"` // buy, sell or dividend
+	Date          time.Time `json:"date" gorm:"type:date"`
+	Units         float64   `json:"units"`
+	Price         float64   `json:"price"`
+	Fee           float64   `json:"fee"`
+	Amount        float64   `json:"amount"`
+	TransactionId *int      `json:"transaction_id"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// InstrumentPrice is the price of one unit of an instrument on a day,
+// entered by the user or loaded from CSV.
+type InstrumentPrice struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"user_id" gorm:"uniqueIndex:idx_price_user_symbol_date"`
+	Symbol    string    `json:"symbol" gorm:"uniqueIndex:idx_price_user_symbol_date"`
+	Date      time.Time `json:"date" gorm:"type:date;uniqueIndex:idx_price_user_symbol_date"`
+	Price     float64   `json:"price"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HoldingValuation is a holding valued on a date.
+type HoldingValuation struct {
+	Holding
+	Units                 float64  `json:"units"`
+	CostBasis             float64  `json:"cost_basis"`
+	AverageCost           float64  `json:"average_cost"`
+	Price                 *float64 `json:"price"`
+	PriceDate             *string  `json:"price_date"`
+	PriceSource           string   `json:"price_source"` // price_history, last_trade or none
+	MarketValue           float64  `json:"market_value"`
+	UnrealizedGain        float64  `json:"unrealized_gain"`
+	UnrealizedGainPercent float64  `json:"unrealized_gain_percent"`
+	RealizedGain          float64  `json:"realized_gain"`
+	Dividends             float64  `json:"dividends"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Asset is something the user owns (a house, a vehicle) or owes outside the
+// debt schedules (a credit card balance), valued manually over time.
+type Asset struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"user_id" gorm:"index"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"` // asset or liability
+	Type      string    `json:"type"` // free label such as property or vehicle
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AssetValuation is the value of an asset from ValuedAt until the next
+// valuation.
+type AssetValuation struct {
+	Id        int       `json:"id"`
+	AssetId   int       `json:"asset_id" gorm:"index"`
+	UserId    int       `json:"user_id"`
+	Value     float64   `json:"value"`
+	ValuedAt  time.Time `json:"valued_at" gorm:"type:date"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NetWorthItem is one line of a net worth breakdown.
+type NetWorthItem struct {
+	Source string  `json:"source"` // cash, asset or debt
+	Id     int     `json:"id,omitempty"`
+	Name   string  `json:"name"`
+	Kind   string  `json:"kind"` // asset or liability
+	Value  float64 `json:"value"`
+}
+
+// NetWorth is what the user owns minus what they owe at one moment.
+type NetWorth struct {
+	CashBalance       float64        `json:"cash_balance"`
+	ManualAssets      float64        `json:"manual_assets"`
+	DebtsReceivable   float64        `json:"debts_receivable"`
+	TotalAssets       float64        `json:"total_assets"`
+	ManualLiabilities float64        `json:"manual_liabilities"`
+	DebtsPayable      float64        `json:"debts_payable"`
+	TotalLiabilities  float64        `json:"total_liabilities"`
+	NetWorth          float64        `json:"net_worth"`
+	Items             []NetWorthItem `json:"items" gorm:"serializer:json"`
+}
+
+// NetWorthSnapshot stores the net worth of a user for one day; a later
+// snapshot on the same day replaces it.
+type NetWorthSnapshot struct {
+	Id           int       `json:"id"`
+	UserId       int       `json:"user_id" gorm:"uniqueIndex:idx_net_worth_user_date"`
+	SnapshotDate time.Time `json:"snapshot_date" gorm:"type:date;uniqueIndex:idx_net_worth_user_date"`
+	Source       string    `json:"source"` // manual or scheduled
+	NetWorth     `gorm:"embedded"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NetWorthPoint is one point of the net worth time series.
+type NetWorthPoint struct {
+	Date             string  `json:"date"`
+	TotalAssets      float64 `json:"total_assets"`
+	TotalLiabilities float64 `json:"total_liabilities"`
+	NetWorth         float64 `json:"net_worth"`
+	Change           float64 `json:"change"` // against the previous point
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Notification is an entry in a user's in-app inbox. Key identifies the
+// event that raised it so the same event is never notified twice.
+type Notification struct {
+	Id        int                    `json:"id"`
+	UserId    int                    `json:"user_id" gorm:"uniqueIndex:idx_notification_user_key"`
+	Type      string                 `json:"type"` // budget_threshold, large_transaction or bill_due
+	Title     string                 `json:"title"`
+	Message   string                 `json:"message"`
+	Data      map[string]interface{} `json:"data" gorm:"serializer:json"`
+	Key       string                 `json:"-" gorm:"uniqueIndex:idx_notification_user_key"`
+	ReadAt    *time.Time             `json:"read_at"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// NotificationSettings are the user's alert rules and delivery addresses.
+// Users without a row get the defaults: budget alerts at 80% and 100%,
+// bill reminders on and no large transaction alert. Notifications are
+// always stored in the inbox; Email and WebhookUrl also deliver them there
+// when set.
+type NotificationSettings struct {
+	Id                     int       `json:"id"`
+	UserId                 int       `json:"user_id" gorm:"uniqueIndex"`
+	BudgetThresholds       []int     `json:"budget_thresholds" gorm:"serializer:json"` // percent of the envelope's funds
+	LargeTransactionAmount float64   `json:"large_transaction_amount"`                 // 0 turns the alert off
+	BillReminders          bool      `json:"bill_reminders"`
+	Email                  string    `json:"email"`
+	WebhookUrl             string    `json:"webhook_url"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
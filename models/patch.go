@@ -0,0 +1,20 @@
+package models
+
+import "encoding/json"
+
+// Nullable tracks a JSON Merge Patch (RFC 7396) field: absent fields keep
+// Set false, an explicit null sets Null, anything else is decoded into Value.
+type Nullable[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Reconciliation is a session matching the user's cleared transactions
+// against a bank statement. Finalizing it marks those transactions as
+// reconciled, which locks them from edits.
+type Reconciliation struct {
+	Id               int        `json:"id"`
+	UserId           int        `json:"user_id" gorm:"index"`
+	Account          string     `json:"account"`
+	StatementEndDate time.Time  `json:"statement_end_date" gorm:"type:date"`
+	StatementBalance float64    `json:"statement_balance"`
+	Status           string     `json:"status"` // open or finalized
+	FinalizedAt      *time.Time `json:"finalized_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
@@ -1,14 +1,21 @@
 package models
 
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
 type RequestGetUserById struct {
 	Id int `json:"id"`
 }
 
 type RequestSignUp struct {
+	Uuid     string `json:"uuid"` // optional client-generated public id
 	Name     string `json:"name"`
 	Username string `json:"username"`
 	Password string `json:"password"`
-	Role     string `json:"role"` // "admin" or "user", default "user"
+	Role     string `json:"role"` // "admin" (first admin only) or "user", default "user"
 }
 
 type RequestLogin struct {
@@ -17,6 +24,7 @@ type RequestLogin struct {
 }
 
 type RequestCreateCategory struct {
+	Uuid string `json:"uuid"` // optional client-generated public id
 	Name string `json:"name"`
 }
 
@@ -33,18 +41,40 @@ type RequestUpdateCategory struct {
 	Name string `json:"name"`
 }
 
+type RequestPatchCategory struct {
+	Name Nullable[string] `json:"name"`
+}
+
 type RequestCreateTransaction struct {
-	Amount     float64 `json:"amount"`
-	Type       string  `json:"type"`
-	CategoryId int     `json:"category_id"`
+	Amount     float64  `json:"amount"`
+	Type       string   `json:"type"`
+	CategoryId int      `json:"category_id"`
+	Note       string   `json:"note"`
+	Payee      string   `json:"payee"`
+	OccurredAt string   `json:"occurred_at"` // "2006-01-02" or RFC 3339, defaults to now
+	Tags       []string `json:"tags"`        // tag names, created for the user when missing
+	// BankReference is only set by statement imports
+	BankReference string `json:"-"`
+	// DefaultCategoryId is the import's category, used when no rule sets one
+	DefaultCategoryId int `json:"-"`
+	// Uuid is the optional client-generated public id
+	Uuid string `json:"uuid"`
 }
 
 type RequestGetTransactions struct {
-	UserId     int    `json:"user_id"`
-	CategoryId string `json:"category_id"`
-	Type       string `json:"type"`
-	StartDate  string `json:"start_date"`
-	EndDate    string `json:"end_date"`
+	UserId             int      `json:"user_id"`
+	CategoryIds        []string `json:"category_id"`
+	ExcludeCategoryIds []string `json:"exclude_category_id"`
+	Types              []string `json:"type"`
+	ExcludeTypes       []string `json:"exclude_type"`
+	MinAmount          string   `json:"min_amount"`
+	MaxAmount          string   `json:"max_amount"`
+	Search             string   `json:"q"`
+	Tags               []string `json:"tag"`
+	ExcludeTags        []string `json:"exclude_tag"`
+	Statuses           []string `json:"status"`
+	StartDate          string   `json:"start_date"` // "2006-01-02" (whole day) or RFC 3339 datetime
+	EndDate            string   `json:"end_date"`
 	RequestPagination
 }
 
@@ -53,20 +83,94 @@ type RequestGetTransactionById struct {
 }
 
 type RequestUpdateTransaction struct {
-	Amount     float64 `json:"amount"`
-	Type       string  `json:"type"`
-	CategoryId string  `json:"category_id"`
+	Amount     float64  `json:"amount"`
+	Type       string   `json:"type"`
+	CategoryId string   `json:"category_id"`
+	Note       string   `json:"note"`
+	Payee      string   `json:"payee"`
+	OccurredAt string   `json:"occurred_at"` // empty keeps the current value
+	Tags       []string `json:"tags"`        // omitted keeps the current tags, [] clears them
+}
+
+type RequestPatchTransaction struct {
+	Amount     Nullable[float64]  `json:"amount"`
+	Type       Nullable[string]   `json:"type"`
+	CategoryId Nullable[int]      `json:"category_id"`
+	Note       Nullable[string]   `json:"note"`
+	Payee      Nullable[string]   `json:"payee"`
+	OccurredAt Nullable[string]   `json:"occurred_at"`
+	Tags       Nullable[[]string] `json:"tags"`
+}
+
+type RequestBulkTransactions struct {
+	Mode       string                            `json:"mode"` // "atomic" (default) or "best_effort"
+	Operations []RequestBulkTransactionOperation `json:"operations"`
+}
+
+type RequestBulkTransactionOperation struct {
+	Op         string   `json:"op"`   // "create", "update_category", "change_type", "add_tags", "remove_tags" or "delete"
+	Ids        []int    `json:"ids"`  // target transactions for every op except create
+	Uuid       string   `json:"uuid"` // optional client-generated public id for create
+	Amount     float64  `json:"amount"`
+	Type       string   `json:"type"`
+	CategoryId int      `json:"category_id"`
+	Note       string   `json:"note"`
+	Payee      string   `json:"payee"`
+	OccurredAt string   `json:"occurred_at"`
+	Tags       []string `json:"tags"`
 }
 
 type QueryPagination struct {
-	Limit  int `json:"limit"`
-	Offset int `json:"offset"`
-	Page   int `json:"page"`
+	Limit     int       `json:"limit"`
+	Offset    int       `json:"offset"`
+	Page      int       `json:"page"`
+	Sort      QuerySort `json:"sort"`
+	UseCursor bool      `json:"use_cursor"`
+	Cursor    *Cursor   `json:"cursor"` // nil on the first page in cursor mode
+}
+
+// QueryTransactionFilter holds the parsed transaction list filters. Zero
+// values mean "no filter".
+type QueryTransactionFilter struct {
+	UserId             int        `json:"user_id"`
+	CategoryIds        []int      `json:"category_ids"`
+	ExcludeCategoryIds []int      `json:"exclude_category_ids"`
+	Types              []string   `json:"types"`
+	ExcludeTypes       []string   `json:"exclude_types"`
+	MinAmount          *float64   `json:"min_amount"`
+	MaxAmount          *float64   `json:"max_amount"`
+	Search             string     `json:"search"`
+	Tags               []string   `json:"tags"`              // any of these tag names
+	ExcludeTags        []string   `json:"exclude_tags"`      // none of these tag names
+	Statuses           []string   `json:"statuses"`          // uncleared, cleared or reconciled
+	ReconciliationId   int        `json:"reconciliation_id"` // reconciled in this session
+	StartDate          string     `json:"start_date"`        // inclusive, compared by day of occurred_at
+	EndDate            string     `json:"end_date"`          // inclusive, compared by day of occurred_at
+	StartTime          *time.Time `json:"start_time"`        // inclusive datetime bound
+	EndTime            *time.Time `json:"end_time"`          // inclusive datetime bound
+}
+
+type QuerySort struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc"`
+}
+
+// Cursor is the decoded form of the opaque keyset cursor: the sort column
+// value and id of the row to continue after (or before, when Backward).
+type Cursor struct {
+	Sort     string      `json:"s"`
+	Desc     bool        `json:"d"`
+	Value    interface{} `json:"v"`
+	Id       int         `json:"i"`
+	Backward bool        `json:"b,omitempty"`
 }
 
 type RequestPagination struct {
-	Limit string `json:"limit"`
-	Page  string `json:"page"`
+	Limit     string `json:"limit"`
+	Page      string `json:"page"`
+	Sort      string `json:"sort"`
+	Cursor    string `json:"cursor"`
+	UseCursor bool   `json:"-"` // true when the cursor query param is present, even if empty
 }
 
 type RequestGetBalance struct {
@@ -76,6 +180,7 @@ type RequestGetBalance struct {
 }
 
 type RequestCreateUser struct {
+	Uuid     string `json:"uuid"` // optional client-generated public id
 	Name     string `json:"name"`
 	Username string `json:"username"`
 	Password string `json:"password"`
@@ -89,6 +194,396 @@ type RequestUpdateUser struct {
 	Role     string `json:"role"`
 }
 
+type RequestPatchUser struct {
+	Name     Nullable[string] `json:"name"`
+	Username Nullable[string] `json:"username"`
+	Password Nullable[string] `json:"password"`
+	Role     Nullable[string] `json:"role"`
+}
+
+type RequestCreateTag struct {
+	Name string `json:"name"`
+}
+
+type RequestUpdateTag struct {
+	Name string `json:"name"`
+}
+
+type RequestGetTags struct {
+	UserId int    `json:"user_id"`
+	Name   string `json:"q"`
+	RequestPagination
+}
+
+type RequestGetTagById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestGetTagReport struct {
+	UserId    int    `json:"user_id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type RequestUploadAttachment struct {
+	FileName string
+	Size     int64
+	Content  io.Reader
+}
+
+type RequestGetAttachment struct {
+	TransactionId int  `uri:"id"`
+	AttachmentId  int  `uri:"attachmentId"`
+	Thumbnail     bool `form:"thumbnail"`
+}
+
+type RequestCreateRule struct {
+	Name          string   `json:"name"`
+	Priority      int      `json:"priority"`
+	Enabled       *bool    `json:"enabled"` // defaults to true
+	MatchMode     string   `json:"match_mode"`
+	PayeeMatch    string   `json:"payee_match"`
+	NoteMatch     string   `json:"note_match"`
+	MinAmount     *float64 `json:"min_amount"`
+	MaxAmount     *float64 `json:"max_amount"`
+	Type          string   `json:"type"`
+	SetCategoryId *int     `json:"set_category_id"`
+	AddTags       []string `json:"add_tags"`
+	SetPayee      string   `json:"set_payee"`
+}
+
+// RequestUpdateRule replaces every field of the rule, like PUT does for
+// transactions.
+type RequestUpdateRule RequestCreateRule
+
+type RequestGetRuleById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestApplyRules struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	RuleIds   []int  `json:"rule_ids"` // empty runs every enabled rule, listed ones run even if disabled
+	DryRun    bool   `json:"dry_run"`
+}
+
+type RequestGetDuplicates struct {
+	UserId          int    `json:"user_id"`
+	Window          string `json:"window"`           // Go duration such as "30m" or "48h"
+	PayeeSimilarity string `json:"payee_similarity"` // 0 to 1, empty ignores the payee
+	StartDate       string `json:"start_date"`
+	EndDate         string `json:"end_date"`
+}
+
+type RequestMergeDuplicate struct {
+	KeepId      int `json:"keep_id"`
+	DuplicateId int `json:"duplicate_id"`
+}
+
+type RequestDismissDuplicate struct {
+	TransactionId int `json:"transaction_id"`
+	DuplicateId   int `json:"duplicate_id"`
+}
+
+type RequestUpdateTransactionStatus struct {
+	Status string `json:"status"`
+}
+
+type RequestCreateReconciliation struct {
+	Account          string   `json:"account"`
+	StatementEndDate string   `json:"statement_end_date"`
+	StatementBalance *float64 `json:"statement_balance"`
+}
+
+type RequestGetReconciliationById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestImportTransactions struct {
+	Format          string    `form:"format"`      // csv, ofx, qif or camt053, detected from the file name when empty
+	CategoryId      int       `form:"category_id"` // category of imported rows, rules may override it
+	DryRun          bool      `form:"dry_run"`
+	AllowDuplicates bool      `form:"allow_duplicates"`
+	DuplicateWindow string    `form:"duplicate_window"`
+	FileName        string    `form:"-"`
+	Content         io.Reader `form:"-"`
+}
+
+type RequestCreateGoal struct {
+	Name         string  `json:"name"`
+	TargetAmount float64 `json:"target_amount"`
+	TargetDate   string  `json:"target_date"` // optional, YYYY-MM-DD
+	StartDate    string  `json:"start_date"`  // linked transactions count from this day, defaults to today
+	CategoryId   *int    `json:"category_id"`
+	TagId        *int    `json:"tag_id"`
+	Account      string  `json:"account"`
+}
+
+// RequestUpdateGoal replaces every field of the goal.
+type RequestUpdateGoal RequestCreateGoal
+
+type RequestGetGoalById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestCreateGoalContribution struct {
+	Amount float64 `json:"amount"` // negative for a withdrawal
+	Note   string  `json:"note"`
+	Date   string  `json:"date"` // "2006-01-02" or RFC 3339, defaults to now
+}
+
+type RequestGetGoalContribution struct {
+	GoalId         int `uri:"id"`
+	ContributionId int `uri:"contributionId"`
+}
+
+type RequestCreateDebt struct {
+	Name               string  `json:"name"`
+	Direction          string  `json:"direction"`
+	Counterparty       string  `json:"counterparty"`
+	Principal          float64 `json:"principal"`
+	AnnualInterestRate float64 `json:"annual_interest_rate"`
+	InterestMethod     string  `json:"interest_method"` // defaults to annuity
+	TermMonths         int     `json:"term_months"`
+	StartDate          string  `json:"start_date"` // YYYY-MM-DD
+}
+
+// RequestUpdateDebt replaces every field of the debt.
+type RequestUpdateDebt RequestCreateDebt
+
+type RequestGetDebtById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestCreateDebtPayment struct {
+	Amount        float64 `json:"amount"`         // defaults to the transaction amount
+	Date          string  `json:"date"`           // defaults to the transaction date, or now
+	TransactionId *int    `json:"transaction_id"` // optional linked transaction
+	Note          string  `json:"note"`
+}
+
+type RequestGetDebtPayment struct {
+	DebtId    int `uri:"id"`
+	PaymentId int `uri:"paymentId"`
+}
+
+type RequestCreateBill struct {
+	Name             string  `json:"name"`
+	Amount           float64 `json:"amount"`
+	AmountType       string  `json:"amount_type"` // defaults to fixed
+	CategoryId       *int    `json:"category_id"`
+	Frequency        string  `json:"frequency"`          // defaults to monthly
+	Interval         int     `json:"interval"`           // defaults to 1
+	FirstDueDate     string  `json:"first_due_date"`     // YYYY-MM-DD
+	EndDate          string  `json:"end_date"`           // YYYY-MM-DD, optional
+	RemindDaysBefore *int    `json:"remind_days_before"` // defaults to 1
+	Note             string  `json:"note"`
+}
+
+// RequestUpdateBill replaces every field of the bill.
+type RequestUpdateBill RequestCreateBill
+
+type RequestGetBillById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestGetBillOccurrences struct {
+	Id        int `uri:"id"`
+	StartDate string
+	EndDate   string
+}
+
+type RequestCreateBillPayment struct {
+	DueDate       string  `json:"due_date"`       // the occurrence being paid
+	Amount        float64 `json:"amount"`         // defaults to the transaction or bill amount
+	Date          string  `json:"date"`           // defaults to the transaction date, or now
+	TransactionId *int    `json:"transaction_id"` // optional linked transaction
+}
+
+type RequestGetBillPayment struct {
+	BillId    int `uri:"id"`
+	PaymentId int `uri:"paymentId"`
+}
+
+type RequestCreateAsset struct {
+	Name     string   `json:"name"`
+	Kind     string   `json:"kind"` // asset or liability
+	Type     string   `json:"type"`
+	Note     string   `json:"note"`
+	Value    *float64 `json:"value"`     // optional first valuation
+	ValuedAt string   `json:"valued_at"` // YYYY-MM-DD, defaults to today
+}
+
+type RequestUpdateAsset struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Type string `json:"type"`
+	Note string `json:"note"`
+}
+
+type RequestGetAssetById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestCreateAssetValuation struct {
+	Value float64 `json:"value"`
+	Date  string  `json:"date"` // YYYY-MM-DD, defaults to today
+}
+
+type RequestGetAssetValuation struct {
+	AssetId     int `uri:"id"`
+	ValuationId int `uri:"valuationId"`
+}
+
+type RequestGetNetWorthSeries struct {
+	StartDate string
+	EndDate   string
+	Interval  string // day, week or month
+}
+
+type RequestCreatePortfolio struct {
+	Name       string `json:"name"`
+	CostMethod string `json:"cost_method"` // fifo or average, defaults to fifo
+	Note       string `json:"note"`
+}
+
+// RequestUpdatePortfolio replaces every field of the portfolio.
+type RequestUpdatePortfolio RequestCreatePortfolio
+
+type RequestGetPortfolioById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestGetPortfolioValuation struct {
+	Id     int `uri:"id"`
+	Date   string
+	Method string // overrides the portfolio cost method
+}
+
+type RequestCreateHolding struct {
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+}
+
+type RequestGetHolding struct {
+	PortfolioId int `uri:"id"`
+	HoldingId   int `uri:"holdingId"`
+}
+
+type RequestCreateInvestmentActivity struct {
+	Type   string  `json:"type"` // buy, sell or dividend
+	Date   string  `json:"date"` // YYYY-MM-DD, defaults to today
+	Units  float64 `json:"units"`
+	Price  float64 `json:"price"`
+	Fee    float64 `json:"fee"`
+	Amount float64 `json:"amount"` // dividends only; buys and sells use units × price
+	Note   string  `json:"note"`
+	// CreateTransaction records the cash movement as an expense (buy) or
+	// income (sell, dividend) in CategoryId
+	CreateTransaction bool `json:"create_transaction"`
+	CategoryId        int  `json:"category_id"`
+}
+
+type RequestGetInvestmentActivity struct {
+	PortfolioId int `uri:"id"`
+	HoldingId   int `uri:"holdingId"`
+	ActivityId  int `uri:"activityId"`
+}
+
+type RequestCreatePrice struct {
+	Symbol string  `json:"symbol"`
+	Date   string  `json:"date"` // YYYY-MM-DD, defaults to today
+	Price  float64 `json:"price"`
+}
+
+type RequestGetPrices struct {
+	Symbol    string
+	StartDate string
+	EndDate   string
+}
+
+type RequestGetPriceById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestImportPrices struct {
+	Symbol  string    `form:"symbol"` // symbol of every row when the file has no symbol column
+	Content io.Reader `form:"-"`
+}
+
+type RequestGetEnvelopes struct {
+	Date string // any day of the cycle, defaults to today
+}
+
+type RequestSetEnvelopeCategory struct {
+	CategoryId int   `json:"-" uri:"categoryId"`
+	Rollover   *bool `json:"rollover"` // defaults to true
+}
+
+type RequestGetEnvelopeCategory struct {
+	CategoryId int `uri:"categoryId"`
+}
+
+type RequestAssignEnvelope struct {
+	CategoryId int     `json:"category_id"`
+	Date       string  `json:"date"` // any day of the cycle, defaults to today
+	Amount     float64 `json:"amount"`
+}
+
+type RequestGetNotifications struct {
+	UserId int    `json:"user_id"`
+	Unread string `json:"unread"` // "true" lists unread notifications only
+	RequestPagination
+}
+
+type RequestGetNotificationById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestUpdateNotificationSettings struct {
+	BudgetThresholds       []int   `json:"budget_thresholds"`
+	LargeTransactionAmount float64 `json:"large_transaction_amount"`
+	BillReminders          *bool   `json:"bill_reminders"` // defaults to true
+	Email                  string  `json:"email"`
+	WebhookUrl             string  `json:"webhook_url"`
+}
+
+type RequestCreateWebhookSubscription struct {
+	Url         string   `json:"url"`
+	Description string   `json:"description"`
+	Events      []string `json:"events"`
+	Secret      string   `json:"secret"` // generated when empty
+	Active      *bool    `json:"active"` // defaults to true
+}
+
+type RequestUpdateWebhookSubscription RequestCreateWebhookSubscription
+
+type RequestGetWebhookSubscriptionById struct {
+	Id int `json:"id" uri:"id"`
+}
+
+type RequestGetWebhookDeliveries struct {
+	SubscriptionId int    `json:"subscription_id"`
+	Status         string `json:"status"`
+	RequestPagination
+}
+
+type RequestGetWebhookDelivery struct {
+	SubscriptionId int `json:"subscription_id" uri:"id"`
+	DeliveryId     int `json:"delivery_id" uri:"deliveryId"`
+}
+
+type RequestGetDomainEvents struct {
+	Type string `json:"type"`
+	RequestPagination
+}
+
+type RequestReplayEvents struct {
+	Subscriber   string `json:"-" uri:"subscriber"`
+	FromSequence int64  `json:"from_sequence"` // handled again from this sequence on
+}
+
 type RequestDeleteUser struct {
 	Id int `json:"id" uri:"id"`
 }
@@ -96,3 +591,31 @@ type RequestDeleteUser struct {
 type RequestGetAllUsers struct {
 	RequestPagination
 }
+
+// RequestGraphQL is the body of POST /graphql.
+type RequestGraphQL struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type RequestSync struct {
+	Since string `form:"since"`
+	Limit string `form:"limit"`
+}
+
+type RequestPushSync struct {
+	Changes []SyncChange `json:"changes" binding:"required"`
+}
+
+// SyncChange is one offline change. Data holds the fields of POST
+// /transactions for a transaction upsert and {"name"} for a tag upsert.
+// BaseUpdatedAt is the updated_at the client last pulled, empty for
+// last-write-wins.
+type SyncChange struct {
+	Entity        string          `json:"entity"`
+	Op            string          `json:"op"`
+	Uuid          string          `json:"uuid"`
+	BaseUpdatedAt string          `json:"base_updated_at"`
+	Data          json.RawMessage `json:"data"`
+}
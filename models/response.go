@@ -1,5 +1,7 @@
 package models
 
+import "io"
+
 type Response struct {
 	Code    int         `json:"code"`
 	Status  string      `json:"status"`
@@ -13,41 +15,306 @@ type ResponseLogin struct {
 }
 
 type ResponseCategoryList struct {
-	Data  []Category `json:"data"`
-	Count int64      `json:"count"`
-	Page  int        `json:"page"`
-	Limit int        `json:"limit"`
+	Data       []Category `json:"data"`
+	Count      int64      `json:"count"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	PrevCursor string     `json:"prev_cursor,omitempty"`
 }
 
 type ResponseTransactionList struct {
-	Data  []TransactionResponse `json:"data"`
-	Count int64                 `json:"count"`
-	Page  int                   `json:"page"`
-	Limit int                   `json:"limit"`
+	Data       []TransactionResponse `json:"data"`
+	Count      int64                 `json:"count"`
+	Page       int                   `json:"page"`
+	Limit      int                   `json:"limit"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	PrevCursor string                `json:"prev_cursor,omitempty"`
 }
 
 type TransactionResponse struct {
-	Id        int                    `json:"id"`
-	User      UserSimpleResponse     `json:"user"`
-	Amount    float64                `json:"amount"`
-	Type      string                 `json:"type"`
-	Category  CategorySimpleResponse `json:"category"`
-	CreatedAt string                 `json:"created_at"`
-	UpdatedAt string                 `json:"updated_at"`
+	Id               int                    `json:"id"`
+	Uuid             string                 `json:"uuid"`
+	User             UserSimpleResponse     `json:"user"`
+	Amount           float64                `json:"amount"`
+	Type             string                 `json:"type"`
+	Category         CategorySimpleResponse `json:"category"`
+	Note             string                 `json:"note"`
+	Payee            string                 `json:"payee"`
+	OccurredAt       string                 `json:"occurred_at"`
+	Tags             []TagSimpleResponse    `json:"tags"`
+	Status           string                 `json:"status"`
+	ReconciliationId *int                   `json:"reconciliation_id"`
+	BankReference    string                 `json:"bank_reference,omitempty"`
+	CreatedAt        string                 `json:"created_at"`
+	UpdatedAt        string                 `json:"updated_at"`
+	// Version is updated_at at full precision, the base_updated_at of sync
+	// pushes.
+	Version string `json:"version"`
+}
+
+type ResponseBulkTransactions struct {
+	Mode      string                  `json:"mode"`
+	Committed bool                    `json:"committed"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+	Results   []BulkTransactionResult `json:"results"`
+}
+
+type BulkTransactionResult struct {
+	Operation   int                  `json:"operation"` // index into the request operations
+	Op          string               `json:"op"`
+	Id          int                  `json:"id,omitempty"`
+	Status      string               `json:"status"` // "ok", "error" or "rolled_back"
+	Error       string               `json:"error,omitempty"`
+	Transaction *TransactionResponse `json:"transaction,omitempty"`
+}
+
+type ResponseImportTransactions struct {
+	Format     string            `json:"format"`
+	DryRun     bool              `json:"dry_run"`
+	Total      int               `json:"total"`
+	Imported   int               `json:"imported"`
+	Skipped    int               `json:"skipped"`    // already imported before
+	Duplicates int               `json:"duplicates"` // likely duplicates, not inserted
+	Failed     int               `json:"failed"`
+	Results    []ImportRowResult `json:"results"`
+}
+
+type ImportRowResult struct {
+	Row           int                  `json:"row"`
+	Date          string               `json:"date"`
+	Amount        float64              `json:"amount"`
+	Type          string               `json:"type"`
+	Payee         string               `json:"payee"`
+	Memo          string               `json:"memo"`
+	BankReference string               `json:"bank_reference"`
+	Status        string               `json:"status"` // "imported", "already_imported", "possible_duplicate" or "error"
+	Error         string               `json:"error,omitempty"`
+	DuplicateOf   []int                `json:"duplicate_of,omitempty"`
+	Transaction   *TransactionResponse `json:"transaction,omitempty"`
+}
+
+// ResponseGoalProgress is how far a goal is and where it is heading. The
+// projection uses the average monthly contribution of the last three months.
+type ResponseGoalProgress struct {
+	Goal                    Goal     `json:"goal"`
+	Saved                   float64  `json:"saved"`
+	ManualSaved             float64  `json:"manual_saved"`
+	LinkedSaved             float64  `json:"linked_saved"`
+	Remaining               float64  `json:"remaining"`
+	Percent                 float64  `json:"percent"`
+	MonthlyNeeded           *float64 `json:"monthly_needed"` // only with a target date
+	RecentMonthlyRate       float64  `json:"recent_monthly_rate"`
+	ProjectedCompletionDate *string  `json:"projected_completion_date"` // null when the rate is not positive
+	OnTrack                 *bool    `json:"on_track"`                  // only with a target date
+}
+
+type ResponseDebtSchedule struct {
+	Debt         Debt          `json:"debt"`
+	Installments []Installment `json:"installments"`
+}
+
+// ResponseDebtSummary is where a debt stands after the recorded payments.
+type ResponseDebtSummary struct {
+	Debt                 Debt          `json:"debt"`
+	InstallmentAmount    float64       `json:"installment_amount"`
+	TotalPayable         float64       `json:"total_payable"`
+	TotalInterest        float64       `json:"total_interest"`
+	PaidTotal            float64       `json:"paid_total"`
+	RemainingPayable     float64       `json:"remaining_payable"`
+	OutstandingPrincipal float64       `json:"outstanding_principal"`
+	PaidInstallments     int           `json:"paid_installments"`
+	OverdueAmount        float64       `json:"overdue_amount"`
+	NextDueDate          *string       `json:"next_due_date"`
+	Upcoming             []Installment `json:"upcoming"`
+}
+
+type ResponseCalendarToken struct {
+	Token string `json:"token"`
+	Url   string `json:"url"`
+}
+
+// ResponseAsset is an asset with its latest valuation.
+type ResponseAsset struct {
+	Asset
+	CurrentValue *float64 `json:"current_value"`
+	ValuedAt     *string  `json:"valued_at"`
+}
+
+type ResponsePortfolioValuation struct {
+	Portfolio             Portfolio          `json:"portfolio"`
+	Date                  string             `json:"date"`
+	CostMethod            string             `json:"cost_method"`
+	Holdings              []HoldingValuation `json:"holdings"`
+	CostBasis             float64            `json:"cost_basis"`
+	MarketValue           float64            `json:"market_value"`
+	UnrealizedGain        float64            `json:"unrealized_gain"`
+	UnrealizedGainPercent float64            `json:"unrealized_gain_percent"`
+	RealizedGain          float64            `json:"realized_gain"`
+	Dividends             float64            `json:"dividends"`
+}
+
+type ResponseImportPrices struct {
+	Saved   int      `json:"saved"`
+	Symbols []string `json:"symbols"`
+}
+
+// ResponseEnvelopes is the envelope budget of one cycle. AvailableToAssign
+// is the income not yet given to an envelope, including what was left
+// before envelopes were used and minus spending outside the envelopes.
+type ResponseEnvelopes struct {
+	CycleStart         string     `json:"cycle_start"`
+	CycleEnd           string     `json:"cycle_end"`
+	Income             float64    `json:"income"`
+	UnbudgetedSpending float64    `json:"unbudgeted_spending"`
+	Assigned           float64    `json:"assigned"`
+	Activity           float64    `json:"activity"`
+	Available          float64    `json:"available"`
+	Overspent          float64    `json:"overspent"`
+	AvailableToAssign  float64    `json:"available_to_assign"`
+	Envelopes          []Envelope `json:"envelopes"`
+}
+
+type ResponseNotificationList struct {
+	Data        []Notification `json:"data"`
+	Count       int64          `json:"count"`
+	UnreadCount int64          `json:"unread_count"`
+	Page        int            `json:"page"`
+	Limit       int            `json:"limit"`
+	NextCursor  string         `json:"next_cursor,omitempty"`
+	PrevCursor  string         `json:"prev_cursor,omitempty"`
+}
+
+// ResponseWebhookSubscription is returned on create and when the secret is
+// changed, the only times the secret is shown.
+type ResponseWebhookSubscription struct {
+	WebhookSubscription
+	Secret string `json:"secret,omitempty"`
+}
+
+type ResponseWebhookDeliveryList struct {
+	Data       []WebhookDelivery `json:"data"`
+	Count      int64             `json:"count"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	PrevCursor string            `json:"prev_cursor,omitempty"`
+}
+
+type ResponseDomainEventList struct {
+	Data       []DomainEvent `json:"data"`
+	Count      int64         `json:"count"`
+	Page       int           `json:"page"`
+	Limit      int           `json:"limit"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	PrevCursor string        `json:"prev_cursor,omitempty"`
 }
 
 type UserSimpleResponse struct {
 	Id   int    `json:"id"`
+	Uuid string `json:"uuid"`
 	Name string `json:"name"`
 }
 
 type CategorySimpleResponse struct {
+	Id   int    `json:"id"`
+	Uuid string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+type TagSimpleResponse struct {
 	Id   int    `json:"id"`
 	Name string `json:"name"`
 }
 
+type ResponseTagList struct {
+	Data       []Tag  `json:"data"`
+	Count      int64  `json:"count"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+type TagTotal struct {
+	TagId        int     `json:"tag_id"`
+	Name         string  `json:"name"`
+	Count        int64   `json:"count"`
+	TotalIncome  float64 `json:"total_income"`
+	TotalExpense float64 `json:"total_expense"`
+}
+
+type ResponseTagReport struct {
+	UserId    int        `json:"user_id"`
+	StartDate string     `json:"start_date"`
+	EndDate   string     `json:"end_date"`
+	Data      []TagTotal `json:"data"`
+}
+
+type ResponseApplyRules struct {
+	DryRun    bool         `json:"dry_run"`
+	StartDate string       `json:"start_date"`
+	EndDate   string       `json:"end_date"`
+	Scanned   int          `json:"scanned"`
+	Changed   int          `json:"changed"`
+	Changes   []RuleChange `json:"changes"`
+}
+
+// RuleChange is the diff the rules make (or would make, on a dry run) to
+// one transaction.
+type RuleChange struct {
+	TransactionId int        `json:"transaction_id"`
+	RuleIds       []int      `json:"rule_ids"`
+	Before        RuleFields `json:"before"`
+	After         RuleFields `json:"after"`
+}
+
+type RuleFields struct {
+	CategoryId int      `json:"category_id"`
+	Payee      string   `json:"payee"`
+	Tags       []string `json:"tags"`
+}
+
+type ResponseDuplicateList struct {
+	Window    string               `json:"window"`
+	StartDate string               `json:"start_date"`
+	EndDate   string               `json:"end_date"`
+	Count     int                  `json:"count"`
+	Data      []DuplicateCandidate `json:"data"`
+}
+
+type DuplicateCandidate struct {
+	Transaction     TransactionResponse `json:"transaction"`
+	Duplicate       TransactionResponse `json:"duplicate"`
+	TimeDifference  int64               `json:"time_difference_seconds"`
+	PayeeSimilarity float64             `json:"payee_similarity"`
+}
+
+// ResponseReconciliation is a session with its running totals. The cleared
+// balance is every reconciled transaction plus the cleared ones up to the
+// statement end date; the session can be finalized once Difference is zero.
+type ResponseReconciliation struct {
+	Reconciliation
+	ReconciledBalance float64 `json:"reconciled_balance"`
+	ClearedTotal      float64 `json:"cleared_total"`
+	ClearedCount      int64   `json:"cleared_count"`
+	ClearedBalance    float64 `json:"cleared_balance"`
+	Difference        float64 `json:"difference"`
+}
+
+// ResponseAttachmentContent is a stored attachment (or its thumbnail) ready
+// to be streamed to the client. The caller must close Content.
+type ResponseAttachmentContent struct {
+	Attachment  Attachment
+	ContentType string
+	Size        int64
+	Content     io.ReadCloser
+}
+
 type UserResponse struct {
 	Id       int    `json:"id"`
+	Uuid     string `json:"uuid"`
 	Name     string `json:"name"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
@@ -70,8 +337,60 @@ type ResponseBalance struct {
 }
 
 type ResponseUserList struct {
-	Data  []UserResponse `json:"data"`
-	Count int64          `json:"count"`
-	Page  int            `json:"page"`
-	Limit int            `json:"limit"`
+	Data       []UserResponse `json:"data"`
+	Count      int64          `json:"count"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	PrevCursor string         `json:"prev_cursor,omitempty"`
+}
+
+// ResponseSync holds what changed since the token, or everything when Full.
+type ResponseSync struct {
+	Token        string                 `json:"token"`
+	Full         bool                   `json:"full"`
+	HasMore      bool                   `json:"has_more"`
+	Transactions SyncTransactionChanges `json:"transactions"`
+	Categories   SyncCategoryChanges    `json:"categories"`
+	Tags         SyncTagChanges         `json:"tags"`
+}
+
+type SyncTransactionChanges struct {
+	Upserted []TransactionResponse `json:"upserted"`
+	Deleted  []SyncTombstone       `json:"deleted"`
+}
+
+type SyncCategoryChanges struct {
+	Upserted []Category      `json:"upserted"`
+	Deleted  []SyncTombstone `json:"deleted"`
+}
+
+type SyncTagChanges struct {
+	Upserted []Tag           `json:"upserted"`
+	Deleted  []SyncTombstone `json:"deleted"`
+}
+
+// SyncTombstone identifies a deleted entity.
+type SyncTombstone struct {
+	Id   int    `json:"id"`
+	Uuid string `json:"uuid"`
+}
+
+type ResponsePushSync struct {
+	Applied   int                `json:"applied"`
+	Conflicts int                `json:"conflicts"`
+	Failed    int                `json:"failed"`
+	Results   []SyncChangeResult `json:"results"`
+}
+
+// SyncChangeResult is the outcome of one pushed change. Current is the
+// server's copy after an applied upsert or on a conflict, null once deleted.
+type SyncChangeResult struct {
+	Index   int         `json:"index"`
+	Entity  string      `json:"entity"`
+	Op      string      `json:"op"`
+	Uuid    string      `json:"uuid"`
+	Status  string      `json:"status"`
+	Error   string      `json:"error,omitempty"`
+	Current interface{} `json:"current"`
 }
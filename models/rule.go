@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Rule auto-categorizes a user's transactions. Every condition that is set
+// must match; the actions are then applied on top of the transaction.
+type Rule struct {
+	Id       int    `json:"id"`
+	UserId   int    `json:"user_id" gorm:"index"`
+	Name     string `json:"name"`
+	Priority int    `json:"priority"` // lower runs first
+	Enabled  bool   `json:"enabled"`
+	// Conditions
+	MatchMode  string   `json:"match_mode"` // contains or regex, used by payee_match and note_match
+	PayeeMatch string   `json:"payee_match"`
+	NoteMatch  string   `json:"note_match"`
+	MinAmount  *float64 `json:"min_amount"`
+	MaxAmount  *float64 `json:"max_amount"`
+	Type       string   `json:"type"`
+	// Actions
+	SetCategoryId *int      `json:"set_category_id"`
+	AddTags       []string  `json:"add_tags" gorm:"serializer:json"`
+	SetPayee      string    `json:"set_payee"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
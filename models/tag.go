@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type Tag struct {
+	Id        int       `json:"id"`
+	Uuid      string    `json:"uuid" gorm:"type:uuid;uniqueIndex"`
+	UserId    int       `json:"user_id" gorm:"index"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
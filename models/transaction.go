@@ -3,13 +3,21 @@ package models
 import "time"
 
 type Transaction struct {
-	Id         int       `json:"id" gorm:"primaryKey"`
-	UserId     int       `json:"user_id"`
-	User       User      `json:"user" gorm:"foreignKey:UserId"`
-	Amount     float64   `json:"amount"`
-	Type       string    `json:"type"`
-	CategoryId int       `json:"category_id"`
-	Category   Category  `json:"category" gorm:"foreignKey:CategoryId"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	Id               int       `json:"id" gorm:"primaryKey"`
+	Uuid             string    `json:"uuid" gorm:"type:uuid;uniqueIndex"`
+	UserId           int       `json:"user_id"`
+	User             User      `json:"user" gorm:"foreignKey:UserId"`
+	Amount           float64   `json:"amount"`
+	Type             string    `json:"type"`
+	CategoryId       int       `json:"category_id"`
+	Category         Category  `json:"category" gorm:"foreignKey:CategoryId"`
+	Note             string    `json:"note"`
+	Payee            string    `json:"payee"`
+	OccurredAt       time.Time `json:"occurred_at" gorm:"index"` // user-chosen transaction date, defaults to insertion time
+	Tags             []Tag     `json:"tags" gorm:"many2many:transaction_tags;"`
+	Status           string    `json:"status" gorm:"default:uncleared;index"` // uncleared, cleared or reconciled (locked)
+	ReconciliationId *int      `json:"reconciliation_id"`
+	BankReference    string    `json:"bank_reference" gorm:"index"` // statement reference of imported rows
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
@@ -4,6 +4,7 @@ import "time"
 
 type User struct {
 	Id        int       `json:"id"`
+	Uuid      string    `json:"uuid" gorm:"type:uuid;uniqueIndex"`
 	Name      string    `json:"name"`
 	Username  string    `json:"username"`
 	Password  string    `json:"password"`
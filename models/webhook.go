@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// WebhookSubscription sends the chosen events to Url. Every request is
+// signed with HMAC-SHA256 using Secret.
+type WebhookSubscription struct {
+	Id          int       `json:"id"`
+	Url         string    `json:"url"`
+	Description string    `json:"description"`
+	Events      []string  `json:"events" gorm:"serializer:json"`
+	Secret      string    `json:"-"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery is one event queued for one subscription. Pending
+// deliveries are sent once NextAttemptAt has passed and retried with
+// exponential backoff until they succeed or run out of attempts.
+type WebhookDelivery struct {
+	Id             int        `json:"id"`
+	SubscriptionId int        `json:"subscription_id" gorm:"index"`
+	EventId        string     `json:"event_id" gorm:"index"`
+	Event          string     `json:"event"`
+	Payload        string     `json:"payload" gorm:"type:text"`
+	Status         string     `json:"status" gorm:"index"` // pending, succeeded or failed
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at" gorm:"index"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at"`
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	RedeliveryOf   *int       `json:"redelivery_of"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// WebhookEvent is the JSON body posted to subscribers.
+type WebhookEvent struct {
+	Id        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookUser is the user sent with user events, without the password.
+type WebhookUser struct {
+	Id        int       `json:"id"`
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type EmailConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type emailChannel struct {
+	Config EmailConfig
+}
+
+// NewEmailChannel sends plain text mails through an SMTP server. PLAIN auth
+// is used when a username is configured.
+func NewEmailChannel(config EmailConfig) Channel {
+	if config.Port == "" {
+		config.Port = "587"
+	}
+	return &emailChannel{Config: config}
+}
+
+func (e *emailChannel) Send(ctx context.Context, recipient string, message Message) (err error) {
+	if strings.ContainsAny(recipient, "\r\n") || !strings.Contains(recipient, "@") {
+		return errors.New("invalid email recipient")
+	}
+
+	var auth smtp.Auth
+	if e.Config.Username != "" {
+		auth = smtp.PlainAuth("", e.Config.Username, e.Config.Password, e.Config.Host)
+	}
+
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(message.Title)
+	body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		e.Config.From, recipient, subject, message.Body)
+
+	err = smtp.SendMail(net.JoinHostPort(e.Config.Host, e.Config.Port), auth, e.Config.From, []string{recipient}, []byte(body))
+	return
+}
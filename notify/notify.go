@@ -0,0 +1,23 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Message is a notification rendered for delivery outside the app.
+type Message struct {
+	Id        int                    `json:"id"`
+	UserId    int                    `json:"user_id"`
+	Type      string                 `json:"type"`
+	Title     string                 `json:"title"`
+	Body      string                 `json:"body"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// Channel delivers messages to a recipient, such as an email address or a
+// webhook URL.
+type Channel interface {
+	Send(ctx context.Context, recipient string, message Message) (err error)
+}
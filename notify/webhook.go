@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const webhookTimeout = 10 * time.Second
+
+type webhookChannel struct {
+	Client *http.Client
+}
+
+// NewWebhookChannel posts the message as JSON to the recipient URL. Any 2xx
+// response counts as delivered; redirects and private addresses are refused.
+func NewWebhookChannel() Channel {
+	return &webhookChannel{Client: NewPublicClient(webhookTimeout)}
+}
+
+func (w *webhookChannel) Send(ctx context.Context, recipient string, message Message) (err error) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, recipient, bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := w.Client.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		err = fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+	return
+}
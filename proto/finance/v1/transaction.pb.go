@@ -0,0 +1,1423 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: finance/v1/transaction.proto
+
+package financev1
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Transaction struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uuid   string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	User   *TransactionUser       `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Amount float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	// "income" or "expense".
+	Type     string               `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	Category *TransactionCategory `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	Note     string               `protobuf:"bytes,7,opt,name=note,proto3" json:"note,omitempty"`
+	Payee    string               `protobuf:"bytes,8,opt,name=payee,proto3" json:"payee,omitempty"`
+	// "2006-01-02 15:04:05", like the REST responses.
+	OccurredAt       string            `protobuf:"bytes,9,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	Tags             []*TransactionTag `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	Status           string            `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"`
+	ReconciliationId *int64            `protobuf:"varint,12,opt,name=reconciliation_id,json=reconciliationId,proto3,oneof" json:"reconciliation_id,omitempty"`
+	BankReference    string            `protobuf:"bytes,13,opt,name=bank_reference,json=bankReference,proto3" json:"bank_reference,omitempty"`
+	CreatedAt        string            `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        string            `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Transaction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Transaction) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Transaction) GetUser() *TransactionUser {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *Transaction) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transaction) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Transaction) GetCategory() *TransactionCategory {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+func (x *Transaction) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *Transaction) GetPayee() string {
+	if x != nil {
+		return x.Payee
+	}
+	return ""
+}
+
+func (x *Transaction) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+func (x *Transaction) GetTags() []*TransactionTag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Transaction) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Transaction) GetReconciliationId() int64 {
+	if x != nil && x.ReconciliationId != nil {
+		return *x.ReconciliationId
+	}
+	return 0
+}
+
+func (x *Transaction) GetBankReference() string {
+	if x != nil {
+		return x.BankReference
+	}
+	return ""
+}
+
+func (x *Transaction) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Transaction) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type TransactionUser struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uuid          string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionUser) Reset() {
+	*x = TransactionUser{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionUser) ProtoMessage() {}
+
+func (x *TransactionUser) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionUser.ProtoReflect.Descriptor instead.
+func (*TransactionUser) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TransactionUser) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TransactionUser) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *TransactionUser) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type TransactionCategory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uuid          string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionCategory) Reset() {
+	*x = TransactionCategory{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionCategory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionCategory) ProtoMessage() {}
+
+func (x *TransactionCategory) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionCategory.ProtoReflect.Descriptor instead.
+func (*TransactionCategory) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TransactionCategory) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TransactionCategory) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *TransactionCategory) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type TransactionTag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionTag) Reset() {
+	*x = TransactionTag{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionTag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionTag) ProtoMessage() {}
+
+func (x *TransactionTag) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionTag.ProtoReflect.Descriptor instead.
+func (*TransactionTag) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TransactionTag) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TransactionTag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// TransactionFilter narrows a listing with the filters of
+// GET /transactions. Ids are integer ids or uuids.
+type TransactionFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Admins only: the user whose transactions to list, every user when empty.
+	UserId             string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CategoryIds        []string `protobuf:"bytes,2,rep,name=category_ids,json=categoryIds,proto3" json:"category_ids,omitempty"`
+	ExcludeCategoryIds []string `protobuf:"bytes,3,rep,name=exclude_category_ids,json=excludeCategoryIds,proto3" json:"exclude_category_ids,omitempty"`
+	Types              []string `protobuf:"bytes,4,rep,name=types,proto3" json:"types,omitempty"`
+	ExcludeTypes       []string `protobuf:"bytes,5,rep,name=exclude_types,json=excludeTypes,proto3" json:"exclude_types,omitempty"`
+	MinAmount          *float64 `protobuf:"fixed64,6,opt,name=min_amount,json=minAmount,proto3,oneof" json:"min_amount,omitempty"`
+	MaxAmount          *float64 `protobuf:"fixed64,7,opt,name=max_amount,json=maxAmount,proto3,oneof" json:"max_amount,omitempty"`
+	// Searches the notes and payees.
+	Search      string   `protobuf:"bytes,8,opt,name=search,proto3" json:"search,omitempty"`
+	Tags        []string `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	ExcludeTags []string `protobuf:"bytes,10,rep,name=exclude_tags,json=excludeTags,proto3" json:"exclude_tags,omitempty"`
+	Statuses    []string `protobuf:"bytes,11,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	// "2006-01-02" for the whole day or an RFC 3339 datetime.
+	StartDate     string `protobuf:"bytes,12,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string `protobuf:"bytes,13,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionFilter) Reset() {
+	*x = TransactionFilter{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionFilter) ProtoMessage() {}
+
+func (x *TransactionFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionFilter.ProtoReflect.Descriptor instead.
+func (*TransactionFilter) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TransactionFilter) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TransactionFilter) GetCategoryIds() []string {
+	if x != nil {
+		return x.CategoryIds
+	}
+	return nil
+}
+
+func (x *TransactionFilter) GetExcludeCategoryIds() []string {
+	if x != nil {
+		return x.ExcludeCategoryIds
+	}
+	return nil
+}
+
+func (x *TransactionFilter) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *TransactionFilter) GetExcludeTypes() []string {
+	if x != nil {
+		return x.ExcludeTypes
+	}
+	return nil
+}
+
+func (x *TransactionFilter) GetMinAmount() float64 {
+	if x != nil && x.MinAmount != nil {
+		return *x.MinAmount
+	}
+	return 0
+}
+
+func (x *TransactionFilter) GetMaxAmount() float64 {
+	if x != nil && x.MaxAmount != nil {
+		return *x.MaxAmount
+	}
+	return 0
+}
+
+func (x *TransactionFilter) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *TransactionFilter) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *TransactionFilter) GetExcludeTags() []string {
+	if x != nil {
+		return x.ExcludeTags
+	}
+	return nil
+}
+
+func (x *TransactionFilter) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *TransactionFilter) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *TransactionFilter) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+// TagNames is a list of tag names that can be told apart from no list.
+type TagNames struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         []string               `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagNames) Reset() {
+	*x = TagNames{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagNames) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagNames) ProtoMessage() {}
+
+func (x *TagNames) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagNames.ProtoReflect.Descriptor instead.
+func (*TagNames) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TagNames) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type CreateTransactionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional client-generated public id.
+	Uuid   string  `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Amount float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Type   string  `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	// Integer id or uuid.
+	CategoryId string `protobuf:"bytes,4,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Note       string `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+	Payee      string `protobuf:"bytes,6,opt,name=payee,proto3" json:"payee,omitempty"`
+	// "2006-01-02" or RFC 3339, now when left out.
+	OccurredAt string `protobuf:"bytes,7,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// Tag names, created for the user when missing.
+	Tags          []string `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTransactionRequest) Reset() {
+	*x = CreateTransactionRequest{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTransactionRequest) ProtoMessage() {}
+
+func (x *CreateTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTransactionRequest.ProtoReflect.Descriptor instead.
+func (*CreateTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateTransactionRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *CreateTransactionRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CreateTransactionRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CreateTransactionRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *CreateTransactionRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *CreateTransactionRequest) GetPayee() string {
+	if x != nil {
+		return x.Payee
+	}
+	return ""
+}
+
+func (x *CreateTransactionRequest) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+func (x *CreateTransactionRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type CreateTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transaction   *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTransactionResponse) Reset() {
+	*x = CreateTransactionResponse{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTransactionResponse) ProtoMessage() {}
+
+func (x *CreateTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTransactionResponse.ProtoReflect.Descriptor instead.
+func (*CreateTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateTransactionResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type GetTransactionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Integer id or uuid.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTransactionRequest) Reset() {
+	*x = GetTransactionRequest{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransactionRequest) ProtoMessage() {}
+
+func (x *GetTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransactionRequest.ProtoReflect.Descriptor instead.
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTransactionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transaction   *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTransactionResponse) Reset() {
+	*x = GetTransactionResponse{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransactionResponse) ProtoMessage() {}
+
+func (x *GetTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransactionResponse.ProtoReflect.Descriptor instead.
+func (*GetTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetTransactionResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type ListTransactionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *TransactionFilter     `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Page          *PageRequest           `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsRequest) Reset() {
+	*x = ListTransactionsRequest{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsRequest) ProtoMessage() {}
+
+func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListTransactionsRequest) GetFilter() *TransactionFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListTransactionsRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type ListTransactionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transactions  []*Transaction         `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	PageInfo      *PageInfo              `protobuf:"bytes,2,opt,name=page_info,json=pageInfo,proto3" json:"page_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsResponse) Reset() {
+	*x = ListTransactionsResponse{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsResponse) ProtoMessage() {}
+
+func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ListTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListTransactionsResponse) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *ListTransactionsResponse) GetPageInfo() *PageInfo {
+	if x != nil {
+		return x.PageInfo
+	}
+	return nil
+}
+
+type StreamTransactionsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *TransactionFilter     `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// "field:asc" or "field:desc", newest first when left out.
+	Sort          string `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTransactionsRequest) Reset() {
+	*x = StreamTransactionsRequest{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTransactionsRequest) ProtoMessage() {}
+
+func (x *StreamTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*StreamTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StreamTransactionsRequest) GetFilter() *TransactionFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *StreamTransactionsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type StreamTransactionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transaction   *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTransactionsResponse) Reset() {
+	*x = StreamTransactionsResponse{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTransactionsResponse) ProtoMessage() {}
+
+func (x *StreamTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*StreamTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StreamTransactionsResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type UpdateTransactionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Integer id or uuid.
+	Id     string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Amount *float64 `protobuf:"fixed64,2,opt,name=amount,proto3,oneof" json:"amount,omitempty"`
+	Type   *string  `protobuf:"bytes,3,opt,name=type,proto3,oneof" json:"type,omitempty"`
+	// Integer id or uuid.
+	CategoryId *string `protobuf:"bytes,4,opt,name=category_id,json=categoryId,proto3,oneof" json:"category_id,omitempty"`
+	Note       *string `protobuf:"bytes,5,opt,name=note,proto3,oneof" json:"note,omitempty"`
+	Payee      *string `protobuf:"bytes,6,opt,name=payee,proto3,oneof" json:"payee,omitempty"`
+	OccurredAt *string `protobuf:"bytes,7,opt,name=occurred_at,json=occurredAt,proto3,oneof" json:"occurred_at,omitempty"`
+	// Replaces the tags when set; an empty list clears them.
+	Tags          *TagNames `protobuf:"bytes,8,opt,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTransactionRequest) Reset() {
+	*x = UpdateTransactionRequest{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTransactionRequest) ProtoMessage() {}
+
+func (x *UpdateTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTransactionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateTransactionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateTransactionRequest) GetAmount() float64 {
+	if x != nil && x.Amount != nil {
+		return *x.Amount
+	}
+	return 0
+}
+
+func (x *UpdateTransactionRequest) GetType() string {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ""
+}
+
+func (x *UpdateTransactionRequest) GetCategoryId() string {
+	if x != nil && x.CategoryId != nil {
+		return *x.CategoryId
+	}
+	return ""
+}
+
+func (x *UpdateTransactionRequest) GetNote() string {
+	if x != nil && x.Note != nil {
+		return *x.Note
+	}
+	return ""
+}
+
+func (x *UpdateTransactionRequest) GetPayee() string {
+	if x != nil && x.Payee != nil {
+		return *x.Payee
+	}
+	return ""
+}
+
+func (x *UpdateTransactionRequest) GetOccurredAt() string {
+	if x != nil && x.OccurredAt != nil {
+		return *x.OccurredAt
+	}
+	return ""
+}
+
+func (x *UpdateTransactionRequest) GetTags() *TagNames {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type UpdateTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transaction   *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTransactionResponse) Reset() {
+	*x = UpdateTransactionResponse{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTransactionResponse) ProtoMessage() {}
+
+func (x *UpdateTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTransactionResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateTransactionResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type DeleteTransactionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Integer id or uuid.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTransactionRequest) Reset() {
+	*x = DeleteTransactionRequest{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTransactionRequest) ProtoMessage() {}
+
+func (x *DeleteTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTransactionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteTransactionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTransactionResponse) Reset() {
+	*x = DeleteTransactionResponse{}
+	mi := &file_finance_v1_transaction_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTransactionResponse) ProtoMessage() {}
+
+func (x *DeleteTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finance_v1_transaction_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTransactionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_finance_v1_transaction_proto_rawDescGZIP(), []int{17}
+}
+
+var File_finance_v1_transaction_proto protoreflect.FileDescriptor
+
+const file_finance_v1_transaction_proto_rawDesc = "" +
+	"\n" +
+	"\x1cfinance/v1/transaction.proto\x12\n" +
+	"finance.v1\x1a\x15finance/v1/page.proto\x1a\x1cgoogle/api/annotations.proto\"\x8b\x04\n" +
+	"\vTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12/\n" +
+	"\x04user\x18\x03 \x01(\v2\x1b.finance.v1.TransactionUserR\x04user\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12;\n" +
+	"\bcategory\x18\x06 \x01(\v2\x1f.finance.v1.TransactionCategoryR\bcategory\x12\x12\n" +
+	"\x04note\x18\a \x01(\tR\x04note\x12\x14\n" +
+	"\x05payee\x18\b \x01(\tR\x05payee\x12\x1f\n" +
+	"\voccurred_at\x18\t \x01(\tR\n" +
+	"occurredAt\x12.\n" +
+	"\x04tags\x18\n" +
+	" \x03(\v2\x1a.finance.v1.TransactionTagR\x04tags\x12\x16\n" +
+	"\x06status\x18\v \x01(\tR\x06status\x120\n" +
+	"\x11reconciliation_id\x18\f \x01(\x03H\x00R\x10reconciliationId\x88\x01\x01\x12%\n" +
+	"\x0ebank_reference\x18\r \x01(\tR\rbankReference\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x0f \x01(\tR\tupdatedAtB\x14\n" +
+	"\x12_reconciliation_id\"I\n" +
+	"\x0fTransactionUser\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"M\n" +
+	"\x13TransactionCategory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"4\n" +
+	"\x0eTransactionTag\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\xc7\x03\n" +
+	"\x11TransactionFilter\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fcategory_ids\x18\x02 \x03(\tR\vcategoryIds\x120\n" +
+	"\x14exclude_category_ids\x18\x03 \x03(\tR\x12excludeCategoryIds\x12\x14\n" +
+	"\x05types\x18\x04 \x03(\tR\x05types\x12#\n" +
+	"\rexclude_types\x18\x05 \x03(\tR\fexcludeTypes\x12\"\n" +
+	"\n" +
+	"min_amount\x18\x06 \x01(\x01H\x00R\tminAmount\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"max_amount\x18\a \x01(\x01H\x01R\tmaxAmount\x88\x01\x01\x12\x16\n" +
+	"\x06search\x18\b \x01(\tR\x06search\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tags\x12!\n" +
+	"\fexclude_tags\x18\n" +
+	" \x03(\tR\vexcludeTags\x12\x1a\n" +
+	"\bstatuses\x18\v \x03(\tR\bstatuses\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\f \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\r \x01(\tR\aendDateB\r\n" +
+	"\v_min_amountB\r\n" +
+	"\v_max_amount\" \n" +
+	"\bTagNames\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\"\xda\x01\n" +
+	"\x18CreateTransactionRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1f\n" +
+	"\vcategory_id\x18\x04 \x01(\tR\n" +
+	"categoryId\x12\x12\n" +
+	"\x04note\x18\x05 \x01(\tR\x04note\x12\x14\n" +
+	"\x05payee\x18\x06 \x01(\tR\x05payee\x12\x1f\n" +
+	"\voccurred_at\x18\a \x01(\tR\n" +
+	"occurredAt\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\"V\n" +
+	"\x19CreateTransactionResponse\x129\n" +
+	"\vtransaction\x18\x01 \x01(\v2\x17.finance.v1.TransactionR\vtransaction\"'\n" +
+	"\x15GetTransactionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"S\n" +
+	"\x16GetTransactionResponse\x129\n" +
+	"\vtransaction\x18\x01 \x01(\v2\x17.finance.v1.TransactionR\vtransaction\"}\n" +
+	"\x17ListTransactionsRequest\x125\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1d.finance.v1.TransactionFilterR\x06filter\x12+\n" +
+	"\x04page\x18\x02 \x01(\v2\x17.finance.v1.PageRequestR\x04page\"\x8a\x01\n" +
+	"\x18ListTransactionsResponse\x12;\n" +
+	"\ftransactions\x18\x01 \x03(\v2\x17.finance.v1.TransactionR\ftransactions\x121\n" +
+	"\tpage_info\x18\x02 \x01(\v2\x14.finance.v1.PageInfoR\bpageInfo\"f\n" +
+	"\x19StreamTransactionsRequest\x125\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1d.finance.v1.TransactionFilterR\x06filter\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\"W\n" +
+	"\x1aStreamTransactionsResponse\x129\n" +
+	"\vtransaction\x18\x01 \x01(\v2\x17.finance.v1.TransactionR\vtransaction\"\xd1\x02\n" +
+	"\x18UpdateTransactionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\x06amount\x18\x02 \x01(\x01H\x00R\x06amount\x88\x01\x01\x12\x17\n" +
+	"\x04type\x18\x03 \x01(\tH\x01R\x04type\x88\x01\x01\x12$\n" +
+	"\vcategory_id\x18\x04 \x01(\tH\x02R\n" +
+	"categoryId\x88\x01\x01\x12\x17\n" +
+	"\x04note\x18\x05 \x01(\tH\x03R\x04note\x88\x01\x01\x12\x19\n" +
+	"\x05payee\x18\x06 \x01(\tH\x04R\x05payee\x88\x01\x01\x12$\n" +
+	"\voccurred_at\x18\a \x01(\tH\x05R\n" +
+	"occurredAt\x88\x01\x01\x12(\n" +
+	"\x04tags\x18\b \x01(\v2\x14.finance.v1.TagNamesR\x04tagsB\t\n" +
+	"\a_amountB\a\n" +
+	"\x05_typeB\x0e\n" +
+	"\f_category_idB\a\n" +
+	"\x05_noteB\b\n" +
+	"\x06_payeeB\x0e\n" +
+	"\f_occurred_at\"V\n" +
+	"\x19UpdateTransactionResponse\x129\n" +
+	"\vtransaction\x18\x01 \x01(\v2\x17.finance.v1.TransactionR\vtransaction\"*\n" +
+	"\x18DeleteTransactionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1b\n" +
+	"\x19DeleteTransactionResponse2\xad\x06\n" +
+	"\x12TransactionService\x12\x81\x01\n" +
+	"\x11CreateTransaction\x12$.finance.v1.CreateTransactionRequest\x1a%.finance.v1.CreateTransactionResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/api/v1/transactions\x12z\n" +
+	"\x0eGetTransaction\x12!.finance.v1.GetTransactionRequest\x1a\".finance.v1.GetTransactionResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/v1/transactions/{id}\x12{\n" +
+	"\x10ListTransactions\x12#.finance.v1.ListTransactionsRequest\x1a$.finance.v1.ListTransactionsResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/api/v1/transactions\x12\x8a\x01\n" +
+	"\x12StreamTransactions\x12%.finance.v1.StreamTransactionsRequest\x1a&.finance.v1.StreamTransactionsResponse\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/api/v1/transactions:stream0\x01\x12\x86\x01\n" +
+	"\x11UpdateTransaction\x12$.finance.v1.UpdateTransactionRequest\x1a%.finance.v1.UpdateTransactionResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*2\x19/api/v1/transactions/{id}\x12\x83\x01\n" +
+	"\x11DeleteTransaction\x12$.finance.v1.DeleteTransactionRequest\x1a%.finance.v1.DeleteTransactionResponse\"!\x82\xd3\xe4\x93\x02\x1b*\x19/api/v1/transactions/{id}B(Z&go-crud-api/proto/finance/v1;financev1b\x06proto3"
+
+var (
+	file_finance_v1_transaction_proto_rawDescOnce sync.Once
+	file_finance_v1_transaction_proto_rawDescData []byte
+)
+
+func file_finance_v1_transaction_proto_rawDescGZIP() []byte {
+	file_finance_v1_transaction_proto_rawDescOnce.Do(func() {
+		file_finance_v1_transaction_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_finance_v1_transaction_proto_rawDesc), len(file_finance_v1_transaction_proto_rawDesc)))
+	})
+	return file_finance_v1_transaction_proto_rawDescData
+}
+
+var file_finance_v1_transaction_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_finance_v1_transaction_proto_goTypes = []any{
+	(*Transaction)(nil),                // 0: finance.v1.Transaction
+	(*TransactionUser)(nil),            // 1: finance.v1.TransactionUser
+	(*TransactionCategory)(nil),        // 2: finance.v1.TransactionCategory
+	(*TransactionTag)(nil),             // 3: finance.v1.TransactionTag
+	(*TransactionFilter)(nil),          // 4: finance.v1.TransactionFilter
+	(*TagNames)(nil),                   // 5: finance.v1.TagNames
+	(*CreateTransactionRequest)(nil),   // 6: finance.v1.CreateTransactionRequest
+	(*CreateTransactionResponse)(nil),  // 7: finance.v1.CreateTransactionResponse
+	(*GetTransactionRequest)(nil),      // 8: finance.v1.GetTransactionRequest
+	(*GetTransactionResponse)(nil),     // 9: finance.v1.GetTransactionResponse
+	(*ListTransactionsRequest)(nil),    // 10: finance.v1.ListTransactionsRequest
+	(*ListTransactionsResponse)(nil),   // 11: finance.v1.ListTransactionsResponse
+	(*StreamTransactionsRequest)(nil),  // 12: finance.v1.StreamTransactionsRequest
+	(*StreamTransactionsResponse)(nil), // 13: finance.v1.StreamTransactionsResponse
+	(*UpdateTransactionRequest)(nil),   // 14: finance.v1.UpdateTransactionRequest
+	(*UpdateTransactionResponse)(nil),  // 15: finance.v1.UpdateTransactionResponse
+	(*DeleteTransactionRequest)(nil),   // 16: finance.v1.DeleteTransactionRequest
+	(*DeleteTransactionResponse)(nil),  // 17: finance.v1.DeleteTransactionResponse
+	(*PageRequest)(nil),                // 18: finance.v1.PageRequest
+	(*PageInfo)(nil),                   // 19: finance.v1.PageInfo
+}
+var file_finance_v1_transaction_proto_depIdxs = []int32{
+	1,  // 0: finance.v1.Transaction.user:type_name -> finance.v1.TransactionUser
+	2,  // 1: finance.v1.Transaction.category:type_name -> finance.v1.TransactionCategory
+	3,  // 2: finance.v1.Transaction.tags:type_name -> finance.v1.TransactionTag
+	0,  // 3: finance.v1.CreateTransactionResponse.transaction:type_name -> finance.v1.Transaction
+	0,  // 4: finance.v1.GetTransactionResponse.transaction:type_name -> finance.v1.Transaction
+	4,  // 5: finance.v1.ListTransactionsRequest.filter:type_name -> finance.v1.TransactionFilter
+	18, // 6: finance.v1.ListTransactionsRequest.page:type_name -> finance.v1.PageRequest
+	0,  // 7: finance.v1.ListTransactionsResponse.transactions:type_name -> finance.v1.Transaction
+	19, // 8: finance.v1.ListTransactionsResponse.page_info:type_name -> finance.v1.PageInfo
+	4,  // 9: finance.v1.StreamTransactionsRequest.filter:type_name -> finance.v1.TransactionFilter
+	0,  // 10: finance.v1.StreamTransactionsResponse.transaction:type_name -> finance.v1.Transaction
+	5,  // 11: finance.v1.UpdateTransactionRequest.tags:type_name -> finance.v1.TagNames
+	0,  // 12: finance.v1.UpdateTransactionResponse.transaction:type_name -> finance.v1.Transaction
+	6,  // 13: finance.v1.TransactionService.CreateTransaction:input_type -> finance.v1.CreateTransactionRequest
+	8,  // 14: finance.v1.TransactionService.GetTransaction:input_type -> finance.v1.GetTransactionRequest
+	10, // 15: finance.v1.TransactionService.ListTransactions:input_type -> finance.v1.ListTransactionsRequest
+	12, // 16: finance.v1.TransactionService.StreamTransactions:input_type -> finance.v1.StreamTransactionsRequest
+	14, // 17: finance.v1.TransactionService.UpdateTransaction:input_type -> finance.v1.UpdateTransactionRequest
+	16, // 18: finance.v1.TransactionService.DeleteTransaction:input_type -> finance.v1.DeleteTransactionRequest
+	7,  // 19: finance.v1.TransactionService.CreateTransaction:output_type -> finance.v1.CreateTransactionResponse
+	9,  // 20: finance.v1.TransactionService.GetTransaction:output_type -> finance.v1.GetTransactionResponse
+	11, // 21: finance.v1.TransactionService.ListTransactions:output_type -> finance.v1.ListTransactionsResponse
+	13, // 22: finance.v1.TransactionService.StreamTransactions:output_type -> finance.v1.StreamTransactionsResponse
+	15, // 23: finance.v1.TransactionService.UpdateTransaction:output_type -> finance.v1.UpdateTransactionResponse
+	17, // 24: finance.v1.TransactionService.DeleteTransaction:output_type -> finance.v1.DeleteTransactionResponse
+	19, // [19:25] is the sub-list for method output_type
+	13, // [13:19] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_finance_v1_transaction_proto_init() }
+func file_finance_v1_transaction_proto_init() {
+	if File_finance_v1_transaction_proto != nil {
+		return
+	}
+	file_finance_v1_page_proto_init()
+	file_finance_v1_transaction_proto_msgTypes[0].OneofWrappers = []any{}
+	file_finance_v1_transaction_proto_msgTypes[4].OneofWrappers = []any{}
+	file_finance_v1_transaction_proto_msgTypes[14].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_finance_v1_transaction_proto_rawDesc), len(file_finance_v1_transaction_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_finance_v1_transaction_proto_goTypes,
+		DependencyIndexes: file_finance_v1_transaction_proto_depIdxs,
+		MessageInfos:      file_finance_v1_transaction_proto_msgTypes,
+	}.Build()
+	File_finance_v1_transaction_proto = out.File
+	file_finance_v1_transaction_proto_goTypes = nil
+	file_finance_v1_transaction_proto_depIdxs = nil
+}
@@ -0,0 +1,201 @@
+// Package realtime streams the domain events of the outbox to connected
+// clients.
+package realtime
+
+import (
+	"encoding/json"
+	"go-crud-api/models"
+	"go-crud-api/repository"
+	"go-crud-api/services"
+	"slices"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// EventBalanceChanged follows every transaction event so dashboards
+	// know to reload the balance of the user.
+	EventBalanceChanged = "balance.changed"
+	// EventReset tells the client it fell too far behind to be replayed and
+	// must reload its state.
+	EventReset = "reset"
+
+	// clientBuffer is how many events a connection may fall behind before
+	// it is dropped
+	clientBuffer = 64
+	// maxReplay is how many events are replayed on reconnect at most
+	maxReplay = 500
+	// pollBatchSize events are read from the outbox per query
+	pollBatchSize = 100
+)
+
+// streamedTypes are the domain events clients are told about.
+var streamedTypes = []string{
+	services.EventTransactionCreated, services.EventTransactionUpdated, services.EventTransactionDeleted,
+	services.EventCategoryCreated, services.EventCategoryUpdated, services.EventCategoryDeleted,
+}
+
+// Event is one message of the stream. Its id is the sequence of the
+// domain event it comes from, so a reconnecting client can resume after it.
+type Event struct {
+	Id   int64           `json:"id"`
+	Type string          `json:"event"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Client is one open stream. It receives the events of its user, the
+// shared ones, and with Admin the events of every user.
+type Client struct {
+	UserId  int
+	Admin   bool
+	events  chan []Event
+	dropped chan struct{}
+}
+
+// Events delivers the events of one domain event at a time.
+func (c *Client) Events() <-chan []Event {
+	return c.events
+}
+
+// Dropped is closed when the client fell clientBuffer events behind. The
+// stream should end so the client reconnects and catches up by replay.
+func (c *Client) Dropped() <-chan struct{} {
+	return c.dropped
+}
+
+func (c *Client) receives(event models.DomainEvent) bool {
+	return c.Admin || event.UserId == 0 || event.UserId == c.UserId
+}
+
+// Broker follows the outbox and fans its events out to the clients
+// connected to this API instance. Every instance runs its own broker, so
+// clients get the events wherever they are connected.
+type Broker struct {
+	Repository repository.Repository
+	Db         *gorm.DB
+	mu         sync.Mutex
+	clients    map[*Client]struct{}
+	sequence   int64
+	started    bool
+}
+
+func NewBroker(repository repository.Repository, db *gorm.DB) *Broker {
+	return &Broker{Repository: repository, Db: db, clients: map[*Client]struct{}{}}
+}
+
+// Subscribe opens a stream for the user.
+func (b *Broker) Subscribe(user models.User) *Client {
+	client := &Client{
+		UserId:  user.Id,
+		Admin:   user.Role == "admin",
+		events:  make(chan []Event, clientBuffer),
+		dropped: make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clients[client] = struct{}{}
+	return client
+}
+
+func (b *Broker) Unsubscribe(client *Client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.clients, client)
+}
+
+// Replay returns the client's events after the sequence, grouped per domain
+// event. When there are more than maxReplay it returns a single reset at the
+// latest sequence instead.
+func (b *Broker) Replay(client *Client, sequence int64) (batches [][]Event, err error) {
+	userId := client.UserId
+	if client.Admin {
+		userId = 0
+	}
+
+	events, err := b.Repository.GetUserDomainEventsAfter(b.Db, sequence, userId, streamedTypes, maxReplay+1)
+	if err != nil {
+		return
+	}
+
+	if len(events) > maxReplay {
+		latest, errLatest := b.Repository.GetLatestEventSequence(b.Db)
+		if errLatest != nil {
+			err = errLatest
+			return
+		}
+		batches = [][]Event{{{Id: latest, Type: EventReset, Data: json.RawMessage("{}")}}}
+		return
+	}
+
+	for _, event := range events {
+		batches = append(batches, toStreamEvents(event))
+	}
+	return
+}
+
+// Poll hands the events sequenced since the last poll to the connected
+// clients. It is run by the scheduler.
+func (b *Broker) Poll() (err error) {
+	b.mu.Lock()
+	connected := len(b.clients)
+	b.mu.Unlock()
+
+	// Nobody to tell, and reconnecting clients catch up by replay
+	if connected == 0 || !b.started {
+		b.sequence, err = b.Repository.GetLatestEventSequence(b.Db)
+		b.started = err == nil
+		return
+	}
+
+	for {
+		events, errEvents := b.Repository.GetDomainEventsAfter(b.Db, b.sequence, pollBatchSize)
+		if errEvents != nil {
+			return errEvents
+		}
+
+		for _, event := range events {
+			b.publish(event)
+			b.sequence = *event.Sequence
+		}
+
+		if len(events) < pollBatchSize {
+			return
+		}
+	}
+}
+
+// publish gives the event to every client receiving it without waiting. A
+// client whose buffer is full is dropped rather than holding up the others.
+func (b *Broker) publish(event models.DomainEvent) {
+	if !slices.Contains(streamedTypes, event.Type) {
+		return
+	}
+	batch := toStreamEvents(event)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for client := range b.clients {
+		if !client.receives(event) {
+			continue
+		}
+
+		select {
+		case client.events <- batch:
+		default:
+			delete(b.clients, client)
+			close(client.dropped)
+		}
+	}
+}
+
+func toStreamEvents(event models.DomainEvent) []Event {
+	batch := []Event{{Id: *event.Sequence, Type: event.Type, Data: event.Payload}}
+	if strings.HasPrefix(event.Type, "transaction.") {
+		data, _ := json.Marshal(map[string]int{"user_id": event.UserId})
+		batch = append(batch, Event{Id: *event.Sequence, Type: EventBalanceChanged, Data: data})
+	}
+	return batch
+}
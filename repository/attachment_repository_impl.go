@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) CreateAttachment(db *gorm.DB, attachment models.Attachment) (models.Attachment, error) {
+	err := db.Create(&attachment).Error
+	return attachment, err
+}
+
+func (r *repository) GetAttachments(db *gorm.DB, transactionId int) (attachments []models.Attachment, err error) {
+	err = db.Where("transaction_id = ?", transactionId).Order("created_at ASC").Find(&attachments).Error
+	return
+}
+
+func (r *repository) GetAttachmentById(db *gorm.DB, id int) (attachment models.Attachment, err error) {
+	err = db.Where("id = ?", id).First(&attachment).Error
+	return
+}
+
+func (r *repository) DeleteAttachment(db *gorm.DB, id int) (err error) {
+	err = db.Where("id = ?", id).Delete(&models.Attachment{}).Error
+	return
+}
+
+func (r *repository) DeleteAttachmentsByTransaction(db *gorm.DB, transactionId int) (err error) {
+	err = db.Where("transaction_id = ?", transactionId).Delete(&models.Attachment{}).Error
+	return
+}
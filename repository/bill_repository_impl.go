@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) CreateBill(db *gorm.DB, bill models.Bill) (models.Bill, error) {
+	err := db.Create(&bill).Error
+	return bill, err
+}
+
+func (r *repository) GetBills(db *gorm.DB, userId int) (bills []models.Bill, err error) {
+	err = db.Where("user_id = ?", userId).Order("first_due_date ASC").Order("id ASC").Find(&bills).Error
+	return
+}
+
+func (r *repository) GetBillById(db *gorm.DB, id int) (bill models.Bill, err error) {
+	err = db.Where("id = ?", id).First(&bill).Error
+	return
+}
+
+func (r *repository) UpdateBill(db *gorm.DB, bill models.Bill) (err error) {
+	err = db.Save(&bill).Error
+	return
+}
+
+func (r *repository) DeleteBill(db *gorm.DB, id int) (err error) {
+	err = db.Where("bill_id = ?", id).Delete(&models.BillPayment{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("id = ?", id).Delete(&models.Bill{}).Error
+	return
+}
+
+func (r *repository) CreateBillPayment(db *gorm.DB, payment models.BillPayment) (models.BillPayment, error) {
+	err := db.Create(&payment).Error
+	return payment, err
+}
+
+// GetBillPayments returns the payments of the bills for occurrences due
+// between startDate and endDate (YYYY-MM-DD, inclusive).
+func (r *repository) GetBillPayments(db *gorm.DB, billIds []int, startDate string, endDate string) (payments []models.BillPayment, err error) {
+	if len(billIds) == 0 {
+		return
+	}
+	err = db.Where("bill_id IN ?", billIds).
+		Where("due_date >= ? AND due_date <= ?", startDate, endDate).
+		Order("due_date ASC").
+		Find(&payments).Error
+	return
+}
+
+func (r *repository) GetBillPaymentById(db *gorm.DB, id int) (payment models.BillPayment, err error) {
+	err = db.Where("id = ?", id).First(&payment).Error
+	return
+}
+
+func (r *repository) FindBillPayment(db *gorm.DB, billId int, dueDate string) (payment models.BillPayment, err error) {
+	err = db.Where("bill_id = ? AND due_date = ?", billId, dueDate).First(&payment).Error
+	return
+}
+
+func (r *repository) FindBillPaymentByTransaction(db *gorm.DB, transactionId int) (payment models.BillPayment, err error) {
+	err = db.Where("transaction_id = ?", transactionId).First(&payment).Error
+	return
+}
+
+func (r *repository) DeleteBillPayment(db *gorm.DB, id int) (err error) {
+	err = db.Where("id = ?", id).Delete(&models.BillPayment{}).Error
+	return
+}
+
+// SaveCalendarFeed replaces the user's feed, revoking the previous token.
+func (r *repository) SaveCalendarFeed(db *gorm.DB, feed models.CalendarFeed) (err error) {
+	err = db.Where("user_id = ?", feed.UserId).Delete(&models.CalendarFeed{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Create(&feed).Error
+	return
+}
+
+func (r *repository) FindCalendarFeedByTokenHash(db *gorm.DB, tokenHash string) (feed models.CalendarFeed, err error) {
+	err = db.Where("token_hash = ?", tokenHash).First(&feed).Error
+	return
+}
+
+func (r *repository) DeleteCalendarFeed(db *gorm.DB, userId int) (err error) {
+	err = db.Where("user_id = ?", userId).Delete(&models.CalendarFeed{}).Error
+	return
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) CreateDebt(db *gorm.DB, debt models.Debt) (models.Debt, error) {
+	err := db.Create(&debt).Error
+	return debt, err
+}
+
+func (r *repository) GetDebts(db *gorm.DB, userId int) (debts []models.Debt, err error) {
+	err = db.Where("user_id = ?", userId).Order("start_date DESC").Order("id DESC").Find(&debts).Error
+	return
+}
+
+func (r *repository) GetDebtById(db *gorm.DB, id int) (debt models.Debt, err error) {
+	err = db.Where("id = ?", id).First(&debt).Error
+	return
+}
+
+func (r *repository) UpdateDebt(db *gorm.DB, debt models.Debt) (err error) {
+	err = db.Save(&debt).Error
+	return
+}
+
+func (r *repository) DeleteDebt(db *gorm.DB, id int) (err error) {
+	err = db.Where("debt_id = ?", id).Delete(&models.DebtPayment{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("id = ?", id).Delete(&models.Debt{}).Error
+	return
+}
+
+func (r *repository) CreateDebtPayment(db *gorm.DB, payment models.DebtPayment) (models.DebtPayment, error) {
+	err := db.Create(&payment).Error
+	return payment, err
+}
+
+func (r *repository) GetDebtPayments(db *gorm.DB, debtId int) (payments []models.DebtPayment, err error) {
+	err = db.Where("debt_id = ?", debtId).Order("paid_at ASC").Order("id ASC").Find(&payments).Error
+	return
+}
+
+func (r *repository) GetDebtPaymentById(db *gorm.DB, id int) (payment models.DebtPayment, err error) {
+	err = db.Where("id = ?", id).First(&payment).Error
+	return
+}
+
+func (r *repository) FindDebtPaymentByTransaction(db *gorm.DB, transactionId int) (payment models.DebtPayment, err error) {
+	err = db.Where("transaction_id = ?", transactionId).First(&payment).Error
+	return
+}
+
+func (r *repository) DeleteDebtPayment(db *gorm.DB, id int) (err error) {
+	err = db.Where("id = ?", id).Delete(&models.DebtPayment{}).Error
+	return
+}
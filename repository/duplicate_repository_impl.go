@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"go-crud-api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FindDuplicatePairs pairs the user's transactions that share amount, type
+// and category and happened within window of each other. Dismissed pairs
+// are left out.
+func (r *repository) FindDuplicatePairs(db *gorm.DB, userId int, window time.Duration, startDate string, endDate string) (pairs []models.DuplicatePair, err error) {
+	err = db.Table("transactions AS a").
+		Select("a.id AS transaction_id, b.id AS duplicate_id").
+		Joins("JOIN transactions AS b ON b.user_id = a.user_id AND b.amount = a.amount AND b.type = a.type AND b.category_id = a.category_id AND b.id > a.id").
+		Where("a.user_id = ?", userId).
+		Where("ABS(EXTRACT(EPOCH FROM (b.occurred_at - a.occurred_at))) <= ?", window.Seconds()).
+		Where("DATE(a.occurred_at) BETWEEN ? AND ?", startDate, endDate).
+		Where("NOT EXISTS (SELECT 1 FROM duplicate_dismissals AS d WHERE d.transaction_id = a.id AND d.duplicate_id = b.id)").
+		Order("a.occurred_at DESC").Order("a.id").Order("b.id").
+		Scan(&pairs).Error
+	return
+}
+
+// FindSimilarTransactions returns the user's transactions with the same
+// amount, type and category that happened between from and to.
+func (r *repository) FindSimilarTransactions(db *gorm.DB, userId int, amount float64, transactionType string, categoryId int, from time.Time, to time.Time) (transactions []models.Transaction, err error) {
+	err = db.Where("user_id = ? AND amount = ? AND type = ? AND category_id = ?", userId, amount, transactionType, categoryId).
+		Where("occurred_at BETWEEN ? AND ?", from, to).
+		Order("occurred_at").
+		Find(&transactions).Error
+	return
+}
+
+func (r *repository) GetTransactionsByIds(db *gorm.DB, ids []int) (transactions []models.Transaction, err error) {
+	err = db.Preload("User").Preload("Category").Preload("Tags").Where("id IN ?", ids).Find(&transactions).Error
+	return
+}
+
+func (r *repository) FindTransactionByBankReference(db *gorm.DB, userId int, reference string) (transaction models.Transaction, err error) {
+	err = db.Where("user_id = ? AND bank_reference = ?", userId, reference).First(&transaction).Error
+	return
+}
+
+func (r *repository) CreateDuplicateDismissal(db *gorm.DB, dismissal models.DuplicateDismissal) (models.DuplicateDismissal, error) {
+	err := db.Where(models.DuplicateDismissal{TransactionId: dismissal.TransactionId, DuplicateId: dismissal.DuplicateId}).
+		FirstOrCreate(&dismissal).Error
+	return dismissal, err
+}
+
+func (r *repository) MoveAttachments(db *gorm.DB, fromTransactionId int, toTransactionId int) (err error) {
+	err = db.Model(&models.Attachment{}).Where("transaction_id = ?", fromTransactionId).Update("transaction_id", toTransactionId).Error
+	return
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) GetEnvelopeCategories(db *gorm.DB, userId int) (envelopes []models.EnvelopeCategory, err error) {
+	err = db.Preload("Category").Where("user_id = ?", userId).Order("category_id ASC").Find(&envelopes).Error
+	return
+}
+
+// SaveEnvelopeCategory inserts the envelope or updates the rollover of the
+// existing one.
+func (r *repository) SaveEnvelopeCategory(db *gorm.DB, envelope models.EnvelopeCategory) (models.EnvelopeCategory, error) {
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "category_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rollover", "updated_at"}),
+	}).Omit("Category").Create(&envelope).Error
+	if err != nil {
+		return envelope, err
+	}
+	err = db.Preload("Category").Where("user_id = ? AND category_id = ?", envelope.UserId, envelope.CategoryId).First(&envelope).Error
+	return envelope, err
+}
+
+// DeleteEnvelopeCategory removes the envelope and all of its assignments.
+func (r *repository) DeleteEnvelopeCategory(db *gorm.DB, userId int, categoryId int) (err error) {
+	err = db.Where("user_id = ? AND category_id = ?", userId, categoryId).Delete(&models.EnvelopeAssignment{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("user_id = ? AND category_id = ?", userId, categoryId).Delete(&models.EnvelopeCategory{}).Error
+	return
+}
+
+// GetEnvelopeAssignments returns the user's assignments for cycles starting
+// on or before until (YYYY-MM-DD).
+func (r *repository) GetEnvelopeAssignments(db *gorm.DB, userId int, until string) (assignments []models.EnvelopeAssignment, err error) {
+	err = db.Where("user_id = ? AND cycle_start <= ?", userId, until).Order("cycle_start ASC").Find(&assignments).Error
+	return
+}
+
+// SaveEnvelopeAssignment inserts the assignment or replaces the amount of
+// the same envelope and cycle.
+func (r *repository) SaveEnvelopeAssignment(db *gorm.DB, assignment models.EnvelopeAssignment) (models.EnvelopeAssignment, error) {
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "category_id"}, {Name: "cycle_start"}},
+		DoUpdates: clause.AssignmentColumns([]string{"amount", "updated_at"}),
+	}).Create(&assignment).Error
+	if err != nil {
+		return assignment, err
+	}
+	err = db.Where("user_id = ? AND category_id = ? AND cycle_start = ?", assignment.UserId, assignment.CategoryId, assignment.CycleStart.Format("2006-01-02")).First(&assignment).Error
+	return assignment, err
+}
+
+// SumTransactionsByCategory totals the user's transactions between the
+// dates (YYYY-MM-DD, inclusive) per category and type.
+func (r *repository) SumTransactionsByCategory(db *gorm.DB, userId int, startDate string, endDate string) (totals []models.CategoryTotal, err error) {
+	err = db.Model(&models.Transaction{}).
+		Select("category_id, type, COALESCE(SUM(amount), 0) AS total").
+		Where("user_id = ?", userId).
+		Where("DATE(occurred_at) >= ? AND DATE(occurred_at) <= ?", startDate, endDate).
+		Group("category_id, type").
+		Scan(&totals).Error
+	return
+}
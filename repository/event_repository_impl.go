@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// eventDispatchLock is the Postgres advisory lock key held while events
+// are sequenced and dispatched, so only one API instance does it at a time.
+const eventDispatchLock = 4501
+
+func (r *repository) CreateDomainEvent(db *gorm.DB, event models.DomainEvent) (err error) {
+	err = db.Create(&event).Error
+	return
+}
+
+// TryLockEventDispatch takes the dispatch lock for the rest of the DB
+// transaction db, or reports that another instance holds it.
+func (r *repository) TryLockEventDispatch(db *gorm.DB) (locked bool, err error) {
+	err = db.Raw("SELECT pg_try_advisory_xact_lock(?)", eventDispatchLock).Scan(&locked).Error
+	return
+}
+
+// SequenceDomainEvents numbers the committed events that have no sequence
+// yet, in id order after the highest sequence so far. Events committed late
+// get a later sequence than events already handed out, so subscribers never
+// skip them. Callers must hold the dispatch lock.
+func (r *repository) SequenceDomainEvents(db *gorm.DB) (err error) {
+	err = db.Exec(`UPDATE domain_events SET sequence = numbered.sequence
+		FROM (
+			SELECT id, (SELECT COALESCE(MAX(sequence), 0) FROM domain_events) + ROW_NUMBER() OVER (ORDER BY id) AS sequence
+			FROM domain_events WHERE sequence IS NULL
+		) AS numbered
+		WHERE domain_events.id = numbered.id`).Error
+	return
+}
+
+func (r *repository) GetLatestEventSequence(db *gorm.DB) (sequence int64, err error) {
+	err = db.Model(&models.DomainEvent{}).Select("COALESCE(MAX(sequence), 0)").Scan(&sequence).Error
+	return
+}
+
+func (r *repository) GetDomainEventsAfter(db *gorm.DB, sequence int64, limit int) (events []models.DomainEvent, err error) {
+	err = db.Where("sequence > ?", sequence).Order("sequence ASC").Limit(limit).Find(&events).Error
+	return
+}
+
+// GetUserDomainEventsAfter is GetDomainEventsAfter limited to the types and
+// to the events of the user plus the shared ones. A userId of 0 keeps the
+// events of every user.
+func (r *repository) GetUserDomainEventsAfter(db *gorm.DB, sequence int64, userId int, types []string, limit int) (events []models.DomainEvent, err error) {
+	query := db.Where("sequence > ? AND type IN ?", sequence, types)
+	if userId != 0 {
+		query = query.Where("user_id IN ?", []int{0, userId})
+	}
+
+	err = query.Order("sequence ASC").Limit(limit).Find(&events).Error
+	return
+}
+
+func (r *repository) GetDomainEvents(db *gorm.DB, eventType string, pagination models.QueryPagination) (count int64, events []models.DomainEvent, err error) {
+	query := db.Model(&models.DomainEvent{})
+
+	if eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+
+	err = query.Count(&count).Error
+	if err != nil {
+		return
+	}
+
+	err = paginate(query, pagination).Find(&events).Error
+	return
+}
+
+func (r *repository) GetEventOffsets(db *gorm.DB) (offsets []models.EventOffset, err error) {
+	err = db.Order("subscriber ASC").Find(&offsets).Error
+	return
+}
+
+func (r *repository) GetEventOffset(db *gorm.DB, subscriber string) (offset models.EventOffset, err error) {
+	err = db.Where("subscriber = ?", subscriber).First(&offset).Error
+	return
+}
+
+// InitEventOffset starts tracking the subscriber at sequence unless it is
+// already tracked.
+func (r *repository) InitEventOffset(db *gorm.DB, subscriber string, sequence int64) (err error) {
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subscriber"}},
+		DoNothing: true,
+	}).Create(&models.EventOffset{Subscriber: subscriber, Sequence: sequence}).Error
+	return
+}
+
+func (r *repository) SaveEventOffset(db *gorm.DB, subscriber string, sequence int64) (err error) {
+	err = db.Model(&models.EventOffset{}).Where("subscriber = ?", subscriber).Update("sequence", sequence).Error
+	return
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"go-crud-api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) CreateGoal(db *gorm.DB, goal models.Goal) (models.Goal, error) {
+	err := db.Create(&goal).Error
+	return goal, err
+}
+
+func (r *repository) GetGoals(db *gorm.DB, userId int) (goals []models.Goal, err error) {
+	err = db.Where("user_id = ?", userId).Order("created_at ASC").Order("id ASC").Find(&goals).Error
+	return
+}
+
+func (r *repository) GetGoalById(db *gorm.DB, id int) (goal models.Goal, err error) {
+	err = db.Where("id = ?", id).First(&goal).Error
+	return
+}
+
+func (r *repository) UpdateGoal(db *gorm.DB, goal models.Goal) (err error) {
+	err = db.Save(&goal).Error
+	return
+}
+
+func (r *repository) DeleteGoal(db *gorm.DB, id int) (err error) {
+	err = db.Where("goal_id = ?", id).Delete(&models.GoalContribution{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("id = ?", id).Delete(&models.Goal{}).Error
+	return
+}
+
+func (r *repository) CreateGoalContribution(db *gorm.DB, contribution models.GoalContribution) (models.GoalContribution, error) {
+	err := db.Create(&contribution).Error
+	return contribution, err
+}
+
+func (r *repository) GetGoalContributions(db *gorm.DB, goalId int) (contributions []models.GoalContribution, err error) {
+	err = db.Where("goal_id = ?", goalId).Order("contributed_at DESC").Order("id DESC").Find(&contributions).Error
+	return
+}
+
+func (r *repository) GetGoalContributionById(db *gorm.DB, id int) (contribution models.GoalContribution, err error) {
+	err = db.Where("id = ?", id).First(&contribution).Error
+	return
+}
+
+func (r *repository) DeleteGoalContribution(db *gorm.DB, id int) (err error) {
+	err = db.Where("id = ?", id).Delete(&models.GoalContribution{}).Error
+	return
+}
+
+// SumGoalContributions adds up the manual contributions of a goal, only
+// those made at or after since when it is set.
+func (r *repository) SumGoalContributions(db *gorm.DB, goalId int, since *time.Time) (total float64, err error) {
+	query := db.Model(&models.GoalContribution{}).Where("goal_id = ?", goalId)
+	if since != nil {
+		query = query.Where("contributed_at >= ?", *since)
+	}
+	err = query.Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	return
+}
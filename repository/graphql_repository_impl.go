@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) FindUsersByIds(db *gorm.DB, ids []int) (users []models.User, err error) {
+	err = db.Where("id IN ?", ids).Find(&users).Error
+	return
+}
+
+func (r *repository) GetCategoriesByIds(db *gorm.DB, ids []int) (categories []models.Category, err error) {
+	err = db.Where("id IN ?", ids).Find(&categories).Error
+	return
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) CreatePortfolio(db *gorm.DB, portfolio models.Portfolio) (models.Portfolio, error) {
+	err := db.Create(&portfolio).Error
+	return portfolio, err
+}
+
+func (r *repository) GetPortfolios(db *gorm.DB, userId int) (portfolios []models.Portfolio, err error) {
+	err = db.Where("user_id = ?", userId).Order("name ASC").Order("id ASC").Find(&portfolios).Error
+	return
+}
+
+func (r *repository) GetPortfolioById(db *gorm.DB, id int) (portfolio models.Portfolio, err error) {
+	err = db.Where("id = ?", id).First(&portfolio).Error
+	return
+}
+
+func (r *repository) UpdatePortfolio(db *gorm.DB, portfolio models.Portfolio) (err error) {
+	err = db.Save(&portfolio).Error
+	return
+}
+
+// DeletePortfolio removes the portfolio with its holdings and activities.
+func (r *repository) DeletePortfolio(db *gorm.DB, id int) (err error) {
+	holdingIds := db.Model(&models.Holding{}).Select("id").Where("portfolio_id = ?", id)
+	err = db.Where("holding_id IN (?)", holdingIds).Delete(&models.InvestmentActivity{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("portfolio_id = ?", id).Delete(&models.Holding{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("id = ?", id).Delete(&models.Portfolio{}).Error
+	return
+}
+
+func (r *repository) CreateHolding(db *gorm.DB, holding models.Holding) (models.Holding, error) {
+	err := db.Create(&holding).Error
+	return holding, err
+}
+
+func (r *repository) GetHoldings(db *gorm.DB, portfolioId int) (holdings []models.Holding, err error) {
+	err = db.Where("portfolio_id = ?", portfolioId).Order("symbol ASC").Find(&holdings).Error
+	return
+}
+
+func (r *repository) GetHoldingById(db *gorm.DB, id int) (holding models.Holding, err error) {
+	err = db.Where("id = ?", id).First(&holding).Error
+	return
+}
+
+func (r *repository) FindHoldingBySymbol(db *gorm.DB, portfolioId int, symbol string) (holding models.Holding, err error) {
+	err = db.Where("portfolio_id = ? AND symbol = ?", portfolioId, symbol).First(&holding).Error
+	return
+}
+
+func (r *repository) DeleteHolding(db *gorm.DB, id int) (err error) {
+	err = db.Where("holding_id = ?", id).Delete(&models.InvestmentActivity{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("id = ?", id).Delete(&models.Holding{}).Error
+	return
+}
+
+func (r *repository) CreateInvestmentActivity(db *gorm.DB, activity models.InvestmentActivity) (models.InvestmentActivity, error) {
+	err := db.Create(&activity).Error
+	return activity, err
+}
+
+// GetInvestmentActivities returns the activities of the holdings in the
+// order they happened, up to until (YYYY-MM-DD) when it is set.
+func (r *repository) GetInvestmentActivities(db *gorm.DB, holdingIds []int, until string) (activities []models.InvestmentActivity, err error) {
+	if len(holdingIds) == 0 {
+		return
+	}
+	query := db.Where("holding_id IN ?", holdingIds)
+	if until != "" {
+		query = query.Where("date <= ?", until)
+	}
+	err = query.Order("date ASC").Order("id ASC").Find(&activities).Error
+	return
+}
+
+func (r *repository) GetInvestmentActivityById(db *gorm.DB, id int) (activity models.InvestmentActivity, err error) {
+	err = db.Where("id = ?", id).First(&activity).Error
+	return
+}
+
+func (r *repository) DeleteInvestmentActivity(db *gorm.DB, id int) (err error) {
+	err = db.Where("id = ?", id).Delete(&models.InvestmentActivity{}).Error
+	return
+}
+
+// SaveInstrumentPrice inserts the price or replaces the one of the same
+// symbol and day.
+func (r *repository) SaveInstrumentPrice(db *gorm.DB, price models.InstrumentPrice) (models.InstrumentPrice, error) {
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "symbol"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"price", "updated_at"}),
+	}).Create(&price).Error
+	if err != nil {
+		return price, err
+	}
+	err = db.Where("user_id = ? AND symbol = ? AND date = ?", price.UserId, price.Symbol, price.Date.Format("2006-01-02")).First(&price).Error
+	return price, err
+}
+
+func (r *repository) GetInstrumentPrices(db *gorm.DB, userId int, symbol string, startDate string, endDate string) (prices []models.InstrumentPrice, err error) {
+	query := db.Where("user_id = ?", userId)
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	if startDate != "" {
+		query = query.Where("date >= ?", startDate)
+	}
+	if endDate != "" {
+		query = query.Where("date <= ?", endDate)
+	}
+	err = query.Order("symbol ASC").Order("date DESC").Find(&prices).Error
+	return
+}
+
+func (r *repository) GetInstrumentPriceById(db *gorm.DB, id int) (price models.InstrumentPrice, err error) {
+	err = db.Where("id = ?", id).First(&price).Error
+	return
+}
+
+// GetLatestInstrumentPrices returns, for each symbol, the most recent price
+// on or before asOf (YYYY-MM-DD).
+func (r *repository) GetLatestInstrumentPrices(db *gorm.DB, userId int, symbols []string, asOf string) (prices []models.InstrumentPrice, err error) {
+	if len(symbols) == 0 {
+		return
+	}
+	err = db.Raw(`
+		SELECT DISTINCT ON (symbol) *
+		FROM instrument_prices
+		WHERE user_id = ? AND symbol IN ? AND date <= ?
+		ORDER BY symbol, date DESC`, userId, symbols, asOf).
+		Scan(&prices).Error
+	return
+}
+
+func (r *repository) DeleteInstrumentPrice(db *gorm.DB, id int) (err error) {
+	err = db.Where("id = ?", id).Delete(&models.InstrumentPrice{}).Error
+	return
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) CreateAsset(db *gorm.DB, asset models.Asset) (models.Asset, error) {
+	err := db.Create(&asset).Error
+	return asset, err
+}
+
+func (r *repository) GetAssets(db *gorm.DB, userId int) (assets []models.Asset, err error) {
+	err = db.Where("user_id = ?", userId).Order("kind ASC").Order("name ASC").Order("id ASC").Find(&assets).Error
+	return
+}
+
+func (r *repository) GetAssetById(db *gorm.DB, id int) (asset models.Asset, err error) {
+	err = db.Where("id = ?", id).First(&asset).Error
+	return
+}
+
+func (r *repository) UpdateAsset(db *gorm.DB, asset models.Asset) (err error) {
+	err = db.Save(&asset).Error
+	return
+}
+
+func (r *repository) DeleteAsset(db *gorm.DB, id int) (err error) {
+	err = db.Where("asset_id = ?", id).Delete(&models.AssetValuation{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("id = ?", id).Delete(&models.Asset{}).Error
+	return
+}
+
+func (r *repository) CreateAssetValuation(db *gorm.DB, valuation models.AssetValuation) (models.AssetValuation, error) {
+	err := db.Create(&valuation).Error
+	return valuation, err
+}
+
+func (r *repository) GetAssetValuations(db *gorm.DB, assetId int) (valuations []models.AssetValuation, err error) {
+	err = db.Where("asset_id = ?", assetId).Order("valued_at DESC").Order("id DESC").Find(&valuations).Error
+	return
+}
+
+func (r *repository) GetAssetValuationById(db *gorm.DB, id int) (valuation models.AssetValuation, err error) {
+	err = db.Where("id = ?", id).First(&valuation).Error
+	return
+}
+
+// GetLatestAssetValuations returns, for each asset of the user, the most
+// recent valuation on or before asOf (YYYY-MM-DD).
+func (r *repository) GetLatestAssetValuations(db *gorm.DB, userId int, asOf string) (valuations []models.AssetValuation, err error) {
+	err = db.Raw(`
+		SELECT DISTINCT ON (asset_id) *
+		FROM asset_valuations
+		WHERE user_id = ? AND valued_at <= ?
+		ORDER BY asset_id, valued_at DESC, id DESC`, userId, asOf).
+		Scan(&valuations).Error
+	return
+}
+
+func (r *repository) DeleteAssetValuation(db *gorm.DB, id int) (err error) {
+	err = db.Where("id = ?", id).Delete(&models.AssetValuation{}).Error
+	return
+}
+
+// SaveNetWorthSnapshot inserts the snapshot or replaces the one of the same
+// user and day.
+func (r *repository) SaveNetWorthSnapshot(db *gorm.DB, snapshot models.NetWorthSnapshot) (models.NetWorthSnapshot, error) {
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "snapshot_date"}},
+		UpdateAll: true,
+	}).Create(&snapshot).Error
+	if err != nil {
+		return snapshot, err
+	}
+	err = db.Where("user_id = ? AND snapshot_date = ?", snapshot.UserId, snapshot.SnapshotDate.Format("2006-01-02")).First(&snapshot).Error
+	return snapshot, err
+}
+
+func (r *repository) GetNetWorthSnapshots(db *gorm.DB, userId int, startDate string, endDate string) (snapshots []models.NetWorthSnapshot, err error) {
+	err = db.Where("user_id = ?", userId).
+		Where("snapshot_date >= ? AND snapshot_date <= ?", startDate, endDate).
+		Order("snapshot_date ASC").
+		Find(&snapshots).Error
+	return
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"go-crud-api/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateNotification inserts the notification unless the user already has
+// one with the same key; created tells which happened.
+func (r *repository) CreateNotification(db *gorm.DB, notification models.Notification) (created bool, result models.Notification, err error) {
+	query := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoNothing: true,
+	}).Create(&notification)
+	err = query.Error
+	created = query.RowsAffected > 0
+	result = notification
+	return
+}
+
+func (r *repository) GetNotifications(db *gorm.DB, userId int, unreadOnly bool, pagination models.QueryPagination) (count int64, notifications []models.Notification, err error) {
+	query := db.Model(&models.Notification{}).Where("user_id = ?", userId)
+
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	err = query.Count(&count).Error
+	if err != nil {
+		return
+	}
+
+	err = paginate(query, pagination).Find(&notifications).Error
+	return
+}
+
+func (r *repository) CountUnreadNotifications(db *gorm.DB, userId int) (count int64, err error) {
+	err = db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userId).Count(&count).Error
+	return
+}
+
+func (r *repository) GetNotificationById(db *gorm.DB, id int) (notification models.Notification, err error) {
+	err = db.Where("id = ?", id).First(&notification).Error
+	return
+}
+
+// MarkNotificationsRead marks the unread notification id of the user as
+// read, or all of them when id is 0.
+func (r *repository) MarkNotificationsRead(db *gorm.DB, userId int, id int) (err error) {
+	query := db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userId)
+	if id != 0 {
+		query = query.Where("id = ?", id)
+	}
+	err = query.Update("read_at", time.Now()).Error
+	return
+}
+
+func (r *repository) GetNotificationSettings(db *gorm.DB, userId int) (settings models.NotificationSettings, err error) {
+	err = db.Where("user_id = ?", userId).First(&settings).Error
+	return
+}
+
+// SaveNotificationSettings inserts the user's settings or replaces the
+// existing ones.
+func (r *repository) SaveNotificationSettings(db *gorm.DB, settings models.NotificationSettings) (models.NotificationSettings, error) {
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"budget_thresholds", "large_transaction_amount", "bill_reminders", "email", "webhook_url", "updated_at"}),
+	}).Create(&settings).Error
+	if err != nil {
+		return settings, err
+	}
+	err = db.Where("user_id = ?", settings.UserId).First(&settings).Error
+	return settings, err
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) CreateReconciliation(db *gorm.DB, reconciliation models.Reconciliation) (models.Reconciliation, error) {
+	err := db.Create(&reconciliation).Error
+	return reconciliation, err
+}
+
+func (r *repository) GetReconciliations(db *gorm.DB, userId int) (reconciliations []models.Reconciliation, err error) {
+	err = db.Where("user_id = ?", userId).Order("statement_end_date DESC").Order("id DESC").Find(&reconciliations).Error
+	return
+}
+
+func (r *repository) GetReconciliationById(db *gorm.DB, id int) (reconciliation models.Reconciliation, err error) {
+	err = db.Where("id = ?", id).First(&reconciliation).Error
+	return
+}
+
+// LockReconciliationById reads the session with SELECT ... FOR UPDATE, so
+// it stays as read until the surrounding DB transaction ends.
+func (r *repository) LockReconciliationById(db *gorm.DB, id int) (reconciliation models.Reconciliation, err error) {
+	err = db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&reconciliation).Error
+	return
+}
+
+func (r *repository) FindOpenReconciliation(db *gorm.DB, userId int) (reconciliation models.Reconciliation, err error) {
+	err = db.Where("user_id = ? AND status = ?", userId, "open").First(&reconciliation).Error
+	return
+}
+
+func (r *repository) UpdateReconciliationFields(db *gorm.DB, id int, fields map[string]interface{}) (err error) {
+	err = db.Model(&models.Reconciliation{}).Where("id = ?", id).Updates(fields).Error
+	return
+}
+
+func (r *repository) DeleteReconciliation(db *gorm.DB, id int) (err error) {
+	err = db.Where("id = ?", id).Delete(&models.Reconciliation{}).Error
+	return
+}
+
+// SumTransactions returns the net amount (income minus expense) and the
+// number of the transactions matching the filter.
+func (r *repository) SumTransactions(db *gorm.DB, filter models.QueryTransactionFilter) (total float64, count int64, err error) {
+	var result struct {
+		Total float64
+		Count int64
+	}
+	err = filterTransactions(db, db.Model(&models.Transaction{}), filter).
+		Select("COALESCE(SUM(CASE WHEN type = 'income' THEN amount WHEN type = 'expense' THEN -amount ELSE 0 END), 0) AS total, COUNT(*) AS count").
+		Scan(&result).Error
+	return result.Total, result.Count, err
+}
+
+// ReconcileTransactions marks the user's cleared transactions up to endDate
+// as reconciled in the given session and returns their ids. The rows are
+// locked first so the ids are exactly the ones updated.
+func (r *repository) ReconcileTransactions(db *gorm.DB, userId int, endDate string, reconciliationId int) (ids []int, err error) {
+	err = db.Model(&models.Transaction{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND status = ? AND DATE(occurred_at) <= ?", userId, "cleared", endDate).
+		Order("id ASC").
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return
+	}
+
+	err = db.Model(&models.Transaction{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"status": "reconciled", "reconciliation_id": reconciliationId}).Error
+	return
+}
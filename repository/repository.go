@@ -2,6 +2,7 @@ package repository
 
 import (
 	"go-crud-api/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -10,18 +11,184 @@ type Repository interface {
 	CreateUser(db *gorm.DB, user models.User) (err error)
 	FindUserById(db *gorm.DB, id int) (user models.User, err error)
 	FindUserByUsername(db *gorm.DB, username string) (user models.User, err error)
+	CountUsersByRole(db *gorm.DB, role string) (count int64, err error)
 	CreateCategory(db *gorm.DB, category models.Category) (models.Category, error)
 	GetCategories(db *gorm.DB, name string, pagination models.QueryPagination) (count int64, categories []models.Category, err error)
 	GetCategoryById(db *gorm.DB, id int) (category models.Category, err error)
 	UpdateCategory(db *gorm.DB, id int, name string) (err error)
 	DeleteCategory(db *gorm.DB, id int) (err error)
 	CreateTransaction(db *gorm.DB, transaction models.Transaction) (models.Transaction, error)
-	GetTransactions(db *gorm.DB, userId int, categoryId int, transactionType string, startDate string, endDate string, pagination models.QueryPagination) (count int64, transactions []models.Transaction, err error)
+	GetTransactions(db *gorm.DB, filter models.QueryTransactionFilter, pagination models.QueryPagination) (count int64, transactions []models.Transaction, err error)
 	GetTransactionById(db *gorm.DB, id int) (transaction models.Transaction, err error)
+	LockTransactionById(db *gorm.DB, id int) (transaction models.Transaction, err error)
 	UpdateTransaction(db *gorm.DB, id int, transaction models.Transaction) (err error)
+	UpdateTransactionFields(db *gorm.DB, id int, fields map[string]interface{}) (err error)
 	DeleteTransaction(db *gorm.DB, id int) (err error)
 	GetBalanceByDateRange(db *gorm.DB, userId int, startDate string, endDate string) (totalIncome float64, totalExpense float64, err error)
 	GetAllUsers(db *gorm.DB, pagination models.QueryPagination) (count int64, users []models.User, err error)
 	UpdateUser(db *gorm.DB, id int, user models.User) (err error)
+	UpdateUserFields(db *gorm.DB, id int, fields map[string]interface{}) (err error)
 	DeleteUser(db *gorm.DB, id int) (err error)
+	// Tags
+	CreateTag(db *gorm.DB, tag models.Tag) (models.Tag, error)
+	GetTags(db *gorm.DB, userId int, name string, pagination models.QueryPagination) (count int64, tags []models.Tag, err error)
+	GetTagById(db *gorm.DB, id int) (tag models.Tag, err error)
+	FindTagByName(db *gorm.DB, userId int, name string) (tag models.Tag, err error)
+	UpdateTag(db *gorm.DB, id int, name string) (err error)
+	DeleteTag(db *gorm.DB, id int) (transactionIds []int, err error)
+	ReplaceTransactionTags(db *gorm.DB, transactionId int, tags []models.Tag) (err error)
+	AddTransactionTags(db *gorm.DB, transactionId int, tags []models.Tag) (err error)
+	RemoveTransactionTags(db *gorm.DB, transactionId int, tags []models.Tag) (err error)
+	GetTagTotals(db *gorm.DB, userId int, startDate string, endDate string) (totals []models.TagTotal, err error)
+	// Rules
+	CreateRule(db *gorm.DB, rule models.Rule) (models.Rule, error)
+	GetRules(db *gorm.DB, userId int) (rules []models.Rule, err error)
+	GetRuleById(db *gorm.DB, id int) (rule models.Rule, err error)
+	UpdateRule(db *gorm.DB, rule models.Rule) (err error)
+	DeleteRule(db *gorm.DB, id int) (err error)
+	// Duplicates
+	FindDuplicatePairs(db *gorm.DB, userId int, window time.Duration, startDate string, endDate string) (pairs []models.DuplicatePair, err error)
+	FindSimilarTransactions(db *gorm.DB, userId int, amount float64, transactionType string, categoryId int, from time.Time, to time.Time) (transactions []models.Transaction, err error)
+	GetTransactionsByIds(db *gorm.DB, ids []int) (transactions []models.Transaction, err error)
+	FindTransactionByBankReference(db *gorm.DB, userId int, reference string) (transaction models.Transaction, err error)
+	CreateDuplicateDismissal(db *gorm.DB, dismissal models.DuplicateDismissal) (models.DuplicateDismissal, error)
+	MoveAttachments(db *gorm.DB, fromTransactionId int, toTransactionId int) (err error)
+	// Reconciliations
+	CreateReconciliation(db *gorm.DB, reconciliation models.Reconciliation) (models.Reconciliation, error)
+	GetReconciliations(db *gorm.DB, userId int) (reconciliations []models.Reconciliation, err error)
+	GetReconciliationById(db *gorm.DB, id int) (reconciliation models.Reconciliation, err error)
+	LockReconciliationById(db *gorm.DB, id int) (reconciliation models.Reconciliation, err error)
+	FindOpenReconciliation(db *gorm.DB, userId int) (reconciliation models.Reconciliation, err error)
+	UpdateReconciliationFields(db *gorm.DB, id int, fields map[string]interface{}) (err error)
+	DeleteReconciliation(db *gorm.DB, id int) (err error)
+	SumTransactions(db *gorm.DB, filter models.QueryTransactionFilter) (total float64, count int64, err error)
+	ReconcileTransactions(db *gorm.DB, userId int, endDate string, reconciliationId int) (ids []int, err error)
+	// Goals
+	CreateGoal(db *gorm.DB, goal models.Goal) (models.Goal, error)
+	GetGoals(db *gorm.DB, userId int) (goals []models.Goal, err error)
+	GetGoalById(db *gorm.DB, id int) (goal models.Goal, err error)
+	UpdateGoal(db *gorm.DB, goal models.Goal) (err error)
+	DeleteGoal(db *gorm.DB, id int) (err error)
+	CreateGoalContribution(db *gorm.DB, contribution models.GoalContribution) (models.GoalContribution, error)
+	GetGoalContributions(db *gorm.DB, goalId int) (contributions []models.GoalContribution, err error)
+	GetGoalContributionById(db *gorm.DB, id int) (contribution models.GoalContribution, err error)
+	DeleteGoalContribution(db *gorm.DB, id int) (err error)
+	SumGoalContributions(db *gorm.DB, goalId int, since *time.Time) (total float64, err error)
+	// Debts
+	CreateDebt(db *gorm.DB, debt models.Debt) (models.Debt, error)
+	GetDebts(db *gorm.DB, userId int) (debts []models.Debt, err error)
+	GetDebtById(db *gorm.DB, id int) (debt models.Debt, err error)
+	UpdateDebt(db *gorm.DB, debt models.Debt) (err error)
+	DeleteDebt(db *gorm.DB, id int) (err error)
+	CreateDebtPayment(db *gorm.DB, payment models.DebtPayment) (models.DebtPayment, error)
+	GetDebtPayments(db *gorm.DB, debtId int) (payments []models.DebtPayment, err error)
+	GetDebtPaymentById(db *gorm.DB, id int) (payment models.DebtPayment, err error)
+	FindDebtPaymentByTransaction(db *gorm.DB, transactionId int) (payment models.DebtPayment, err error)
+	DeleteDebtPayment(db *gorm.DB, id int) (err error)
+	// Bills
+	CreateBill(db *gorm.DB, bill models.Bill) (models.Bill, error)
+	GetBills(db *gorm.DB, userId int) (bills []models.Bill, err error)
+	GetBillById(db *gorm.DB, id int) (bill models.Bill, err error)
+	UpdateBill(db *gorm.DB, bill models.Bill) (err error)
+	DeleteBill(db *gorm.DB, id int) (err error)
+	CreateBillPayment(db *gorm.DB, payment models.BillPayment) (models.BillPayment, error)
+	GetBillPayments(db *gorm.DB, billIds []int, startDate string, endDate string) (payments []models.BillPayment, err error)
+	GetBillPaymentById(db *gorm.DB, id int) (payment models.BillPayment, err error)
+	FindBillPayment(db *gorm.DB, billId int, dueDate string) (payment models.BillPayment, err error)
+	FindBillPaymentByTransaction(db *gorm.DB, transactionId int) (payment models.BillPayment, err error)
+	DeleteBillPayment(db *gorm.DB, id int) (err error)
+	SaveCalendarFeed(db *gorm.DB, feed models.CalendarFeed) (err error)
+	FindCalendarFeedByTokenHash(db *gorm.DB, tokenHash string) (feed models.CalendarFeed, err error)
+	DeleteCalendarFeed(db *gorm.DB, userId int) (err error)
+	// Net worth
+	CreateAsset(db *gorm.DB, asset models.Asset) (models.Asset, error)
+	GetAssets(db *gorm.DB, userId int) (assets []models.Asset, err error)
+	GetAssetById(db *gorm.DB, id int) (asset models.Asset, err error)
+	UpdateAsset(db *gorm.DB, asset models.Asset) (err error)
+	DeleteAsset(db *gorm.DB, id int) (err error)
+	CreateAssetValuation(db *gorm.DB, valuation models.AssetValuation) (models.AssetValuation, error)
+	GetAssetValuations(db *gorm.DB, assetId int) (valuations []models.AssetValuation, err error)
+	GetAssetValuationById(db *gorm.DB, id int) (valuation models.AssetValuation, err error)
+	GetLatestAssetValuations(db *gorm.DB, userId int, asOf string) (valuations []models.AssetValuation, err error)
+	DeleteAssetValuation(db *gorm.DB, id int) (err error)
+	SaveNetWorthSnapshot(db *gorm.DB, snapshot models.NetWorthSnapshot) (models.NetWorthSnapshot, error)
+	GetNetWorthSnapshots(db *gorm.DB, userId int, startDate string, endDate string) (snapshots []models.NetWorthSnapshot, err error)
+	// Investments
+	CreatePortfolio(db *gorm.DB, portfolio models.Portfolio) (models.Portfolio, error)
+	GetPortfolios(db *gorm.DB, userId int) (portfolios []models.Portfolio, err error)
+	GetPortfolioById(db *gorm.DB, id int) (portfolio models.Portfolio, err error)
+	UpdatePortfolio(db *gorm.DB, portfolio models.Portfolio) (err error)
+	DeletePortfolio(db *gorm.DB, id int) (err error)
+	CreateHolding(db *gorm.DB, holding models.Holding) (models.Holding, error)
+	GetHoldings(db *gorm.DB, portfolioId int) (holdings []models.Holding, err error)
+	GetHoldingById(db *gorm.DB, id int) (holding models.Holding, err error)
+	FindHoldingBySymbol(db *gorm.DB, portfolioId int, symbol string) (holding models.Holding, err error)
+	DeleteHolding(db *gorm.DB, id int) (err error)
+	CreateInvestmentActivity(db *gorm.DB, activity models.InvestmentActivity) (models.InvestmentActivity, error)
+	GetInvestmentActivities(db *gorm.DB, holdingIds []int, until string) (activities []models.InvestmentActivity, err error)
+	GetInvestmentActivityById(db *gorm.DB, id int) (activity models.InvestmentActivity, err error)
+	DeleteInvestmentActivity(db *gorm.DB, id int) (err error)
+	SaveInstrumentPrice(db *gorm.DB, price models.InstrumentPrice) (models.InstrumentPrice, error)
+	GetInstrumentPrices(db *gorm.DB, userId int, symbol string, startDate string, endDate string) (prices []models.InstrumentPrice, err error)
+	GetInstrumentPriceById(db *gorm.DB, id int) (price models.InstrumentPrice, err error)
+	GetLatestInstrumentPrices(db *gorm.DB, userId int, symbols []string, asOf string) (prices []models.InstrumentPrice, err error)
+	DeleteInstrumentPrice(db *gorm.DB, id int) (err error)
+	// Envelopes
+	GetEnvelopeCategories(db *gorm.DB, userId int) (envelopes []models.EnvelopeCategory, err error)
+	SaveEnvelopeCategory(db *gorm.DB, envelope models.EnvelopeCategory) (models.EnvelopeCategory, error)
+	DeleteEnvelopeCategory(db *gorm.DB, userId int, categoryId int) (err error)
+	GetEnvelopeAssignments(db *gorm.DB, userId int, until string) (assignments []models.EnvelopeAssignment, err error)
+	SaveEnvelopeAssignment(db *gorm.DB, assignment models.EnvelopeAssignment) (models.EnvelopeAssignment, error)
+	SumTransactionsByCategory(db *gorm.DB, userId int, startDate string, endDate string) (totals []models.CategoryTotal, err error)
+	// Notifications
+	CreateNotification(db *gorm.DB, notification models.Notification) (created bool, result models.Notification, err error)
+	GetNotifications(db *gorm.DB, userId int, unreadOnly bool, pagination models.QueryPagination) (count int64, notifications []models.Notification, err error)
+	CountUnreadNotifications(db *gorm.DB, userId int) (count int64, err error)
+	GetNotificationById(db *gorm.DB, id int) (notification models.Notification, err error)
+	MarkNotificationsRead(db *gorm.DB, userId int, id int) (err error)
+	GetNotificationSettings(db *gorm.DB, userId int) (settings models.NotificationSettings, err error)
+	SaveNotificationSettings(db *gorm.DB, settings models.NotificationSettings) (models.NotificationSettings, error)
+	// Webhooks
+	CreateWebhookSubscription(db *gorm.DB, subscription models.WebhookSubscription) (models.WebhookSubscription, error)
+	GetWebhookSubscriptions(db *gorm.DB) (subscriptions []models.WebhookSubscription, err error)
+	GetWebhookSubscriptionById(db *gorm.DB, id int) (subscription models.WebhookSubscription, err error)
+	UpdateWebhookSubscription(db *gorm.DB, subscription models.WebhookSubscription) (err error)
+	DeleteWebhookSubscription(db *gorm.DB, id int) (err error)
+	CreateWebhookDeliveries(db *gorm.DB, deliveries []models.WebhookDelivery) (err error)
+	GetWebhookDeliveries(db *gorm.DB, subscriptionId int, status string, pagination models.QueryPagination) (count int64, deliveries []models.WebhookDelivery, err error)
+	GetWebhookDeliveryById(db *gorm.DB, id int) (delivery models.WebhookDelivery, err error)
+	ClaimWebhookDeliveries(db *gorm.DB, now time.Time, leaseUntil time.Time, limit int) (deliveries []models.WebhookDelivery, err error)
+	UpdateWebhookDeliveryFields(db *gorm.DB, id int, fields map[string]interface{}) (err error)
+	// Domain events
+	CreateDomainEvent(db *gorm.DB, event models.DomainEvent) (err error)
+	TryLockEventDispatch(db *gorm.DB) (locked bool, err error)
+	SequenceDomainEvents(db *gorm.DB) (err error)
+	GetLatestEventSequence(db *gorm.DB) (sequence int64, err error)
+	GetDomainEventsAfter(db *gorm.DB, sequence int64, limit int) (events []models.DomainEvent, err error)
+	GetUserDomainEventsAfter(db *gorm.DB, sequence int64, userId int, types []string, limit int) (events []models.DomainEvent, err error)
+	GetDomainEvents(db *gorm.DB, eventType string, pagination models.QueryPagination) (count int64, events []models.DomainEvent, err error)
+	GetEventOffsets(db *gorm.DB) (offsets []models.EventOffset, err error)
+	GetEventOffset(db *gorm.DB, subscriber string) (offset models.EventOffset, err error)
+	InitEventOffset(db *gorm.DB, subscriber string, sequence int64) (err error)
+	SaveEventOffset(db *gorm.DB, subscriber string, sequence int64) (err error)
+	// Stream tickets
+	CreateStreamTicket(db *gorm.DB, ticket models.StreamTicket) (err error)
+	ConsumeStreamTicket(db *gorm.DB, tokenHash string, now time.Time) (ticket models.StreamTicket, err error)
+	DeleteExpiredStreamTickets(db *gorm.DB, now time.Time) (err error)
+	// Sync
+	GetUserTransactions(db *gorm.DB, userId int) (transactions []models.Transaction, err error)
+	GetAllCategories(db *gorm.DB) (categories []models.Category, err error)
+	GetUserTags(db *gorm.DB, userId int) (tags []models.Tag, err error)
+	GetTransactionByUuid(db *gorm.DB, uuid string) (transaction models.Transaction, err error)
+	GetTagByUuid(db *gorm.DB, uuid string) (tag models.Tag, err error)
+	FindIdByUuid(db *gorm.DB, model interface{}, uuid string, userId int) (id int, err error)
+	// GraphQL
+	FindUsersByIds(db *gorm.DB, ids []int) (users []models.User, err error)
+	GetCategoriesByIds(db *gorm.DB, ids []int) (categories []models.Category, err error)
+	// Attachments
+	CreateAttachment(db *gorm.DB, attachment models.Attachment) (models.Attachment, error)
+	GetAttachments(db *gorm.DB, transactionId int) (attachments []models.Attachment, err error)
+	GetAttachmentById(db *gorm.DB, id int) (attachment models.Attachment, err error)
+	DeleteAttachment(db *gorm.DB, id int) (err error)
+	DeleteAttachmentsByTransaction(db *gorm.DB, transactionId int) (err error)
 }
@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"fmt"
 	"go-crud-api/models"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository struct{}
@@ -12,6 +15,16 @@ func NewRepository() Repository {
 	return &repository{}
 }
 
+// likeEscaper escapes the LIKE wildcards and the escape character itself,
+// for patterns used with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern is the LIKE pattern matching text anywhere in a value,
+// with the wildcards in text taken literally.
+func containsPattern(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
+
 func (r *repository) CreateUser(db *gorm.DB, user models.User) (err error) {
 	err = db.Create(&user).Error
 	return
@@ -27,6 +40,11 @@ func (r *repository) FindUserByUsername(db *gorm.DB, username string) (user mode
 	return
 }
 
+func (r *repository) CountUsersByRole(db *gorm.DB, role string) (count int64, err error) {
+	err = db.Model(&models.User{}).Where("role = ?", role).Count(&count).Error
+	return
+}
+
 func (r *repository) CreateCategory(db *gorm.DB, category models.Category) (models.Category, error) {
 	err := db.Create(&category).Error
 	return category, err
@@ -36,8 +54,8 @@ func (r *repository) GetCategories(db *gorm.DB, name string, pagination models.Q
 	query := db.Model(&models.Category{})
 
 	if name != "" {
-		searchQuery := "%" + name + "%"
-		query = query.Where("LOWER(name) LIKE ?", searchQuery)
+		searchQuery := containsPattern(name)
+		query = query.Where(`LOWER(name) LIKE ? ESCAPE '\'`, searchQuery)
 	}
 
 	err = query.Count(&count).Error
@@ -45,7 +63,7 @@ func (r *repository) GetCategories(db *gorm.DB, name string, pagination models.Q
 		return
 	}
 
-	err = query.Limit(pagination.Limit).Offset(pagination.Offset).Find(&categories).Error
+	err = paginate(query, pagination).Find(&categories).Error
 	if err != nil {
 		return
 	}
@@ -74,49 +92,107 @@ func (r *repository) CreateTransaction(db *gorm.DB, transaction models.Transacti
 		return transaction, err
 	}
 	// Load relations
-	err = db.Preload("User").Preload("Category").First(&transaction, transaction.Id).Error
+	err = db.Preload("User").Preload("Category").Preload("Tags").First(&transaction, transaction.Id).Error
 	return transaction, err
 }
 
-func (r *repository) GetTransactions(db *gorm.DB, userId int, categoryId int, transactionType string, startDate string, endDate string, pagination models.QueryPagination) (count int64, transactions []models.Transaction, err error) {
-	query := db.Model(&models.Transaction{})
+func (r *repository) GetTransactions(db *gorm.DB, filter models.QueryTransactionFilter, pagination models.QueryPagination) (count int64, transactions []models.Transaction, err error) {
+	query := filterTransactions(db, db.Model(&models.Transaction{}), filter)
 
-	if userId != 0 {
-		query = query.Where("user_id = ?", userId)
+	err = query.Count(&count).Error
+	if err != nil {
+		return
 	}
 
-	if categoryId != 0 {
-		query = query.Where("category_id = ?", categoryId)
+	err = paginate(query.Preload("User").Preload("Category").Preload("Tags"), pagination).Find(&transactions).Error
+	if err != nil {
+		return
 	}
 
-	if transactionType != "" {
-		query = query.Where("type = ?", transactionType)
+	return
+}
+
+// filterTransactions applies every non-zero field of the filter to query.
+func filterTransactions(db *gorm.DB, query *gorm.DB, filter models.QueryTransactionFilter) *gorm.DB {
+	if filter.UserId != 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+
+	if len(filter.CategoryIds) > 0 {
+		query = query.Where("category_id IN ?", filter.CategoryIds)
+	}
+
+	if len(filter.ExcludeCategoryIds) > 0 {
+		query = query.Where("category_id NOT IN ?", filter.ExcludeCategoryIds)
+	}
+
+	if len(filter.Types) > 0 {
+		query = query.Where("type IN ?", filter.Types)
+	}
+
+	if len(filter.ExcludeTypes) > 0 {
+		query = query.Where("type NOT IN ?", filter.ExcludeTypes)
+	}
+
+	if filter.MinAmount != nil {
+		query = query.Where("amount >= ?", *filter.MinAmount)
+	}
+
+	if filter.MaxAmount != nil {
+		query = query.Where("amount <= ?", *filter.MaxAmount)
+	}
+
+	if filter.Search != "" {
+		searchQuery := containsPattern(strings.ToLower(filter.Search))
+		categoryIds := db.Model(&models.Category{}).Select("id").Where(`LOWER(name) LIKE ? ESCAPE '\'`, searchQuery)
+		query = query.Where(`(LOWER(note) LIKE ? ESCAPE '\' OR LOWER(payee) LIKE ? ESCAPE '\' OR category_id IN (?))`, searchQuery, searchQuery, categoryIds)
+	}
+
+	if len(filter.Tags) > 0 {
+		query = query.Where("id IN (?)", taggedTransactionIds(db, filter.Tags))
+	}
+
+	if len(filter.ExcludeTags) > 0 {
+		query = query.Where("id NOT IN (?)", taggedTransactionIds(db, filter.ExcludeTags))
+	}
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+
+	if filter.ReconciliationId != 0 {
+		query = query.Where("reconciliation_id = ?", filter.ReconciliationId)
 	}
 
 	// Add date range filter
-	if startDate != "" {
-		query = query.Where("DATE(created_at) >= ?", startDate)
+	if filter.StartDate != "" {
+		query = query.Where("DATE(occurred_at) >= ?", filter.StartDate)
 	}
 
-	if endDate != "" {
-		query = query.Where("DATE(created_at) <= ?", endDate)
+	if filter.EndDate != "" {
+		query = query.Where("DATE(occurred_at) <= ?", filter.EndDate)
 	}
 
-	err = query.Count(&count).Error
-	if err != nil {
-		return
+	if filter.StartTime != nil {
+		query = query.Where("occurred_at >= ?", *filter.StartTime)
 	}
 
-	err = query.Preload("User").Preload("Category").Order("created_at DESC").Limit(pagination.Limit).Offset(pagination.Offset).Find(&transactions).Error
-	if err != nil {
-		return
+	if filter.EndTime != nil {
+		query = query.Where("occurred_at <= ?", *filter.EndTime)
 	}
 
-	return
+	return query
 }
 
 func (r *repository) GetTransactionById(db *gorm.DB, id int) (transaction models.Transaction, err error) {
-	err = db.Preload("User").Preload("Category").Where("id = ?", id).First(&transaction).Error
+	err = db.Preload("User").Preload("Category").Preload("Tags").Where("id = ?", id).First(&transaction).Error
+	return
+}
+
+// LockTransactionById reads the transaction with SELECT ... FOR UPDATE, so
+// it stays as read until the surrounding DB transaction ends.
+func (r *repository) LockTransactionById(db *gorm.DB, id int) (transaction models.Transaction, err error) {
+	err = db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&transaction).Error
 	return
 }
 
@@ -125,7 +201,36 @@ func (r *repository) UpdateTransaction(db *gorm.DB, id int, transaction models.T
 	return
 }
 
+// UpdateTransactionFields updates only the given columns, including zero values.
+func (r *repository) UpdateTransactionFields(db *gorm.DB, id int, fields map[string]interface{}) (err error) {
+	err = db.Model(&models.Transaction{}).Where("id = ?", id).Updates(fields).Error
+	return
+}
+
 func (r *repository) DeleteTransaction(db *gorm.DB, id int) (err error) {
+	err = db.Table("transaction_tags").Where("transaction_id = ?", id).Delete(nil).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("transaction_id = ? OR duplicate_id = ?", id, id).Delete(&models.DuplicateDismissal{}).Error
+	if err != nil {
+		return
+	}
+	// Debt and bill payments linked to the transaction are the same money
+	// movement, so they go with it
+	err = db.Where("transaction_id = ?", id).Delete(&models.DebtPayment{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("transaction_id = ?", id).Delete(&models.BillPayment{}).Error
+	if err != nil {
+		return
+	}
+	// An investment activity happened regardless of its cash record
+	err = db.Model(&models.InvestmentActivity{}).Where("transaction_id = ?", id).Update("transaction_id", nil).Error
+	if err != nil {
+		return
+	}
 	err = db.Where("id = ?", id).Delete(&models.Transaction{}).Error
 	return
 }
@@ -134,10 +239,10 @@ func (r *repository) GetBalanceByDateRange(db *gorm.DB, userId int, startDate st
 	// Calculate total income
 	incomeQuery := db.Model(&models.Transaction{}).Where("user_id = ?", userId).Where("type = ?", "income")
 	if startDate != "" {
-		incomeQuery = incomeQuery.Where("DATE(created_at) >= ?", startDate)
+		incomeQuery = incomeQuery.Where("DATE(occurred_at) >= ?", startDate)
 	}
 	if endDate != "" {
-		incomeQuery = incomeQuery.Where("DATE(created_at) <= ?", endDate)
+		incomeQuery = incomeQuery.Where("DATE(occurred_at) <= ?", endDate)
 	}
 
 	var incomeResult struct {
@@ -152,10 +257,10 @@ func (r *repository) GetBalanceByDateRange(db *gorm.DB, userId int, startDate st
 	// Calculate total expense
 	expenseQuery := db.Model(&models.Transaction{}).Where("user_id = ?", userId).Where("type = ?", "expense")
 	if startDate != "" {
-		expenseQuery = expenseQuery.Where("DATE(created_at) >= ?", startDate)
+		expenseQuery = expenseQuery.Where("DATE(occurred_at) >= ?", startDate)
 	}
 	if endDate != "" {
-		expenseQuery = expenseQuery.Where("DATE(created_at) <= ?", endDate)
+		expenseQuery = expenseQuery.Where("DATE(occurred_at) <= ?", endDate)
 	}
 
 	var expenseResult struct {
@@ -178,7 +283,7 @@ func (r *repository) GetAllUsers(db *gorm.DB, pagination models.QueryPagination)
 		return
 	}
 
-	err = query.Select("id", "name", "username", "role", "created_at", "updated_at").Order("created_at DESC").Limit(pagination.Limit).Offset(pagination.Offset).Find(&users).Error
+	err = paginate(query.Select("id", "uuid", "name", "username", "role", "created_at", "updated_at"), pagination).Find(&users).Error
 	if err != nil {
 		return
 	}
@@ -191,7 +296,61 @@ func (r *repository) UpdateUser(db *gorm.DB, id int, user models.User) (err erro
 	return
 }
 
+// UpdateUserFields updates only the given columns, including zero values.
+func (r *repository) UpdateUserFields(db *gorm.DB, id int, fields map[string]interface{}) (err error) {
+	err = db.Model(&models.User{}).Where("id = ?", id).Updates(fields).Error
+	return
+}
+
 func (r *repository) DeleteUser(db *gorm.DB, id int) (err error) {
 	err = db.Where("id = ?", id).Delete(&models.User{}).Error
 	return
 }
+
+// taggedTransactionIds is a subquery of transaction ids carrying any of the
+// tag names, matched case-insensitively.
+func taggedTransactionIds(db *gorm.DB, names []string) *gorm.DB {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+	return db.Table("transaction_tags").
+		Select("transaction_tags.transaction_id").
+		Joins("JOIN tags ON tags.id = transaction_tags.tag_id").
+		Where("LOWER(tags.name) IN ?", lowered)
+}
+
+// paginate orders by the requested sort column with id as tie-breaker and
+// applies either offset or keyset pagination. In cursor mode one extra row is
+// fetched so the caller can tell whether another page exists, and backward
+// pages are read in reverse order.
+func paginate(query *gorm.DB, pagination models.QueryPagination) *gorm.DB {
+	column := pagination.Sort.Column
+	if column == "" {
+		column = "created_at"
+	}
+	desc := pagination.Sort.Desc
+	if pagination.Cursor != nil && pagination.Cursor.Backward {
+		desc = !desc
+	}
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	if pagination.Cursor != nil {
+		operator := ">"
+		if desc {
+			operator = "<"
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, operator), pagination.Cursor.Value, pagination.Cursor.Id)
+	}
+
+	query = query.Order(column + " " + direction).Order("id " + direction)
+
+	if pagination.UseCursor {
+		return query.Limit(pagination.Limit + 1)
+	}
+	return query.Limit(pagination.Limit).Offset(pagination.Offset)
+}
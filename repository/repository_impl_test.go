@@ -0,0 +1,19 @@
+package repository
+
+import "testing"
+
+func TestContainsPatternEscapesWildcards(t *testing.T) {
+	cases := map[string]string{
+		"kopi":     "%kopi%",
+		"100%":     `%100\%%`,
+		"a_b":      `%a\_b%`,
+		`C:\data`:  `%C:\\data%`,
+		`50\%_off`: `%50\\\%\_off%`,
+		"":         "%%",
+	}
+	for text, want := range cases {
+		if got := containsPattern(text); got != want {
+			t.Errorf("containsPattern(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) CreateRule(db *gorm.DB, rule models.Rule) (models.Rule, error) {
+	err := db.Create(&rule).Error
+	return rule, err
+}
+
+// GetRules returns the user's rules in the order they are applied.
+func (r *repository) GetRules(db *gorm.DB, userId int) (rules []models.Rule, err error) {
+	err = db.Where("user_id = ?", userId).Order("priority ASC").Order("id ASC").Find(&rules).Error
+	return
+}
+
+func (r *repository) GetRuleById(db *gorm.DB, id int) (rule models.Rule, err error) {
+	err = db.Where("id = ?", id).First(&rule).Error
+	return
+}
+
+func (r *repository) UpdateRule(db *gorm.DB, rule models.Rule) (err error) {
+	err = db.Save(&rule).Error
+	return
+}
+
+func (r *repository) DeleteRule(db *gorm.DB, id int) (err error) {
+	err = db.Where("id = ?", id).Delete(&models.Rule{}).Error
+	return
+}
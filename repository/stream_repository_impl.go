@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"go-crud-api/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) CreateStreamTicket(db *gorm.DB, ticket models.StreamTicket) (err error) {
+	err = db.Create(&ticket).Error
+	return
+}
+
+// ConsumeStreamTicket deletes an unexpired ticket and returns it, in one
+// statement so a ticket can only be used once.
+func (r *repository) ConsumeStreamTicket(db *gorm.DB, tokenHash string, now time.Time) (ticket models.StreamTicket, err error) {
+	result := db.Clauses(clause.Returning{}).Where("token_hash = ? AND expires_at > ?", tokenHash, now).Delete(&ticket)
+	err = result.Error
+	if err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+	return
+}
+
+func (r *repository) DeleteExpiredStreamTickets(db *gorm.DB, now time.Time) (err error) {
+	err = db.Where("expires_at <= ?", now).Delete(&models.StreamTicket{}).Error
+	return
+}
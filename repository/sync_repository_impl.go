@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) GetUserTransactions(db *gorm.DB, userId int) (transactions []models.Transaction, err error) {
+	err = db.Preload("User").Preload("Category").Preload("Tags").Where("user_id = ?", userId).Order("id ASC").Find(&transactions).Error
+	return
+}
+
+func (r *repository) GetAllCategories(db *gorm.DB) (categories []models.Category, err error) {
+	err = db.Order("id ASC").Find(&categories).Error
+	return
+}
+
+func (r *repository) GetUserTags(db *gorm.DB, userId int) (tags []models.Tag, err error) {
+	err = db.Where("user_id = ?", userId).Order("id ASC").Find(&tags).Error
+	return
+}
+
+func (r *repository) GetTransactionByUuid(db *gorm.DB, uuid string) (transaction models.Transaction, err error) {
+	err = db.Preload("User").Preload("Category").Preload("Tags").Where("uuid = ?", uuid).First(&transaction).Error
+	return
+}
+
+func (r *repository) GetTagByUuid(db *gorm.DB, uuid string) (tag models.Tag, err error) {
+	err = db.Where("uuid = ?", uuid).First(&tag).Error
+	return
+}
+
+// FindIdByUuid returns the integer id of the model's row with the public id.
+// A userId other than 0 only matches the rows of that user.
+func (r *repository) FindIdByUuid(db *gorm.DB, model interface{}, uuid string, userId int) (id int, err error) {
+	query := db.Model(model).Where("uuid = ?", uuid)
+	if userId != 0 {
+		query = query.Where("user_id = ?", userId)
+	}
+
+	var ids []int
+	err = query.Limit(1).Pluck("id", &ids).Error
+	if err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = gorm.ErrRecordNotFound
+		return
+	}
+	id = ids[0]
+	return
+}
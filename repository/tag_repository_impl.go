@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"go-crud-api/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) CreateTag(db *gorm.DB, tag models.Tag) (models.Tag, error) {
+	err := db.Create(&tag).Error
+	return tag, err
+}
+
+func (r *repository) GetTags(db *gorm.DB, userId int, name string, pagination models.QueryPagination) (count int64, tags []models.Tag, err error) {
+	query := db.Model(&models.Tag{}).Where("user_id = ?", userId)
+
+	if name != "" {
+		searchQuery := containsPattern(strings.ToLower(name))
+		query = query.Where(`LOWER(name) LIKE ? ESCAPE '\'`, searchQuery)
+	}
+
+	err = query.Count(&count).Error
+	if err != nil {
+		return
+	}
+
+	err = paginate(query, pagination).Find(&tags).Error
+	return
+}
+
+func (r *repository) GetTagById(db *gorm.DB, id int) (tag models.Tag, err error) {
+	err = db.Where("id = ?", id).First(&tag).Error
+	return
+}
+
+func (r *repository) FindTagByName(db *gorm.DB, userId int, name string) (tag models.Tag, err error) {
+	err = db.Where("user_id = ? AND LOWER(name) = LOWER(?)", userId, name).First(&tag).Error
+	return
+}
+
+func (r *repository) UpdateTag(db *gorm.DB, id int, name string) (err error) {
+	err = db.Model(&models.Tag{}).Where("id = ?", id).Update("name", name).Error
+	return
+}
+
+// DeleteTag removes the tag from its transactions, which count as updated,
+// and deletes it. It returns the ids of those transactions.
+func (r *repository) DeleteTag(db *gorm.DB, id int) (transactionIds []int, err error) {
+	err = db.Table("transaction_tags").Where("tag_id = ?", id).Order("transaction_id").Pluck("transaction_id", &transactionIds).Error
+	if err != nil {
+		return
+	}
+	err = db.Table("transaction_tags").Where("tag_id = ?", id).Delete(nil).Error
+	if err != nil {
+		return
+	}
+	if len(transactionIds) > 0 {
+		err = db.Model(&models.Transaction{}).Where("id IN ?", transactionIds).Update("updated_at", time.Now()).Error
+		if err != nil {
+			return
+		}
+	}
+	err = db.Where("id = ?", id).Delete(&models.Tag{}).Error
+	return
+}
+
+func (r *repository) ReplaceTransactionTags(db *gorm.DB, transactionId int, tags []models.Tag) (err error) {
+	transaction := models.Transaction{Id: transactionId}
+	if len(tags) == 0 {
+		err = db.Model(&transaction).Association("Tags").Clear()
+		return
+	}
+	err = db.Model(&transaction).Association("Tags").Replace(tags)
+	return
+}
+
+func (r *repository) AddTransactionTags(db *gorm.DB, transactionId int, tags []models.Tag) (err error) {
+	err = db.Model(&models.Transaction{Id: transactionId}).Association("Tags").Append(tags)
+	return
+}
+
+func (r *repository) RemoveTransactionTags(db *gorm.DB, transactionId int, tags []models.Tag) (err error) {
+	err = db.Model(&models.Transaction{Id: transactionId}).Association("Tags").Delete(tags)
+	return
+}
+
+// GetTagTotals sums income and expense per tag for the user's transactions
+// whose occurred_at falls in the range. Tags without transactions are omitted.
+func (r *repository) GetTagTotals(db *gorm.DB, userId int, startDate string, endDate string) (totals []models.TagTotal, err error) {
+	query := db.Table("tags").
+		Select(`tags.id AS tag_id, tags.name AS name, COUNT(transactions.id) AS count,
+			COALESCE(SUM(CASE WHEN transactions.type = 'income' THEN transactions.amount ELSE 0 END), 0) AS total_income,
+			COALESCE(SUM(CASE WHEN transactions.type = 'expense' THEN transactions.amount ELSE 0 END), 0) AS total_expense`).
+		Joins("JOIN transaction_tags ON transaction_tags.tag_id = tags.id").
+		Joins("JOIN transactions ON transactions.id = transaction_tags.transaction_id").
+		Where("tags.user_id = ?", userId)
+
+	if startDate != "" {
+		query = query.Where("DATE(transactions.occurred_at) >= ?", startDate)
+	}
+	if endDate != "" {
+		query = query.Where("DATE(transactions.occurred_at) <= ?", endDate)
+	}
+
+	err = query.Group("tags.id, tags.name").Order("tags.name ASC").Scan(&totals).Error
+	return
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"go-crud-api/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) CreateWebhookSubscription(db *gorm.DB, subscription models.WebhookSubscription) (models.WebhookSubscription, error) {
+	err := db.Create(&subscription).Error
+	return subscription, err
+}
+
+func (r *repository) GetWebhookSubscriptions(db *gorm.DB) (subscriptions []models.WebhookSubscription, err error) {
+	err = db.Order("id ASC").Find(&subscriptions).Error
+	return
+}
+
+func (r *repository) GetWebhookSubscriptionById(db *gorm.DB, id int) (subscription models.WebhookSubscription, err error) {
+	err = db.Where("id = ?", id).First(&subscription).Error
+	return
+}
+
+func (r *repository) UpdateWebhookSubscription(db *gorm.DB, subscription models.WebhookSubscription) (err error) {
+	err = db.Save(&subscription).Error
+	return
+}
+
+// DeleteWebhookSubscription removes the subscription and its delivery log,
+// including deliveries still queued.
+func (r *repository) DeleteWebhookSubscription(db *gorm.DB, id int) (err error) {
+	err = db.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error
+	if err != nil {
+		return
+	}
+	err = db.Where("id = ?", id).Delete(&models.WebhookSubscription{}).Error
+	return
+}
+
+func (r *repository) CreateWebhookDeliveries(db *gorm.DB, deliveries []models.WebhookDelivery) (err error) {
+	if len(deliveries) == 0 {
+		return
+	}
+	err = db.Create(&deliveries).Error
+	return
+}
+
+func (r *repository) GetWebhookDeliveries(db *gorm.DB, subscriptionId int, status string, pagination models.QueryPagination) (count int64, deliveries []models.WebhookDelivery, err error) {
+	query := db.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscriptionId)
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err = query.Count(&count).Error
+	if err != nil {
+		return
+	}
+
+	err = paginate(query, pagination).Find(&deliveries).Error
+	return
+}
+
+func (r *repository) GetWebhookDeliveryById(db *gorm.DB, id int) (delivery models.WebhookDelivery, err error) {
+	err = db.Where("id = ?", id).First(&delivery).Error
+	return
+}
+
+// ClaimWebhookDeliveries picks up to limit pending deliveries that are due
+// and pushes their next attempt to leaseUntil, so another worker won't send
+// them while they are in flight. Rows locked by another worker are skipped.
+func (r *repository) ClaimWebhookDeliveries(db *gorm.DB, now time.Time, leaseUntil time.Time, limit int) (deliveries []models.WebhookDelivery, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		errTx := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", "pending", now).
+			Order("next_attempt_at ASC").Order("id ASC").
+			Limit(limit).
+			Find(&deliveries).Error
+		if errTx != nil || len(deliveries) == 0 {
+			return errTx
+		}
+
+		ids := make([]int, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.Id
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", leaseUntil).Error
+	})
+	return
+}
+
+// UpdateWebhookDeliveryFields updates only the given columns, including zero values.
+func (r *repository) UpdateWebhookDeliveryFields(db *gorm.DB, id int, fields map[string]interface{}) (err error) {
+	err = db.Model(&models.WebhookDelivery{}).Where("id = ?", id).Updates(fields).Error
+	return
+}
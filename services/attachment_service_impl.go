@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxAttachmentSize = 10 << 20 // 10 MB
+	thumbnailMaxSize  = 256
+)
+
+// allowedAttachmentTypes maps the sniffed MIME type to the stored extension.
+var allowedAttachmentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// thumbnailTypes are the image types the thumbnail generator can decode.
+var thumbnailTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+func (s *service) UploadAttachment(transactionId int, userId int, req models.RequestUploadAttachment) (attachment models.Attachment, err error) {
+	// Check if transaction exists, belongs to user and is not locked
+	err = s.checkTransactionEditable(s.Db, transactionId, userId)
+	if err != nil {
+		return
+	}
+
+	// Validasi: ukuran file
+	if req.Size > maxAttachmentSize {
+		err = fmt.Errorf("file is too large: maximum size is %d MB", maxAttachmentSize>>20)
+		return
+	}
+
+	content, err := io.ReadAll(io.LimitReader(req.Content, maxAttachmentSize+1))
+	if err != nil {
+		return
+	}
+	if len(content) == 0 {
+		err = errors.New("file is required and cannot be empty")
+		return
+	}
+	if len(content) > maxAttachmentSize {
+		err = fmt.Errorf("file is too large: maximum size is %d MB", maxAttachmentSize>>20)
+		return
+	}
+
+	// Validasi: MIME type diambil dari isi file, bukan dari header client
+	contentType := http.DetectContentType(content)
+	if i := strings.Index(contentType, ";"); i != -1 {
+		contentType = contentType[:i]
+	}
+	extension, ok := allowedAttachmentTypes[contentType]
+	if !ok {
+		err = fmt.Errorf("unsupported file type %s", contentType)
+		return
+	}
+
+	name, err := randomHex(16)
+	if err != nil {
+		return
+	}
+	ctx := context.Background()
+	baseKey := fmt.Sprintf("attachments/%d/%d/%s", userId, transactionId, name)
+
+	attachment = models.Attachment{
+		TransactionId: transactionId,
+		UserId:        userId,
+		FileName:      path.Base(req.FileName),
+		ContentType:   contentType,
+		Size:          int64(len(content)),
+		StorageKey:    baseKey + extension,
+	}
+
+	err = s.Storage.Put(ctx, attachment.StorageKey, bytes.NewReader(content), attachment.Size, contentType)
+	if err != nil {
+		return
+	}
+
+	if thumbnailTypes[contentType] {
+		// A broken image still gets stored, just without a thumbnail
+		thumbnail, errThumbnail := helper.GenerateThumbnail(content, thumbnailMaxSize)
+		if errThumbnail == nil {
+			thumbnailKey := baseKey + "_thumb.jpg"
+			err = s.Storage.Put(ctx, thumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg")
+			if err != nil {
+				s.Storage.Delete(ctx, attachment.StorageKey)
+				return
+			}
+			attachment.ThumbnailKey = thumbnailKey
+			attachment.ThumbnailSize = int64(len(thumbnail))
+			attachment.HasThumbnail = true
+		}
+	}
+
+	attachment, err = s.Repository.CreateAttachment(s.Db, attachment)
+	if err != nil {
+		s.removeStoredFiles([]models.Attachment{attachment})
+	}
+	return
+}
+
+func (s *service) GetAttachments(transactionId int, userId int) (attachments []models.Attachment, err error) {
+	err = s.checkTransactionOwner(s.Db, transactionId, userId)
+	if err != nil {
+		return
+	}
+
+	attachments, err = s.Repository.GetAttachments(s.Db, transactionId)
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+	return
+}
+
+func (s *service) GetAttachmentContent(req models.RequestGetAttachment, userId int) (response models.ResponseAttachmentContent, err error) {
+	attachment, err := s.getOwnedAttachment(req.TransactionId, req.AttachmentId, userId)
+	if err != nil {
+		return
+	}
+
+	key, contentType, size := attachment.StorageKey, attachment.ContentType, attachment.Size
+	if req.Thumbnail {
+		if !attachment.HasThumbnail {
+			err = errors.New("attachment has no thumbnail")
+			return
+		}
+		key, contentType, size = attachment.ThumbnailKey, "image/jpeg", attachment.ThumbnailSize
+	}
+
+	content, err := s.Storage.Get(context.Background(), key)
+	if err != nil {
+		return
+	}
+
+	response = models.ResponseAttachmentContent{
+		Attachment:  attachment,
+		ContentType: contentType,
+		Size:        size,
+		Content:     content,
+	}
+	return
+}
+
+func (s *service) DeleteAttachment(transactionId int, attachmentId int, userId int) (err error) {
+	// Attachments of a reconciled transaction are locked with it
+	err = s.checkTransactionEditable(s.Db, transactionId, userId)
+	if err != nil {
+		return
+	}
+
+	attachment, err := s.getOwnedAttachment(transactionId, attachmentId, userId)
+	if err != nil {
+		return
+	}
+
+	err = s.Repository.DeleteAttachment(s.Db, attachment.Id)
+	if err != nil {
+		return
+	}
+
+	s.removeStoredFiles([]models.Attachment{attachment})
+	return
+}
+
+// getOwnedAttachment applies the same ownership check as GetTransactionById
+// and makes sure the attachment belongs to that transaction.
+func (s *service) getOwnedAttachment(transactionId int, attachmentId int, userId int) (attachment models.Attachment, err error) {
+	err = s.checkTransactionOwner(s.Db, transactionId, userId)
+	if err != nil {
+		return
+	}
+
+	attachment, err = s.Repository.GetAttachmentById(s.Db, attachmentId)
+	if err != nil {
+		return
+	}
+
+	if attachment.TransactionId != transactionId {
+		err = gorm.ErrRecordNotFound
+		return
+	}
+	return
+}
+
+// removeStoredFiles deletes the blobs of attachments whose rows are gone.
+// Failures only leave orphaned files behind, so they are ignored.
+func (s *service) removeStoredFiles(attachments []models.Attachment) {
+	ctx := context.Background()
+	for _, attachment := range attachments {
+		s.Storage.Delete(ctx, attachment.StorageKey)
+		if attachment.ThumbnailKey != "" {
+			s.Storage.Delete(ctx, attachment.ThumbnailKey)
+		}
+	}
+}
+
+func randomHex(n int) (string, error) {
+	buffer := make([]byte, n)
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
@@ -0,0 +1,590 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BillFixed     = "fixed"
+	BillEstimated = "estimated"
+
+	BillOnce    = "once"
+	BillWeekly  = "weekly"
+	BillMonthly = "monthly"
+	BillYearly  = "yearly"
+
+	BillPaid    = "paid"
+	BillUnpaid  = "unpaid"
+	BillOverdue = "overdue"
+
+	maxBillNameLength     = 100
+	maxBillInterval       = 120
+	maxBillRemindDays     = 60
+	defaultBillRemindDays = 1
+
+	// maxBillOccurrences bounds the due dates expanded for one bill
+	maxBillOccurrences = 5000
+	// billOverdueLookbackDays is how far back unpaid occurrences are listed
+	// as overdue by the upcoming endpoint and the calendar feed
+	billOverdueLookbackDays = 90
+	defaultUpcomingBillDays = 30
+	maxUpcomingBillDays     = 366
+	// billCalendarDays is how far ahead the calendar feed lists due dates
+	billCalendarDays = 365
+)
+
+var errCalendarNotFound = errors.New("calendar not found")
+
+func (s *service) CreateBill(userId int, req models.RequestCreateBill) (bill models.Bill, err error) {
+	bill, err = s.buildBill(userId, req)
+	if err != nil {
+		return
+	}
+
+	bill, err = s.Repository.CreateBill(s.Db, bill)
+	return
+}
+
+func (s *service) GetBills(userId int) (bills []models.Bill, err error) {
+	bills, err = s.Repository.GetBills(s.Db, userId)
+	if bills == nil {
+		bills = []models.Bill{}
+	}
+	return
+}
+
+func (s *service) GetBillById(req models.RequestGetBillById, userId int) (bill models.Bill, err error) {
+	bill, err = s.getOwnedBill(req.Id, userId)
+	return
+}
+
+func (s *service) UpdateBill(id int, userId int, req models.RequestUpdateBill) (bill models.Bill, err error) {
+	existingBill, err := s.getOwnedBill(id, userId)
+	if err != nil {
+		return
+	}
+
+	bill, err = s.buildBill(userId, models.RequestCreateBill(req))
+	if err != nil {
+		return
+	}
+	bill.Id = existingBill.Id
+	bill.CreatedAt = existingBill.CreatedAt
+
+	err = s.Repository.UpdateBill(s.Db, bill)
+	if err != nil {
+		return
+	}
+
+	bill, err = s.Repository.GetBillById(s.Db, id)
+	return
+}
+
+// DeleteBill removes the bill and its payments; linked transactions stay.
+func (s *service) DeleteBill(id int, userId int) (err error) {
+	_, err = s.getOwnedBill(id, userId)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		return s.Repository.DeleteBill(tx, id)
+	})
+	return
+}
+
+// GetBillOccurrences lists the due dates of one bill in the range, which
+// defaults to the current cycle.
+func (s *service) GetBillOccurrences(req models.RequestGetBillOccurrences, userId int) (occurrences []models.BillOccurrence, err error) {
+	bill, err := s.getOwnedBill(req.Id, userId)
+	if err != nil {
+		return
+	}
+
+	startDate, endDate := currentCycle(time.Now())
+	if req.StartDate != "" {
+		startDate = req.StartDate
+	}
+	if req.EndDate != "" {
+		endDate = req.EndDate
+	}
+	from, err := time.ParseInLocation("2006-01-02", startDate, time.Local)
+	if err != nil {
+		err = errors.New("invalid start_date: use YYYY-MM-DD")
+		return
+	}
+	to, err := time.ParseInLocation("2006-01-02", endDate, time.Local)
+	if err != nil {
+		err = errors.New("invalid end_date: use YYYY-MM-DD")
+		return
+	}
+	if to.Before(from) {
+		err = errors.New("invalid date range: end_date is before start_date")
+		return
+	}
+
+	occurrences, err = s.billOccurrences([]models.Bill{bill}, from, to)
+	return
+}
+
+// GetUpcomingBills lists every bill due in the next days, led by the unpaid
+// occurrences that are already overdue.
+func (s *service) GetUpcomingBills(userId int, days int) (occurrences []models.BillOccurrence, err error) {
+	if days <= 0 {
+		days = defaultUpcomingBillDays
+	}
+	if days > maxUpcomingBillDays {
+		err = fmt.Errorf("invalid days: maximum is %d", maxUpcomingBillDays)
+		return
+	}
+
+	bills, err := s.Repository.GetBills(s.Db, userId)
+	if err != nil {
+		return
+	}
+
+	today := startOfDay(time.Now())
+	all, err := s.billOccurrences(bills, today.AddDate(0, 0, -billOverdueLookbackDays), today.AddDate(0, 0, days))
+	if err != nil {
+		return
+	}
+
+	occurrences = []models.BillOccurrence{}
+	for _, occurrence := range all {
+		if occurrence.Status == BillPaid && occurrence.DueDate < today.Format("2006-01-02") {
+			continue
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return
+}
+
+// CreateBillPayment marks an occurrence as paid. A linked transaction must
+// be an expense of the user that pays nothing else; its amount and date are
+// used unless given.
+func (s *service) CreateBillPayment(billId int, userId int, req models.RequestCreateBillPayment) (payment models.BillPayment, err error) {
+	bill, err := s.getOwnedBill(billId, userId)
+	if err != nil {
+		return
+	}
+
+	dueDate, err := time.ParseInLocation("2006-01-02", req.DueDate, time.Local)
+	if err != nil {
+		err = errors.New("invalid due_date: use YYYY-MM-DD")
+		return
+	}
+	if dueDates := billDueDates(bill, dueDate, dueDate); len(dueDates) == 0 {
+		err = errors.New("invalid due_date: the bill is not due on that date")
+		return
+	}
+
+	_, err = s.Repository.FindBillPayment(s.Db, bill.Id, req.DueDate)
+	if err == nil {
+		err = errors.New("bill occurrence is already paid")
+		return
+	}
+	if err != gorm.ErrRecordNotFound {
+		return
+	}
+	err = nil
+
+	amount := req.Amount
+	paidAt := time.Now()
+	if req.TransactionId != nil {
+		transaction, errTransaction := s.Repository.GetTransactionById(s.Db, *req.TransactionId)
+		if errTransaction == gorm.ErrRecordNotFound {
+			err = errors.New("transaction not found")
+			return
+		}
+		if errTransaction != nil {
+			err = errTransaction
+			return
+		}
+		if transaction.UserId != userId {
+			err = errors.New("unauthorized: transaction does not belong to this user")
+			return
+		}
+		if transaction.Type != "expense" {
+			err = errors.New("invalid transaction: a bill must be paid by an expense transaction")
+			return
+		}
+
+		_, errLinked := s.Repository.FindBillPaymentByTransaction(s.Db, transaction.Id)
+		if errLinked == nil {
+			err = errors.New("transaction is already linked to a bill payment")
+			return
+		}
+		if errLinked != gorm.ErrRecordNotFound {
+			err = errLinked
+			return
+		}
+
+		if amount == 0 {
+			amount = transaction.Amount
+		}
+		paidAt = transaction.OccurredAt
+	}
+
+	if amount == 0 {
+		amount = bill.Amount
+	}
+	if amount < 0 {
+		err = errors.New("amount cannot be negative")
+		return
+	}
+
+	if req.Date != "" {
+		paidAt, err = parseOccurredAt(req.Date)
+		if err != nil {
+			err = errors.New("invalid date: use YYYY-MM-DD or RFC 3339 datetime")
+			return
+		}
+	}
+
+	payment, err = s.Repository.CreateBillPayment(s.Db, models.BillPayment{
+		BillId:        bill.Id,
+		UserId:        userId,
+		DueDate:       dueDate,
+		Amount:        amount,
+		PaidAt:        paidAt,
+		TransactionId: req.TransactionId,
+	})
+	return
+}
+
+// DeleteBillPayment marks the occurrence unpaid again; a linked transaction
+// stays.
+func (s *service) DeleteBillPayment(req models.RequestGetBillPayment, userId int) (err error) {
+	_, err = s.getOwnedBill(req.BillId, userId)
+	if err != nil {
+		return
+	}
+
+	payment, err := s.Repository.GetBillPaymentById(s.Db, req.PaymentId)
+	if err != nil {
+		return
+	}
+	if payment.BillId != req.BillId {
+		err = gorm.ErrRecordNotFound
+		return
+	}
+
+	err = s.Repository.DeleteBillPayment(s.Db, payment.Id)
+	return
+}
+
+// CreateCalendarToken issues a new secret for the user's calendar feed and
+// revokes the previous one. The token is returned only here.
+func (s *service) CreateCalendarToken(userId int) (token string, err error) {
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return
+	}
+	token = hex.EncodeToString(secret)
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		return s.Repository.SaveCalendarFeed(tx, models.CalendarFeed{
+			UserId:    userId,
+			TokenHash: hashCalendarToken(token),
+		})
+	})
+	if err != nil {
+		token = ""
+	}
+	return
+}
+
+func (s *service) DeleteCalendarToken(userId int) (err error) {
+	err = s.Repository.DeleteCalendarFeed(s.Db, userId)
+	return
+}
+
+// GetBillCalendar renders the bills of the token's owner as an iCalendar
+// feed: recent overdue occurrences plus everything due in the next year,
+// with a reminder on the unpaid ones.
+func (s *service) GetBillCalendar(token string) (calendar []byte, err error) {
+	feed, err := s.Repository.FindCalendarFeedByTokenHash(s.Db, hashCalendarToken(token))
+	if err == gorm.ErrRecordNotFound {
+		err = errCalendarNotFound
+	}
+	if err != nil {
+		return
+	}
+
+	bills, err := s.Repository.GetBills(s.Db, feed.UserId)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	today := startOfDay(now)
+	occurrences, err := s.billOccurrences(bills, today.AddDate(0, 0, -billOverdueLookbackDays), today.AddDate(0, 0, billCalendarDays))
+	if err != nil {
+		return
+	}
+
+	remindDays := make(map[int]int, len(bills))
+	for _, bill := range bills {
+		remindDays[bill.Id] = bill.RemindDaysBefore
+	}
+
+	events := make([]helper.CalendarEvent, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		date, _ := time.ParseInLocation("2006-01-02", occurrence.DueDate, time.Local)
+
+		amount := strconv.FormatFloat(occurrence.Amount, 'f', -1, 64)
+		if occurrence.AmountType == BillEstimated {
+			amount = "~" + amount
+		}
+		summary := fmt.Sprintf("%s (%s)", occurrence.Name, amount)
+		alarm := remindDays[occurrence.BillId]
+		if occurrence.Status == BillPaid {
+			summary = "Paid: " + summary
+			alarm = -1
+		}
+
+		events = append(events, helper.CalendarEvent{
+			UID:             fmt.Sprintf("bill-%d-%s@go-crud-api", occurrence.BillId, date.Format("20060102")),
+			Date:            date,
+			Summary:         summary,
+			Description:     fmt.Sprintf("Amount: %s (%s)\nStatus: %s", amount, occurrence.AmountType, occurrence.Status),
+			AlarmDaysBefore: alarm,
+		})
+	}
+
+	calendar = helper.BuildCalendar("Bills", events, now)
+	return
+}
+
+// billOccurrences expands the bills into their due dates between from and
+// to and attaches the payment status of each, sorted by due date.
+func (s *service) billOccurrences(bills []models.Bill, from time.Time, to time.Time) (occurrences []models.BillOccurrence, err error) {
+	occurrences = []models.BillOccurrence{}
+	if len(bills) == 0 {
+		return
+	}
+
+	billIds := make([]int, len(bills))
+	for i, bill := range bills {
+		billIds[i] = bill.Id
+	}
+	payments, err := s.Repository.GetBillPayments(s.Db, billIds, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return
+	}
+	paid := make(map[string]models.BillPayment, len(payments))
+	for _, payment := range payments {
+		paid[fmt.Sprintf("%d/%s", payment.BillId, payment.DueDate.Format("2006-01-02"))] = payment
+	}
+
+	today := startOfDay(time.Now())
+	for _, bill := range bills {
+		for _, dueDate := range billDueDates(bill, from, to) {
+			occurrence := models.BillOccurrence{
+				BillId:     bill.Id,
+				Name:       bill.Name,
+				DueDate:    dueDate.Format("2006-01-02"),
+				Amount:     bill.Amount,
+				AmountType: bill.AmountType,
+				Status:     BillUnpaid,
+			}
+			if payment, ok := paid[fmt.Sprintf("%d/%s", bill.Id, occurrence.DueDate)]; ok {
+				paymentId, paidAmount := payment.Id, payment.Amount
+				occurrence.Status = BillPaid
+				occurrence.PaymentId = &paymentId
+				occurrence.PaidAmount = &paidAmount
+				occurrence.TransactionId = payment.TransactionId
+			} else if dueDate.Before(today) {
+				occurrence.Status = BillOverdue
+			}
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		if occurrences[i].DueDate != occurrences[j].DueDate {
+			return occurrences[i].DueDate < occurrences[j].DueDate
+		}
+		return occurrences[i].BillId < occurrences[j].BillId
+	})
+	return
+}
+
+// billDueDates returns the due dates of the bill between from and to, both
+// inclusive. Monthly and yearly dates keep the day of the first due date,
+// clamped to shorter months.
+func billDueDates(bill models.Bill, from time.Time, to time.Time) (dates []time.Time) {
+	first := time.Date(bill.FirstDueDate.Year(), bill.FirstDueDate.Month(), bill.FirstDueDate.Day(), 0, 0, 0, 0, time.Local)
+	from, to = startOfDay(from), startOfDay(to)
+	if bill.EndDate != nil {
+		end := time.Date(bill.EndDate.Year(), bill.EndDate.Month(), bill.EndDate.Day(), 0, 0, 0, 0, time.Local)
+		if end.Before(to) {
+			to = end
+		}
+	}
+
+	for k := 0; k < maxBillOccurrences; k++ {
+		var date time.Time
+		switch bill.Frequency {
+		case BillWeekly:
+			date = first.AddDate(0, 0, 7*bill.Interval*k)
+		case BillMonthly:
+			date = addMonths(first, bill.Interval*k)
+		case BillYearly:
+			date = addMonths(first, 12*bill.Interval*k)
+		default:
+			if k > 0 {
+				return
+			}
+			date = first
+		}
+
+		if date.After(to) {
+			return
+		}
+		if !date.Before(from) {
+			dates = append(dates, date)
+		}
+	}
+	return
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *service) getOwnedBill(id int, userId int) (bill models.Bill, err error) {
+	bill, err = s.Repository.GetBillById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	if bill.UserId != userId {
+		err = errors.New("unauthorized: bill does not belong to this user")
+		return
+	}
+	return
+}
+
+// buildBill validates the request and turns it into a bill of the user.
+func (s *service) buildBill(userId int, req models.RequestCreateBill) (bill models.Bill, err error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		err = errors.New("bill name is required and cannot be empty")
+		return
+	}
+	if len(name) > maxBillNameLength {
+		err = fmt.Errorf("bill name cannot be longer than %d characters", maxBillNameLength)
+		return
+	}
+
+	if req.Amount < 0 {
+		err = errors.New("amount cannot be negative")
+		return
+	}
+
+	amountType := req.AmountType
+	if amountType == "" {
+		amountType = BillFixed
+	}
+	if amountType != BillFixed && amountType != BillEstimated {
+		err = errors.New("amount_type must be fixed or estimated")
+		return
+	}
+	if amountType == BillFixed && req.Amount == 0 {
+		err = errors.New("amount is required for a fixed bill")
+		return
+	}
+
+	if req.CategoryId != nil {
+		err = s.validateCategoryId(s.Db, *req.CategoryId)
+		if err != nil {
+			return
+		}
+	}
+
+	frequency := req.Frequency
+	if frequency == "" {
+		frequency = BillMonthly
+	}
+	if frequency != BillOnce && frequency != BillWeekly && frequency != BillMonthly && frequency != BillYearly {
+		err = errors.New("frequency must be once, weekly, monthly or yearly")
+		return
+	}
+
+	interval := req.Interval
+	if interval == 0 {
+		interval = 1
+	}
+	if interval < 1 || interval > maxBillInterval {
+		err = fmt.Errorf("interval must be between 1 and %d", maxBillInterval)
+		return
+	}
+
+	firstDueDate, err := time.ParseInLocation("2006-01-02", req.FirstDueDate, time.Local)
+	if err != nil {
+		err = errors.New("invalid first_due_date: use YYYY-MM-DD")
+		return
+	}
+
+	var endDate *time.Time
+	if req.EndDate != "" {
+		date, errDate := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+		if errDate != nil {
+			err = errors.New("invalid end_date: use YYYY-MM-DD")
+			return
+		}
+		if date.Before(firstDueDate) {
+			err = errors.New("end_date cannot be before first_due_date")
+			return
+		}
+		endDate = &date
+	}
+
+	remindDays := defaultBillRemindDays
+	if req.RemindDaysBefore != nil {
+		remindDays = *req.RemindDaysBefore
+	}
+	if remindDays < 0 || remindDays > maxBillRemindDays {
+		err = fmt.Errorf("remind_days_before must be between 0 and %d", maxBillRemindDays)
+		return
+	}
+
+	err = validateNote(req.Note)
+	if err != nil {
+		return
+	}
+
+	bill = models.Bill{
+		UserId:           userId,
+		Name:             name,
+		Amount:           req.Amount,
+		AmountType:       amountType,
+		CategoryId:       req.CategoryId,
+		Frequency:        frequency,
+		Interval:         interval,
+		FirstDueDate:     firstDueDate,
+		EndDate:          endDate,
+		RemindDaysBefore: remindDays,
+		Note:             req.Note,
+	}
+	return
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-crud-api/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BulkModeAtomic     = "atomic"
+	BulkModeBestEffort = "best_effort"
+
+	maxBulkItems = 500
+)
+
+var errBulkAborted = errors.New("bulk operation aborted")
+
+// BulkTransactions runs every operation inside one DB transaction. In atomic
+// mode the first failing item rolls everything back; in best_effort mode each
+// item runs under its own savepoint so only the failing items are undone.
+func (s *service) BulkTransactions(userId int, req models.RequestBulkTransactions) (response models.ResponseBulkTransactions, err error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = BulkModeAtomic
+	}
+	if mode != BulkModeAtomic && mode != BulkModeBestEffort {
+		err = errors.New("mode must be atomic or best_effort")
+		return
+	}
+
+	// Validasi: operations wajib diisi dan jumlah item dibatasi
+	if len(req.Operations) == 0 {
+		err = errors.New("operations is required")
+		return
+	}
+
+	totalItems := 0
+	for i, op := range req.Operations {
+		switch op.Op {
+		case "create":
+			totalItems++
+		case "update_category", "change_type", "add_tags", "remove_tags", "delete":
+			if len(op.Ids) == 0 {
+				err = fmt.Errorf("operation %d: ids is required", i)
+				return
+			}
+			totalItems += len(op.Ids)
+		default:
+			err = fmt.Errorf("operation %d: unknown op %q", i, op.Op)
+			return
+		}
+	}
+	if totalItems > maxBulkItems {
+		err = fmt.Errorf("too many items: maximum is %d", maxBulkItems)
+		return
+	}
+
+	response.Mode = mode
+	response.Results = []models.BulkTransactionResult{}
+
+	// Attachment files of deleted transactions, removed only after commit
+	var removedAttachments []models.Attachment
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		for i, op := range req.Operations {
+			ids := op.Ids
+			if op.Op == "create" {
+				ids = []int{0}
+			}
+
+			for _, id := range ids {
+				result := models.BulkTransactionResult{Operation: i, Op: op.Op, Id: id}
+
+				if mode == BulkModeBestEffort {
+					if errSave := tx.SavePoint("bulk_item").Error; errSave != nil {
+						return errSave
+					}
+				}
+
+				transaction, attachments, errItem := s.applyBulkOperation(tx, userId, op, id)
+				if errItem != nil {
+					result.Status = "error"
+					result.Error = errItem.Error()
+					response.Results = append(response.Results, result)
+
+					if mode == BulkModeAtomic {
+						return errBulkAborted
+					}
+					if errRollback := tx.RollbackTo("bulk_item").Error; errRollback != nil {
+						return errRollback
+					}
+					continue
+				}
+
+				result.Status = "ok"
+				removedAttachments = append(removedAttachments, attachments...)
+				if transaction.Id != 0 {
+					transactionResponse := toTransactionResponse(transaction)
+					result.Id = transaction.Id
+					result.Transaction = &transactionResponse
+				}
+				response.Results = append(response.Results, result)
+			}
+		}
+		return nil
+	})
+
+	if err == errBulkAborted {
+		// Items applied before the failure were rolled back with it
+		err = nil
+		for i := range response.Results {
+			if response.Results[i].Status == "ok" {
+				response.Results[i].Status = "rolled_back"
+				response.Results[i].Transaction = nil
+			}
+		}
+	} else if err != nil {
+		return
+	} else {
+		response.Committed = true
+		s.removeStoredFiles(removedAttachments)
+	}
+
+	for _, result := range response.Results {
+		if result.Status == "ok" {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+	return
+}
+
+// applyBulkOperation runs one item through the same create/patch/delete
+// paths as the single-transaction endpoints, including the ownership check.
+func (s *service) applyBulkOperation(tx *gorm.DB, userId int, op models.RequestBulkTransactionOperation, id int) (transaction models.Transaction, attachments []models.Attachment, err error) {
+	switch op.Op {
+	case "create":
+		transaction, err = s.createTransaction(tx, userId, models.RequestCreateTransaction{
+			Uuid:       op.Uuid,
+			Amount:     op.Amount,
+			Type:       op.Type,
+			CategoryId: op.CategoryId,
+			Note:       op.Note,
+			Payee:      op.Payee,
+			OccurredAt: op.OccurredAt,
+			Tags:       op.Tags,
+		})
+	case "update_category":
+		transaction, err = s.patchTransaction(tx, id, userId, models.RequestPatchTransaction{
+			CategoryId: models.Nullable[int]{Set: true, Value: op.CategoryId},
+		})
+	case "change_type":
+		transaction, err = s.patchTransaction(tx, id, userId, models.RequestPatchTransaction{
+			Type: models.Nullable[string]{Set: true, Value: op.Type},
+		})
+	case "add_tags", "remove_tags":
+		transaction, err = s.changeTransactionTags(tx, id, userId, op.Tags, op.Op == "add_tags")
+	case "delete":
+		attachments, err = s.deleteTransaction(tx, id, userId)
+	}
+	return
+}
+
+// changeTransactionTags adds or removes the named tags without touching the
+// other tags of the transaction.
+func (s *service) changeTransactionTags(tx *gorm.DB, id int, userId int, names []string, add bool) (transaction models.Transaction, err error) {
+	err = s.checkTransactionEditable(tx, id, userId)
+	if err != nil {
+		return
+	}
+
+	tagNames, err := normalizeTagNames(names)
+	if err != nil {
+		return
+	}
+	if len(tagNames) == 0 {
+		err = errors.New("tags is required")
+		return
+	}
+
+	tags, err := s.resolveTags(tx, userId, tagNames, add)
+	if err != nil {
+		return
+	}
+
+	if add {
+		err = s.Repository.AddTransactionTags(tx, id, tags)
+	} else if len(tags) > 0 {
+		err = s.Repository.RemoveTransactionTags(tx, id, tags)
+	}
+	if err != nil {
+		return
+	}
+
+	transaction, err = s.recordTransactionUpdated(tx, id)
+	return
+}
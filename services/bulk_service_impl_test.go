@@ -0,0 +1,65 @@
+package services
+
+import (
+	"go-crud-api/models"
+	"slices"
+	"testing"
+)
+
+func TestBulkTransactionsModes(t *testing.T) {
+	tests := []struct {
+		mode          string
+		wantCommitted bool
+		wantStatuses  []string
+		wantSucceeded int
+		wantFailed    int
+		wantType      string // of the user's first transaction afterwards
+		wantRemaining int64
+	}{
+		// The other user's transaction fails and undoes the whole batch
+		{BulkModeAtomic, false, []string{"rolled_back", "error"}, 0, 2, "expense", 3},
+		// Only the failing item is undone
+		{BulkModeBestEffort, true, []string{"ok", "error", "ok"}, 2, 1, "income", 2},
+	}
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			s := newTestService(t)
+			own := createTestTransaction(t, s, models.Transaction{UserId: 1, Amount: 10000})
+			other := createTestTransaction(t, s, models.Transaction{UserId: 2, Amount: 20000})
+			deleted := createTestTransaction(t, s, models.Transaction{UserId: 1, Amount: 30000})
+
+			response, err := s.BulkTransactions(1, models.RequestBulkTransactions{
+				Mode: test.mode,
+				Operations: []models.RequestBulkTransactionOperation{
+					{Op: "change_type", Ids: []int{own.Id, other.Id}, Type: "income"},
+					{Op: "delete", Ids: []int{deleted.Id}},
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var statuses []string
+			for _, result := range response.Results {
+				statuses = append(statuses, result.Status)
+			}
+			if !slices.Equal(statuses, test.wantStatuses) {
+				t.Errorf("expected statuses %v, got %v", test.wantStatuses, statuses)
+			}
+			if response.Committed != test.wantCommitted || response.Succeeded != test.wantSucceeded || response.Failed != test.wantFailed {
+				t.Errorf("expected committed %v, %d succeeded and %d failed, got %+v", test.wantCommitted, test.wantSucceeded, test.wantFailed, response)
+			}
+
+			var stored models.Transaction
+			s.Db.First(&stored, own.Id)
+			if stored.Type != test.wantType {
+				t.Errorf("expected the first transaction to be %s, got %s", test.wantType, stored.Type)
+			}
+			var remaining int64
+			s.Db.Model(&models.Transaction{}).Count(&remaining)
+			if remaining != test.wantRemaining {
+				t.Errorf("expected %d transactions left, got %d", test.wantRemaining, remaining)
+			}
+		})
+	}
+}
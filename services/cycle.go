@@ -0,0 +1,25 @@
+package services
+
+import "time"
+
+// cycleStartDay is the payday-based day of month on which a budget cycle
+// starts; a cycle runs from the 27th to the 26th of the following month.
+const cycleStartDay = 27
+
+// currentCycle returns the first and last day (YYYY-MM-DD) of the cycle
+// containing now.
+func currentCycle(now time.Time) (startDate string, endDate string) {
+	start, end := cycleBounds(now)
+	return start.Format("2006-01-02"), end.Format("2006-01-02")
+}
+
+// cycleBounds returns the first day of the cycle containing t and the last
+// day of that cycle, both at midnight.
+func cycleBounds(t time.Time) (start time.Time, end time.Time) {
+	start = time.Date(t.Year(), t.Month(), cycleStartDay, 0, 0, 0, 0, t.Location())
+	if t.Day() < cycleStartDay {
+		start = start.AddDate(0, -1, 0)
+	}
+	end = start.AddDate(0, 1, -1)
+	return
+}
@@ -0,0 +1,453 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-crud-api/models"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DebtBorrowed = "borrowed"
+	DebtLent     = "lent"
+
+	InterestAnnuity = "annuity"
+	InterestFlat    = "flat"
+
+	InstallmentPaid     = "paid"
+	InstallmentPartial  = "partial"
+	InstallmentOverdue  = "overdue"
+	InstallmentUpcoming = "upcoming"
+
+	maxDebtNameLength  = 100
+	maxDebtTermMonths  = 600
+	defaultUpcomingMax = 3
+)
+
+func (s *service) CreateDebt(userId int, req models.RequestCreateDebt) (debt models.Debt, err error) {
+	debt, err = buildDebt(userId, req)
+	if err != nil {
+		return
+	}
+
+	debt, err = s.Repository.CreateDebt(s.Db, debt)
+	return
+}
+
+func (s *service) GetDebts(userId int) (debts []models.Debt, err error) {
+	debts, err = s.Repository.GetDebts(s.Db, userId)
+	if debts == nil {
+		debts = []models.Debt{}
+	}
+	return
+}
+
+func (s *service) GetDebtById(req models.RequestGetDebtById, userId int) (debt models.Debt, err error) {
+	debt, err = s.getOwnedDebt(req.Id, userId)
+	return
+}
+
+func (s *service) UpdateDebt(id int, userId int, req models.RequestUpdateDebt) (debt models.Debt, err error) {
+	existingDebt, err := s.getOwnedDebt(id, userId)
+	if err != nil {
+		return
+	}
+
+	debt, err = buildDebt(userId, models.RequestCreateDebt(req))
+	if err != nil {
+		return
+	}
+	debt.Id = existingDebt.Id
+	debt.CreatedAt = existingDebt.CreatedAt
+
+	err = s.Repository.UpdateDebt(s.Db, debt)
+	if err != nil {
+		return
+	}
+
+	debt, err = s.Repository.GetDebtById(s.Db, id)
+	return
+}
+
+// DeleteDebt removes the debt and its payments; linked transactions stay.
+func (s *service) DeleteDebt(id int, userId int) (err error) {
+	_, err = s.getOwnedDebt(id, userId)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		return s.Repository.DeleteDebt(tx, id)
+	})
+	return
+}
+
+func (s *service) GetDebtSchedule(req models.RequestGetDebtById, userId int) (response models.ResponseDebtSchedule, err error) {
+	debt, err := s.getOwnedDebt(req.Id, userId)
+	if err != nil {
+		return
+	}
+
+	installments, _, err := s.debtInstallments(debt)
+	if err != nil {
+		return
+	}
+
+	response.Debt = debt
+	response.Installments = installments
+	return
+}
+
+// GetDebtSummary reports the outstanding balance and the next unpaid
+// installments, oldest first so overdue ones lead.
+func (s *service) GetDebtSummary(req models.RequestGetDebtById, userId int, upcomingLimit int) (response models.ResponseDebtSummary, err error) {
+	debt, err := s.getOwnedDebt(req.Id, userId)
+	if err != nil {
+		return
+	}
+
+	if upcomingLimit <= 0 {
+		upcomingLimit = defaultUpcomingMax
+	}
+
+	installments, paidTotal, err := s.debtInstallments(debt)
+	if err != nil {
+		return
+	}
+
+	response.Debt = debt
+	response.InstallmentAmount = installments[0].Payment
+	response.PaidTotal = roundMoney(paidTotal)
+	response.Upcoming = []models.Installment{}
+
+	for _, installment := range installments {
+		response.TotalPayable += installment.Payment
+		response.TotalInterest += installment.Interest
+
+		switch installment.Status {
+		case InstallmentPaid:
+			response.PaidInstallments++
+			continue
+		case InstallmentOverdue:
+			response.OverdueAmount += installment.Payment - installment.Paid
+		}
+
+		if response.NextDueDate == nil {
+			dueDate := installment.DueDate
+			response.NextDueDate = &dueDate
+		}
+		if len(response.Upcoming) < upcomingLimit {
+			response.Upcoming = append(response.Upcoming, installment)
+		}
+	}
+
+	response.TotalPayable = roundMoney(response.TotalPayable)
+	response.TotalInterest = roundMoney(response.TotalInterest)
+	response.RemainingPayable = roundMoney(math.Max(response.TotalPayable-paidTotal, 0))
+	response.OutstandingPrincipal = outstandingPrincipal(debt, installments)
+	response.OverdueAmount = roundMoney(response.OverdueAmount)
+	return
+}
+
+// CreateDebtPayment records a payment. When a transaction is linked, its
+// amount and date are used unless given, and its type must match the
+// direction: expense for a borrowed debt, income for a lent one.
+func (s *service) CreateDebtPayment(debtId int, userId int, req models.RequestCreateDebtPayment) (payment models.DebtPayment, err error) {
+	debt, err := s.getOwnedDebt(debtId, userId)
+	if err != nil {
+		return
+	}
+
+	err = validateNote(req.Note)
+	if err != nil {
+		return
+	}
+
+	amount := req.Amount
+	paidAt := time.Now()
+	if req.TransactionId != nil {
+		transaction, errTransaction := s.Repository.GetTransactionById(s.Db, *req.TransactionId)
+		if errTransaction == gorm.ErrRecordNotFound {
+			err = errors.New("transaction not found")
+			return
+		}
+		if errTransaction != nil {
+			err = errTransaction
+			return
+		}
+		if transaction.UserId != userId {
+			err = errors.New("unauthorized: transaction does not belong to this user")
+			return
+		}
+
+		expectedType := "expense"
+		if debt.Direction == DebtLent {
+			expectedType = "income"
+		}
+		if transaction.Type != expectedType {
+			err = fmt.Errorf("invalid transaction: a payment of a %s debt must be an %s transaction", debt.Direction, expectedType)
+			return
+		}
+
+		_, errLinked := s.Repository.FindDebtPaymentByTransaction(s.Db, transaction.Id)
+		if errLinked == nil {
+			err = errors.New("transaction is already linked to a debt payment")
+			return
+		}
+		if errLinked != gorm.ErrRecordNotFound {
+			err = errLinked
+			return
+		}
+
+		if amount == 0 {
+			amount = transaction.Amount
+		}
+		paidAt = transaction.OccurredAt
+	}
+
+	if amount <= 0 {
+		err = errors.New("amount must be greater than 0")
+		return
+	}
+
+	if req.Date != "" {
+		paidAt, err = parseOccurredAt(req.Date)
+		if err != nil {
+			err = errors.New("invalid date: use YYYY-MM-DD or RFC 3339 datetime")
+			return
+		}
+	}
+
+	payment, err = s.Repository.CreateDebtPayment(s.Db, models.DebtPayment{
+		DebtId:        debtId,
+		UserId:        userId,
+		Amount:        amount,
+		PaidAt:        paidAt,
+		TransactionId: req.TransactionId,
+		Note:          req.Note,
+	})
+	return
+}
+
+func (s *service) GetDebtPayments(debtId int, userId int) (payments []models.DebtPayment, err error) {
+	_, err = s.getOwnedDebt(debtId, userId)
+	if err != nil {
+		return
+	}
+
+	payments, err = s.Repository.GetDebtPayments(s.Db, debtId)
+	if payments == nil {
+		payments = []models.DebtPayment{}
+	}
+	return
+}
+
+// DeleteDebtPayment removes the payment only; a linked transaction stays.
+func (s *service) DeleteDebtPayment(req models.RequestGetDebtPayment, userId int) (err error) {
+	_, err = s.getOwnedDebt(req.DebtId, userId)
+	if err != nil {
+		return
+	}
+
+	payment, err := s.Repository.GetDebtPaymentById(s.Db, req.PaymentId)
+	if err != nil {
+		return
+	}
+	if payment.DebtId != req.DebtId {
+		err = gorm.ErrRecordNotFound
+		return
+	}
+
+	err = s.Repository.DeleteDebtPayment(s.Db, payment.Id)
+	return
+}
+
+// debtInstallments builds the schedule and applies the recorded payments to
+// the installments in due order.
+func (s *service) debtInstallments(debt models.Debt) (installments []models.Installment, paidTotal float64, err error) {
+	payments, err := s.Repository.GetDebtPayments(s.Db, debt.Id)
+	if err != nil {
+		return
+	}
+	for _, payment := range payments {
+		paidTotal += payment.Amount
+	}
+
+	installments = amortizationSchedule(debt)
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).Format("2006-01-02")
+	left := paidTotal
+	for i := range installments {
+		installment := &installments[i]
+		installment.Paid = roundMoney(math.Min(left, installment.Payment))
+		left -= installment.Paid
+
+		switch {
+		case installment.Paid >= installment.Payment:
+			installment.Status = InstallmentPaid
+		case installment.DueDate < today:
+			installment.Status = InstallmentOverdue
+		case installment.Paid > 0:
+			installment.Status = InstallmentPartial
+		default:
+			installment.Status = InstallmentUpcoming
+		}
+	}
+	return
+}
+
+// outstandingPrincipal is the principal not yet covered by the payments
+// applied to the installments. A partial payment covers the interest first.
+func outstandingPrincipal(debt models.Debt, installments []models.Installment) float64 {
+	var covered float64
+	for _, installment := range installments {
+		covered += math.Max(math.Min(installment.Paid-installment.Interest, installment.Principal), 0)
+	}
+	return roundMoney(math.Max(debt.Principal-covered, 0))
+}
+
+// amortizationSchedule splits the debt into monthly installments. Annuity
+// keeps the installment constant with interest on the remaining balance;
+// flat charges interest on the original principal every month. Rounding
+// leftovers go to the last installment.
+func amortizationSchedule(debt models.Debt) (installments []models.Installment) {
+	monthlyRate := debt.AnnualInterestRate / 100 / 12
+	months := debt.TermMonths
+
+	var annuityPayment float64
+	if debt.InterestMethod == InterestAnnuity {
+		if monthlyRate == 0 {
+			annuityPayment = roundMoney(debt.Principal / float64(months))
+		} else {
+			annuityPayment = roundMoney(debt.Principal * monthlyRate / (1 - math.Pow(1+monthlyRate, -float64(months))))
+		}
+	}
+
+	balance := debt.Principal
+	installments = make([]models.Installment, 0, months)
+	for number := 1; number <= months; number++ {
+		var interest, principal float64
+		if debt.InterestMethod == InterestFlat {
+			interest = roundMoney(debt.Principal * monthlyRate)
+			principal = roundMoney(debt.Principal / float64(months))
+		} else {
+			interest = roundMoney(balance * monthlyRate)
+			principal = roundMoney(annuityPayment - interest)
+		}
+		if number == months || principal > balance {
+			principal = roundMoney(balance)
+		}
+		balance = roundMoney(balance - principal)
+
+		installments = append(installments, models.Installment{
+			Number:    number,
+			DueDate:   addMonths(debt.StartDate, number).Format("2006-01-02"),
+			Payment:   roundMoney(principal + interest),
+			Principal: principal,
+			Interest:  interest,
+			Balance:   balance,
+		})
+	}
+	return
+}
+
+// addMonths moves t by months, clamping to the last day of a shorter month
+// so an installment started on the 31st falls on the 30th or 28th.
+func addMonths(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, t.Location())
+}
+
+func (s *service) getOwnedDebt(id int, userId int) (debt models.Debt, err error) {
+	debt, err = s.Repository.GetDebtById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	if debt.UserId != userId {
+		err = errors.New("unauthorized: debt does not belong to this user")
+		return
+	}
+	return
+}
+
+// buildDebt validates the request and turns it into a debt of the user.
+func buildDebt(userId int, req models.RequestCreateDebt) (debt models.Debt, err error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		err = errors.New("debt name is required and cannot be empty")
+		return
+	}
+	if len(name) > maxDebtNameLength {
+		err = fmt.Errorf("debt name cannot be longer than %d characters", maxDebtNameLength)
+		return
+	}
+
+	if req.Direction != DebtBorrowed && req.Direction != DebtLent {
+		err = errors.New("direction must be borrowed or lent")
+		return
+	}
+
+	counterparty := strings.TrimSpace(req.Counterparty)
+	if len(counterparty) > maxDebtNameLength {
+		err = fmt.Errorf("counterparty cannot be longer than %d characters", maxDebtNameLength)
+		return
+	}
+
+	if req.Principal <= 0 {
+		err = errors.New("principal must be greater than 0")
+		return
+	}
+
+	if req.AnnualInterestRate < 0 || req.AnnualInterestRate > 100 {
+		err = errors.New("annual_interest_rate must be between 0 and 100")
+		return
+	}
+
+	method := req.InterestMethod
+	if method == "" {
+		method = InterestAnnuity
+	}
+	if method != InterestAnnuity && method != InterestFlat {
+		err = errors.New("interest_method must be annuity or flat")
+		return
+	}
+
+	if req.TermMonths < 1 || req.TermMonths > maxDebtTermMonths {
+		err = fmt.Errorf("term_months must be between 1 and %d", maxDebtTermMonths)
+		return
+	}
+
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if req.StartDate != "" {
+		startDate, err = time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+		if err != nil {
+			err = errors.New("invalid start_date: use YYYY-MM-DD")
+			return
+		}
+	}
+
+	debt = models.Debt{
+		UserId:             userId,
+		Name:               name,
+		Direction:          req.Direction,
+		Counterparty:       counterparty,
+		Principal:          req.Principal,
+		AnnualInterestRate: req.AnnualInterestRate,
+		InterestMethod:     method,
+		TermMonths:         req.TermMonths,
+		StartDate:          startDate,
+	}
+	return
+}
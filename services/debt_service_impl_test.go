@@ -0,0 +1,72 @@
+package services
+
+import (
+	"go-crud-api/models"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAmortizationSchedule(t *testing.T) {
+	start := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		debt         models.Debt
+		wantFirst    models.Installment
+		wantLast     models.Installment
+		wantInterest float64
+	}{
+		{
+			// Rounding leftovers go to the last installment
+			name:         "zero-interest loan",
+			debt:         models.Debt{Principal: 1000, TermMonths: 3, InterestMethod: InterestAnnuity, StartDate: start},
+			wantFirst:    models.Installment{Number: 1, DueDate: "2026-02-28", Payment: 333.33, Principal: 333.33, Balance: 666.67},
+			wantLast:     models.Installment{Number: 3, DueDate: "2026-04-30", Payment: 333.34, Principal: 333.34},
+			wantInterest: 0,
+		},
+		{
+			name:         "zero-interest flat loan",
+			debt:         models.Debt{Principal: 1000, TermMonths: 3, InterestMethod: InterestFlat, StartDate: start},
+			wantFirst:    models.Installment{Number: 1, DueDate: "2026-02-28", Payment: 333.33, Principal: 333.33, Balance: 666.67},
+			wantLast:     models.Installment{Number: 3, DueDate: "2026-04-30", Payment: 333.34, Principal: 333.34},
+			wantInterest: 0,
+		},
+		{
+			name:         "annuity",
+			debt:         models.Debt{Principal: 10000000, AnnualInterestRate: 12, TermMonths: 12, InterestMethod: InterestAnnuity, StartDate: start},
+			wantFirst:    models.Installment{Number: 1, DueDate: "2026-02-28", Payment: 888487.89, Principal: 788487.89, Interest: 100000, Balance: 9211512.11},
+			wantLast:     models.Installment{Number: 12, DueDate: "2027-01-31", Payment: 888487.85, Principal: 879690.94, Interest: 8796.91},
+			wantInterest: 661854.64,
+		},
+		{
+			name:         "flat",
+			debt:         models.Debt{Principal: 12000000, AnnualInterestRate: 12, TermMonths: 12, InterestMethod: InterestFlat, StartDate: start},
+			wantFirst:    models.Installment{Number: 1, DueDate: "2026-02-28", Payment: 1120000, Principal: 1000000, Interest: 120000, Balance: 11000000},
+			wantLast:     models.Installment{Number: 12, DueDate: "2027-01-31", Payment: 1120000, Principal: 1000000, Interest: 120000},
+			wantInterest: 1440000,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			installments := amortizationSchedule(test.debt)
+			if len(installments) != test.debt.TermMonths {
+				t.Fatalf("expected %d installments, got %d", test.debt.TermMonths, len(installments))
+			}
+			if first := installments[0]; first != test.wantFirst {
+				t.Errorf("expected first installment %+v, got %+v", test.wantFirst, first)
+			}
+			if last := installments[len(installments)-1]; last != test.wantLast {
+				t.Errorf("expected last installment %+v, got %+v", test.wantLast, last)
+			}
+
+			var principal, interest float64
+			for _, installment := range installments {
+				principal += installment.Principal
+				interest += installment.Interest
+			}
+			if roundMoney(principal) != test.debt.Principal || math.Abs(roundMoney(interest)-test.wantInterest) > 0.001 {
+				t.Errorf("expected principal %.2f and interest %.2f in total, got %.2f and %.2f", test.debt.Principal, test.wantInterest, principal, interest)
+			}
+		})
+	}
+}
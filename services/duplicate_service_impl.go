@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"math"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultDuplicateWindow = 24 * time.Hour
+	maxDuplicateWindow     = 31 * 24 * time.Hour
+)
+
+// GetDuplicates lists candidate duplicate pairs among the user's
+// transactions: same amount, type and category within the time window, and
+// optionally with similar payees.
+func (s *service) GetDuplicates(req models.RequestGetDuplicates) (response models.ResponseDuplicateList, err error) {
+	window, err := parseDuplicateWindow(req.Window)
+	if err != nil {
+		return
+	}
+
+	threshold := 0.0
+	if req.PayeeSimilarity != "" {
+		threshold, err = strconv.ParseFloat(req.PayeeSimilarity, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			err = errors.New("invalid payee_similarity: must be a number between 0 and 1")
+			return
+		}
+	}
+
+	// Default to the current cycle, like GetBalance
+	startDate := req.StartDate
+	endDate := req.EndDate
+	if startDate == "" || endDate == "" {
+		startDate, endDate = currentCycle(time.Now())
+	}
+	if _, errDate := time.Parse("2006-01-02", startDate); errDate != nil {
+		err = errors.New("invalid start_date: use YYYY-MM-DD")
+		return
+	}
+	if _, errDate := time.Parse("2006-01-02", endDate); errDate != nil {
+		err = errors.New("invalid end_date: use YYYY-MM-DD")
+		return
+	}
+
+	response = models.ResponseDuplicateList{
+		Window:    window.String(),
+		StartDate: startDate,
+		EndDate:   endDate,
+		Data:      []models.DuplicateCandidate{},
+	}
+
+	pairs, err := s.Repository.FindDuplicatePairs(s.Db, req.UserId, window, startDate, endDate)
+	if err != nil || len(pairs) == 0 {
+		return
+	}
+
+	var ids []int
+	for _, pair := range pairs {
+		ids = append(ids, pair.TransactionId, pair.DuplicateId)
+	}
+	transactions, err := s.Repository.GetTransactionsByIds(s.Db, ids)
+	if err != nil {
+		return
+	}
+	byId := map[int]models.Transaction{}
+	for _, transaction := range transactions {
+		byId[transaction.Id] = transaction
+	}
+
+	for _, pair := range pairs {
+		transaction, duplicate := byId[pair.TransactionId], byId[pair.DuplicateId]
+
+		similarity := helper.StringSimilarity(transaction.Payee, duplicate.Payee)
+		if threshold > 0 && similarity < threshold {
+			continue
+		}
+
+		response.Data = append(response.Data, models.DuplicateCandidate{
+			Transaction:     toTransactionResponse(transaction),
+			Duplicate:       toTransactionResponse(duplicate),
+			TimeDifference:  int64(math.Abs(duplicate.OccurredAt.Sub(transaction.OccurredAt).Seconds())),
+			PayeeSimilarity: math.Round(similarity*100) / 100,
+		})
+	}
+	response.Count = len(response.Data)
+	return
+}
+
+// MergeDuplicate folds the duplicate into the kept transaction: its tags and
+// attachments move over, an empty note or payee is filled from it, and the
+// duplicate is deleted.
+func (s *service) MergeDuplicate(userId int, req models.RequestMergeDuplicate) (response models.TransactionResponse, err error) {
+	if req.KeepId == req.DuplicateId {
+		err = errors.New("keep_id and duplicate_id must be different transactions")
+		return
+	}
+
+	var transaction models.Transaction
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		transaction, errTx = s.mergeTransactions(tx, userId, req.KeepId, req.DuplicateId)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	response = toTransactionResponse(transaction)
+	return
+}
+
+func (s *service) mergeTransactions(tx *gorm.DB, userId int, keepId int, duplicateId int) (transaction models.Transaction, err error) {
+	for _, id := range []int{keepId, duplicateId} {
+		err = s.checkTransactionEditable(tx, id, userId)
+		if err != nil {
+			return
+		}
+	}
+
+	keep, err := s.Repository.GetTransactionById(tx, keepId)
+	if err != nil {
+		return
+	}
+	duplicate, err := s.Repository.GetTransactionById(tx, duplicateId)
+	if err != nil {
+		return
+	}
+
+	updateData := map[string]interface{}{}
+	if keep.Note == "" && duplicate.Note != "" {
+		updateData["note"] = duplicate.Note
+	}
+	if keep.Payee == "" && duplicate.Payee != "" {
+		updateData["payee"] = duplicate.Payee
+	}
+	if len(updateData) > 0 {
+		err = s.Repository.UpdateTransactionFields(tx, keepId, updateData)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(duplicate.Tags) > 0 {
+		err = s.Repository.AddTransactionTags(tx, keepId, duplicate.Tags)
+		if err != nil {
+			return
+		}
+	}
+
+	err = s.Repository.MoveAttachments(tx, duplicateId, keepId)
+	if err != nil {
+		return
+	}
+
+	// The attachments were moved, so there are no files left to remove
+	_, err = s.deleteTransaction(tx, duplicateId, userId)
+	if err != nil {
+		return
+	}
+
+	transaction, err = s.recordTransactionUpdated(tx, keepId)
+	return
+}
+
+// DismissDuplicate hides a candidate pair from later duplicate listings.
+func (s *service) DismissDuplicate(userId int, req models.RequestDismissDuplicate) (dismissal models.DuplicateDismissal, err error) {
+	if req.TransactionId == req.DuplicateId {
+		err = errors.New("transaction_id and duplicate_id must be different transactions")
+		return
+	}
+
+	for _, id := range []int{req.TransactionId, req.DuplicateId} {
+		err = s.checkTransactionOwner(s.Db, id, userId)
+		if err != nil {
+			return
+		}
+	}
+
+	dismissal = models.DuplicateDismissal{
+		UserId:        userId,
+		TransactionId: min(req.TransactionId, req.DuplicateId),
+		DuplicateId:   max(req.TransactionId, req.DuplicateId),
+	}
+	dismissal, err = s.Repository.CreateDuplicateDismissal(s.Db, dismissal)
+	return
+}
+
+// findLikelyDuplicates returns the user's existing transactions that an
+// incoming one probably duplicates, used to flag rows before importing.
+func (s *service) findLikelyDuplicates(db *gorm.DB, userId int, transaction models.Transaction, window time.Duration) (duplicates []models.Transaction, err error) {
+	from := transaction.OccurredAt.Add(-window)
+	to := transaction.OccurredAt.Add(window)
+	duplicates, err = s.Repository.FindSimilarTransactions(db, userId, transaction.Amount, transaction.Type, transaction.CategoryId, from, to)
+	return
+}
+
+func parseDuplicateWindow(value string) (window time.Duration, err error) {
+	if value == "" {
+		return defaultDuplicateWindow, nil
+	}
+	window, err = time.ParseDuration(value)
+	if err != nil || window <= 0 || window > maxDuplicateWindow {
+		err = errors.New("invalid window: use a duration such as 30m or 48h, up to 744h")
+	}
+	return
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"errors"
+	"go-crud-api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GetEnvelopes rolls the envelope budget forward from the first cycle the
+// user budgeted in up to the cycle containing the requested day. Cycles are
+// the same 27th-to-26th cycles as GetBalance.
+//
+// Income outside the envelope categories funds the money available to
+// assign, spending outside them draws from it, and whatever was left before
+// the first budgeted cycle is the opening amount. Income and expense inside
+// an envelope category are that envelope's activity.
+func (s *service) GetEnvelopes(req models.RequestGetEnvelopes, userId int) (response models.ResponseEnvelopes, err error) {
+	day := time.Now()
+	if req.Date != "" {
+		day, err = time.ParseInLocation("2006-01-02", req.Date, time.Local)
+		if err != nil {
+			err = errors.New("invalid date: use YYYY-MM-DD")
+			return
+		}
+	}
+	targetStart, targetEnd := cycleBounds(day)
+
+	envelopes, err := s.Repository.GetEnvelopeCategories(s.Db, userId)
+	if err != nil {
+		return
+	}
+	assignments, err := s.Repository.GetEnvelopeAssignments(s.Db, userId, targetStart.Format("2006-01-02"))
+	if err != nil {
+		return
+	}
+
+	// Budgeting starts with the earliest envelope or assignment
+	firstStart := targetStart
+	for _, envelope := range envelopes {
+		if start, _ := cycleBounds(envelope.CreatedAt.In(time.Local)); start.Before(firstStart) {
+			firstStart = start
+		}
+	}
+	assigned := map[string]map[int]float64{}
+	for _, assignment := range assignments {
+		cycle := assignment.CycleStart.Format("2006-01-02")
+		if assigned[cycle] == nil {
+			assigned[cycle] = map[int]float64{}
+		}
+		assigned[cycle][assignment.CategoryId] += assignment.Amount
+
+		start := time.Date(assignment.CycleStart.Year(), assignment.CycleStart.Month(), assignment.CycleStart.Day(), 0, 0, 0, 0, time.Local)
+		if start.Before(firstStart) {
+			firstStart = start
+		}
+	}
+
+	isEnvelope := make(map[int]bool, len(envelopes))
+	for _, envelope := range envelopes {
+		isEnvelope[envelope.CategoryId] = true
+	}
+
+	opening, _, err := s.Repository.SumTransactions(s.Db, models.QueryTransactionFilter{
+		UserId:  userId,
+		EndDate: firstStart.AddDate(0, 0, -1).Format("2006-01-02"),
+	})
+	if err != nil {
+		return
+	}
+	toAssign := opening
+
+	available := make(map[int]float64, len(envelopes))
+	for cycleStart := firstStart; !cycleStart.After(targetStart); cycleStart = cycleStart.AddDate(0, 1, 0) {
+		_, cycleEnd := cycleBounds(cycleStart)
+		totals, errTotals := s.Repository.SumTransactionsByCategory(s.Db, userId, cycleStart.Format("2006-01-02"), cycleEnd.Format("2006-01-02"))
+		if errTotals != nil {
+			err = errTotals
+			return
+		}
+
+		isTarget := cycleStart.Equal(targetStart)
+		activity := map[int]float64{}
+		for _, total := range totals {
+			amount := total.Total
+			if total.Type == "expense" {
+				amount = -amount
+			} else if total.Type != "income" {
+				continue
+			}
+
+			if isEnvelope[total.CategoryId] {
+				activity[total.CategoryId] += amount
+				continue
+			}
+			toAssign += amount
+			if isTarget {
+				if amount > 0 {
+					response.Income += amount
+				} else {
+					response.UnbudgetedSpending -= amount
+				}
+			}
+		}
+
+		cycleAssigned := assigned[cycleStart.Format("2006-01-02")]
+		for _, envelope := range envelopes {
+			carriedIn := available[envelope.CategoryId]
+			if carriedIn > 0 && !envelope.Rollover {
+				// The leftover goes back to be assigned again
+				toAssign += carriedIn
+				carriedIn = 0
+			}
+
+			assignedAmount := cycleAssigned[envelope.CategoryId]
+			toAssign -= assignedAmount
+			available[envelope.CategoryId] = carriedIn + assignedAmount + activity[envelope.CategoryId]
+
+			if isTarget {
+				response.Envelopes = append(response.Envelopes, models.Envelope{
+					CategoryId:   envelope.CategoryId,
+					CategoryName: envelope.Category.Name,
+					Rollover:     envelope.Rollover,
+					CarriedIn:    roundMoney(carriedIn),
+					Assigned:     roundMoney(assignedAmount),
+					Activity:     roundMoney(activity[envelope.CategoryId]),
+					Available:    roundMoney(available[envelope.CategoryId]),
+				})
+			}
+		}
+	}
+
+	if response.Envelopes == nil {
+		response.Envelopes = []models.Envelope{}
+	}
+	for _, envelope := range response.Envelopes {
+		response.Assigned += envelope.Assigned
+		response.Activity += envelope.Activity
+		response.Available += envelope.Available
+		if envelope.Available < 0 {
+			response.Overspent -= envelope.Available
+		}
+	}
+
+	response.CycleStart = targetStart.Format("2006-01-02")
+	response.CycleEnd = targetEnd.Format("2006-01-02")
+	response.Income = roundMoney(response.Income)
+	response.UnbudgetedSpending = roundMoney(response.UnbudgetedSpending)
+	response.Assigned = roundMoney(response.Assigned)
+	response.Activity = roundMoney(response.Activity)
+	response.Available = roundMoney(response.Available)
+	response.Overspent = roundMoney(response.Overspent)
+	response.AvailableToAssign = roundMoney(toAssign)
+	return
+}
+
+// SetEnvelopeCategory turns the category into an envelope of the user, or
+// changes its rollover.
+func (s *service) SetEnvelopeCategory(req models.RequestSetEnvelopeCategory, userId int) (envelope models.EnvelopeCategory, err error) {
+	err = s.validateCategoryId(s.Db, req.CategoryId)
+	if err != nil {
+		return
+	}
+
+	rollover := true
+	if req.Rollover != nil {
+		rollover = *req.Rollover
+	}
+
+	envelope, err = s.Repository.SaveEnvelopeCategory(s.Db, models.EnvelopeCategory{
+		UserId:     userId,
+		CategoryId: req.CategoryId,
+		Rollover:   rollover,
+	})
+	return
+}
+
+// DeleteEnvelopeCategory stops budgeting the category with envelopes and
+// drops its assignments, so the money returns to be assigned.
+func (s *service) DeleteEnvelopeCategory(req models.RequestGetEnvelopeCategory, userId int) (err error) {
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		return s.Repository.DeleteEnvelopeCategory(tx, userId, req.CategoryId)
+	})
+	return
+}
+
+// AssignEnvelope sets the amount assigned to the category in the cycle
+// containing the date. The category becomes an envelope, with rollover,
+// if it is not one yet.
+func (s *service) AssignEnvelope(req models.RequestAssignEnvelope, userId int) (assignment models.EnvelopeAssignment, err error) {
+	err = s.validateCategoryId(s.Db, req.CategoryId)
+	if err != nil {
+		return
+	}
+
+	if req.Amount < 0 {
+		err = errors.New("amount cannot be negative")
+		return
+	}
+
+	day := time.Now()
+	if req.Date != "" {
+		day, err = time.ParseInLocation("2006-01-02", req.Date, time.Local)
+		if err != nil {
+			err = errors.New("invalid date: use YYYY-MM-DD")
+			return
+		}
+	}
+	cycleStart, _ := cycleBounds(day)
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		envelopes, errList := s.Repository.GetEnvelopeCategories(tx, userId)
+		if errList != nil {
+			return errList
+		}
+		exists := false
+		for _, envelope := range envelopes {
+			exists = exists || envelope.CategoryId == req.CategoryId
+		}
+		if !exists {
+			_, errSave := s.Repository.SaveEnvelopeCategory(tx, models.EnvelopeCategory{
+				UserId:     userId,
+				CategoryId: req.CategoryId,
+				Rollover:   true,
+			})
+			if errSave != nil {
+				return errSave
+			}
+		}
+
+		saved, errSave := s.Repository.SaveEnvelopeAssignment(tx, models.EnvelopeAssignment{
+			UserId:     userId,
+			CategoryId: req.CategoryId,
+			CycleStart: cycleStart,
+			Amount:     req.Amount,
+		})
+		if errSave != nil {
+			return errSave
+		}
+		assignment = saved
+		return nil
+	})
+	return
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"go-crud-api/models"
+	"testing"
+	"time"
+)
+
+func TestGetEnvelopesRollover(t *testing.T) {
+	// Cycle 2026-08-27..2026-09-26, then 2026-09-27..2026-10-26
+	firstCycle := time.Date(2026, 8, 27, 0, 0, 0, 0, time.Local)
+	secondCycle := time.Date(2026, 9, 27, 0, 0, 0, 0, time.Local)
+	noon := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 12, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name          string
+		rollover      bool
+		wantCarriedIn float64
+		wantAvailable float64
+		wantToAssign  float64
+	}{
+		{
+			name:          "leftover rolls over",
+			rollover:      true,
+			wantCarriedIn: 200000,
+			wantAvailable: 250000,
+			wantToAssign:  350000,
+		},
+		{
+			// The leftover goes back to be assigned again
+			name:          "leftover without rollover",
+			rollover:      false,
+			wantCarriedIn: 0,
+			wantAvailable: 50000,
+			wantToAssign:  550000,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestService(t)
+			for _, category := range []models.Category{
+				{Id: 2, Uuid: newUuid(), Name: "Belanja"},
+				{Id: 3, Uuid: newUuid(), Name: "Transport"},
+			} {
+				if err := s.Db.Create(&category).Error; err != nil {
+					t.Fatalf("create category: %v", err)
+				}
+			}
+			// Category 2 may roll over, category 3 never does
+			for _, envelope := range []models.EnvelopeCategory{
+				{UserId: 1, CategoryId: 2, Rollover: test.rollover, CreatedAt: noon(time.September, 1)},
+				{UserId: 1, CategoryId: 3, Rollover: false, CreatedAt: noon(time.September, 1)},
+			} {
+				if err := s.Db.Create(&envelope).Error; err != nil {
+					t.Fatalf("create envelope: %v", err)
+				}
+			}
+			// Cycle starts are stored as plain dates, the way Postgres keeps
+			// a date column, so SQLite compares them like the app does
+			for _, assignment := range []models.EnvelopeAssignment{
+				{CategoryId: 2, CycleStart: firstCycle, Amount: 300000},
+				{CategoryId: 3, CycleStart: firstCycle, Amount: 200000},
+				{CategoryId: 2, CycleStart: secondCycle, Amount: 50000},
+				{CategoryId: 3, CycleStart: secondCycle, Amount: 100000},
+			} {
+				err := s.Db.Exec("INSERT INTO envelope_assignments (user_id, category_id, cycle_start, amount, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+					1, assignment.CategoryId, assignment.CycleStart.Format("2006-01-02"), assignment.Amount, time.Now(), time.Now()).Error
+				if err != nil {
+					t.Fatalf("create assignment: %v", err)
+				}
+			}
+			createTestTransaction(t, s, models.Transaction{UserId: 1, Type: "income", Amount: 1000000, OccurredAt: noon(time.August, 28)})
+			createTestTransaction(t, s, models.Transaction{UserId: 1, CategoryId: 2, Amount: 100000, OccurredAt: noon(time.September, 5)})
+			// Category 3 is overspent by 50000 in the first cycle
+			createTestTransaction(t, s, models.Transaction{UserId: 1, CategoryId: 3, Amount: 250000, OccurredAt: noon(time.September, 10)})
+			createTestTransaction(t, s, models.Transaction{UserId: 1, CategoryId: 3, Amount: 20000, OccurredAt: noon(time.October, 1)})
+
+			response, err := s.GetEnvelopes(models.RequestGetEnvelopes{Date: "2026-10-01"}, 1)
+			if err != nil {
+				t.Fatalf("get envelopes: %v", err)
+			}
+			if response.CycleStart != "2026-09-27" || response.CycleEnd != "2026-10-26" {
+				t.Fatalf("expected cycle 2026-09-27..2026-10-26, got %s..%s", response.CycleStart, response.CycleEnd)
+			}
+			if len(response.Envelopes) != 2 {
+				t.Fatalf("expected 2 envelopes, got %d", len(response.Envelopes))
+			}
+
+			envelopes := map[int]models.Envelope{}
+			for _, envelope := range response.Envelopes {
+				envelopes[envelope.CategoryId] = envelope
+			}
+			if got := envelopes[2]; got.CarriedIn != test.wantCarriedIn || got.Assigned != 50000 || got.Available != test.wantAvailable {
+				t.Errorf("expected category 2 carried in %v and available %v, got %+v", test.wantCarriedIn, test.wantAvailable, got)
+			}
+			// A negative carry-over is kept even without rollover
+			if got := envelopes[3]; got.CarriedIn != -50000 || got.Activity != -20000 || got.Available != 30000 {
+				t.Errorf("expected category 3 carried in -50000 and available 30000, got %+v", got)
+			}
+			if response.Overspent != 0 {
+				t.Errorf("expected nothing overspent, got %v", response.Overspent)
+			}
+			if response.AvailableToAssign != test.wantToAssign {
+				t.Errorf("expected %v available to assign, got %v", test.wantToAssign, response.AvailableToAssign)
+			}
+		})
+	}
+}
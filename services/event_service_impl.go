@@ -0,0 +1,248 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Domain event types written to the outbox. Subscribers such as the
+// webhooks and the notifications pick them up from there.
+const (
+	EventTransactionCreated = "transaction.created"
+	EventTransactionUpdated = "transaction.updated"
+	EventTransactionDeleted = "transaction.deleted"
+	EventCategoryCreated    = "category.created"
+	EventCategoryUpdated    = "category.updated"
+	EventCategoryDeleted    = "category.deleted"
+	EventTagCreated         = "tag.created"
+	EventTagUpdated         = "tag.updated"
+	EventTagDeleted         = "tag.deleted"
+	EventUserCreated        = "user.created"
+	EventUserUpdated        = "user.updated"
+	EventUserDeleted        = "user.deleted"
+	EventUserRoleChanged    = "user.role_changed"
+)
+
+var domainEventTypes = []string{
+	EventTransactionCreated, EventTransactionUpdated, EventTransactionDeleted,
+	EventCategoryCreated, EventCategoryUpdated, EventCategoryDeleted,
+	EventTagCreated, EventTagUpdated, EventTagDeleted,
+	EventUserCreated, EventUserUpdated, EventUserDeleted, EventUserRoleChanged,
+}
+
+var errEventDispatchRunning = errors.New("events are being dispatched right now: try again")
+
+// deletedEntity is the payload of the *.deleted events of transactions,
+// categories and tags.
+type deletedEntity struct {
+	Id     int    `json:"id"`
+	Uuid   string `json:"uuid"`
+	UserId int    `json:"user_id,omitempty"`
+}
+
+// UserRoleChange is the payload of user.role_changed.
+type UserRoleChange struct {
+	Id      int    `json:"id"`
+	OldRole string `json:"old_role"`
+	NewRole string `json:"new_role"`
+}
+
+func (s *service) GetDomainEvents(req models.RequestGetDomainEvents) (response models.ResponseDomainEventList, err error) {
+	if req.Type != "" && !slices.Contains(domainEventTypes, req.Type) {
+		err = errors.New("invalid type: unknown event type")
+		return
+	}
+
+	pagination, err := helper.ParsePagination(req.RequestPagination, domainEventSortColumns, "created_at:desc")
+	if err != nil {
+		return
+	}
+
+	count, events, err := s.Repository.GetDomainEvents(s.Db, req.Type, pagination)
+	if err != nil {
+		return
+	}
+	events, hasMore := helper.TrimCursorPage(events, pagination)
+	if events == nil {
+		events = []models.DomainEvent{}
+	}
+
+	response = models.ResponseDomainEventList{
+		Count: count,
+		Page:  pagination.Page,
+		Limit: pagination.Limit,
+		Data:  events,
+	}
+
+	if pagination.UseCursor && len(events) > 0 {
+		first, last := events[0], events[len(events)-1]
+		column := pagination.Sort.Column
+		response.NextCursor, response.PrevCursor = helper.CursorLinks(pagination, hasMore,
+			domainEventSortValue(first, column), first.Id, domainEventSortValue(last, column), last.Id)
+	}
+	return
+}
+
+func (s *service) GetEventSubscribers() (subscribers []models.EventSubscriber, err error) {
+	offsets, err := s.Repository.GetEventOffsets(s.Db)
+	if err != nil {
+		return
+	}
+	latest, err := s.Repository.GetLatestEventSequence(s.Db)
+	if err != nil {
+		return
+	}
+
+	subscribers = []models.EventSubscriber{}
+	for _, offset := range offsets {
+		subscribers = append(subscribers, toEventSubscriber(offset, latest))
+	}
+	return
+}
+
+// ReplayEvents rewinds the subscriber so the events from FromSequence on
+// are handed to it again by the dispatcher. It takes the dispatch lock so a
+// running dispatch cannot move the offset forward again.
+func (s *service) ReplayEvents(req models.RequestReplayEvents) (subscriber models.EventSubscriber, err error) {
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		locked, errTx := s.Repository.TryLockEventDispatch(tx)
+		if errTx != nil {
+			return
+		}
+		if !locked {
+			return errEventDispatchRunning
+		}
+
+		_, errTx = s.Repository.GetEventOffset(tx, req.Subscriber)
+		if errTx != nil {
+			return
+		}
+
+		latest, errTx := s.Repository.GetLatestEventSequence(tx)
+		if errTx != nil {
+			return
+		}
+		if req.FromSequence < 1 || req.FromSequence > latest+1 {
+			return errors.New("from_sequence must be between 1 and the latest sequence plus one")
+		}
+
+		errTx = s.Repository.SaveEventOffset(tx, req.Subscriber, req.FromSequence-1)
+		if errTx != nil {
+			return
+		}
+
+		offset, errTx := s.Repository.GetEventOffset(tx, req.Subscriber)
+		if errTx != nil {
+			return
+		}
+		subscriber = toEventSubscriber(offset, latest)
+		return
+	})
+	return
+}
+
+func toEventSubscriber(offset models.EventOffset, latest int64) models.EventSubscriber {
+	return models.EventSubscriber{
+		Subscriber: offset.Subscriber,
+		Sequence:   offset.Sequence,
+		Latest:     latest,
+		Lag:        max(latest-offset.Sequence, 0),
+		UpdatedAt:  offset.UpdatedAt,
+	}
+}
+
+// recordEvent writes a domain event to the outbox through db, which must be
+// the DB transaction of the change so the event commits or rolls back with
+// it.
+func (s *service) recordEvent(db *gorm.DB, eventType string, userId int, aggregateId int, data interface{}) (err error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	err = s.Repository.CreateDomainEvent(db, models.DomainEvent{
+		Type:        eventType,
+		UserId:      userId,
+		AggregateId: aggregateId,
+		Payload:     payload,
+	})
+	return
+}
+
+func (s *service) recordTransactionEvent(db *gorm.DB, eventType string, transaction models.Transaction) (err error) {
+	err = s.recordEvent(db, eventType, transaction.UserId, transaction.Id, toTransactionResponse(transaction))
+	return
+}
+
+// recordTransactionUpdated reloads the transaction through db and records
+// it as updated.
+func (s *service) recordTransactionUpdated(db *gorm.DB, id int) (transaction models.Transaction, err error) {
+	transaction, err = s.Repository.GetTransactionById(db, id)
+	if err != nil {
+		return
+	}
+
+	err = s.recordTransactionEvent(db, EventTransactionUpdated, transaction)
+	return
+}
+
+// Categories are shared, so their events have no user.
+func (s *service) recordCategoryEvent(db *gorm.DB, eventType string, category models.Category) (err error) {
+	err = s.recordEvent(db, eventType, 0, category.Id, category)
+	return
+}
+
+func (s *service) recordCategoryUpdated(db *gorm.DB, id int) (category models.Category, err error) {
+	category, err = s.Repository.GetCategoryById(db, id)
+	if err != nil {
+		return
+	}
+
+	err = s.recordCategoryEvent(db, EventCategoryUpdated, category)
+	return
+}
+
+// recordTagEvent records the tag as payload.
+func (s *service) recordTagEvent(db *gorm.DB, eventType string, tag models.Tag) (err error) {
+	err = s.recordEvent(db, eventType, tag.UserId, tag.Id, tag)
+	return
+}
+
+// recordUserEvent sends the user without the password hash.
+func (s *service) recordUserEvent(db *gorm.DB, eventType string, user models.User) (err error) {
+	err = s.recordEvent(db, eventType, user.Id, user.Id, models.WebhookUser{
+		Id:        user.Id,
+		Name:      user.Name,
+		Username:  user.Username,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+	return
+}
+
+// recordUserUpdated reloads the user through db and records it as updated,
+// followed by user.role_changed when the role differs from before.
+func (s *service) recordUserUpdated(db *gorm.DB, before models.User) (user models.User, err error) {
+	user, err = s.Repository.FindUserById(db, before.Id)
+	if err != nil {
+		return
+	}
+
+	err = s.recordUserEvent(db, EventUserUpdated, user)
+	if err != nil || user.Role == before.Role {
+		return
+	}
+
+	err = s.recordEvent(db, EventUserRoleChanged, user.Id, user.Id, UserRoleChange{
+		Id:      user.Id,
+		OldRole: before.Role,
+		NewRole: user.Role,
+	})
+	return
+}
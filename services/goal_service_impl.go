@@ -0,0 +1,335 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-crud-api/models"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxGoalNameLength = 100
+
+	// averageDaysPerMonth turns day spans into fractional months
+	averageDaysPerMonth = 30.44
+	// goalRateMonths is how far back the contribution rate looks
+	goalRateMonths = 3
+)
+
+func (s *service) CreateGoal(userId int, req models.RequestCreateGoal) (goal models.Goal, err error) {
+	goal, err = s.buildGoal(userId, req)
+	if err != nil {
+		return
+	}
+
+	goal, err = s.Repository.CreateGoal(s.Db, goal)
+	return
+}
+
+func (s *service) GetGoals(userId int) (goals []models.Goal, err error) {
+	goals, err = s.Repository.GetGoals(s.Db, userId)
+	if goals == nil {
+		goals = []models.Goal{}
+	}
+	return
+}
+
+func (s *service) GetGoalById(req models.RequestGetGoalById, userId int) (goal models.Goal, err error) {
+	goal, err = s.getOwnedGoal(req.Id, userId)
+	return
+}
+
+func (s *service) UpdateGoal(id int, userId int, req models.RequestUpdateGoal) (goal models.Goal, err error) {
+	existingGoal, err := s.getOwnedGoal(id, userId)
+	if err != nil {
+		return
+	}
+
+	goal, err = s.buildGoal(userId, models.RequestCreateGoal(req))
+	if err != nil {
+		return
+	}
+	goal.Id = existingGoal.Id
+	goal.CreatedAt = existingGoal.CreatedAt
+
+	err = s.Repository.UpdateGoal(s.Db, goal)
+	if err != nil {
+		return
+	}
+
+	goal, err = s.Repository.GetGoalById(s.Db, id)
+	return
+}
+
+func (s *service) DeleteGoal(id int, userId int) (err error) {
+	_, err = s.getOwnedGoal(id, userId)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		return s.Repository.DeleteGoal(tx, id)
+	})
+	return
+}
+
+func (s *service) GetGoalProgress(req models.RequestGetGoalById, userId int) (response models.ResponseGoalProgress, err error) {
+	goal, err := s.getOwnedGoal(req.Id, userId)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	response.Goal = goal
+
+	response.ManualSaved, err = s.Repository.SumGoalContributions(s.Db, goal.Id, nil)
+	if err != nil {
+		return
+	}
+	response.LinkedSaved, err = s.linkedGoalSaved(goal, goal.StartDate)
+	if err != nil {
+		return
+	}
+
+	saved := response.ManualSaved + response.LinkedSaved
+	remaining := math.Max(goal.TargetAmount-saved, 0)
+	response.Saved = roundMoney(saved)
+	response.ManualSaved = roundMoney(response.ManualSaved)
+	response.LinkedSaved = roundMoney(response.LinkedSaved)
+	response.Remaining = roundMoney(remaining)
+	response.Percent = roundMoney(saved / goal.TargetAmount * 100)
+
+	// Contribution rate over the last months, or since the start when the
+	// goal is younger; at least a month so a first deposit isn't inflated
+	since := now.AddDate(0, -goalRateMonths, 0)
+	if since.Before(goal.StartDate) {
+		since = goal.StartDate
+	}
+	recentManual, err := s.Repository.SumGoalContributions(s.Db, goal.Id, &since)
+	if err != nil {
+		return
+	}
+	recentLinked, err := s.linkedGoalSaved(goal, since)
+	if err != nil {
+		return
+	}
+	months := math.Max(now.Sub(since).Hours()/24/averageDaysPerMonth, 1)
+	rate := (recentManual + recentLinked) / months
+	response.RecentMonthlyRate = roundMoney(rate)
+
+	var projected *time.Time
+	if remaining == 0 {
+		projected = &now
+	} else if rate > 0 {
+		days := remaining / rate * averageDaysPerMonth
+		date := now.AddDate(0, 0, int(math.Ceil(days)))
+		projected = &date
+	}
+	if projected != nil {
+		date := projected.Format("2006-01-02")
+		response.ProjectedCompletionDate = &date
+	}
+
+	if goal.TargetDate != nil {
+		// Whatever is left is due at once when the target date has passed
+		monthsLeft := math.Max(goal.TargetDate.Sub(now).Hours()/24/averageDaysPerMonth, 1)
+		monthlyNeeded := roundMoney(remaining / monthsLeft)
+		response.MonthlyNeeded = &monthlyNeeded
+
+		onTrack := remaining == 0 || (projected != nil && !projected.After(goal.TargetDate.AddDate(0, 0, 1)))
+		response.OnTrack = &onTrack
+	}
+	return
+}
+
+func (s *service) CreateGoalContribution(goalId int, userId int, req models.RequestCreateGoalContribution) (contribution models.GoalContribution, err error) {
+	_, err = s.getOwnedGoal(goalId, userId)
+	if err != nil {
+		return
+	}
+
+	if req.Amount == 0 {
+		err = errors.New("amount cannot be 0")
+		return
+	}
+
+	err = validateNote(req.Note)
+	if err != nil {
+		return
+	}
+
+	contributedAt, err := parseOccurredAt(req.Date)
+	if err != nil {
+		err = errors.New("invalid date: use YYYY-MM-DD or RFC 3339 datetime")
+		return
+	}
+
+	contribution, err = s.Repository.CreateGoalContribution(s.Db, models.GoalContribution{
+		GoalId:        goalId,
+		UserId:        userId,
+		Amount:        req.Amount,
+		Note:          req.Note,
+		ContributedAt: contributedAt,
+	})
+	return
+}
+
+// GetGoalContributions lists the manual contributions; the linked
+// transactions can be listed with the transaction filters.
+func (s *service) GetGoalContributions(goalId int, userId int) (contributions []models.GoalContribution, err error) {
+	_, err = s.getOwnedGoal(goalId, userId)
+	if err != nil {
+		return
+	}
+
+	contributions, err = s.Repository.GetGoalContributions(s.Db, goalId)
+	if contributions == nil {
+		contributions = []models.GoalContribution{}
+	}
+	return
+}
+
+func (s *service) DeleteGoalContribution(req models.RequestGetGoalContribution, userId int) (err error) {
+	_, err = s.getOwnedGoal(req.GoalId, userId)
+	if err != nil {
+		return
+	}
+
+	contribution, err := s.Repository.GetGoalContributionById(s.Db, req.ContributionId)
+	if err != nil {
+		return
+	}
+	if contribution.GoalId != req.GoalId {
+		err = gorm.ErrRecordNotFound
+		return
+	}
+
+	err = s.Repository.DeleteGoalContribution(s.Db, contribution.Id)
+	return
+}
+
+// linkedGoalSaved sums the goal's linked transactions from since on. Money
+// spent into the linked category or tag (expense) is saved and income from
+// it is a withdrawal.
+func (s *service) linkedGoalSaved(goal models.Goal, since time.Time) (saved float64, err error) {
+	filter := models.QueryTransactionFilter{UserId: goal.UserId, StartTime: &since}
+
+	switch {
+	case goal.CategoryId != nil:
+		filter.CategoryIds = []int{*goal.CategoryId}
+	case goal.TagId != nil:
+		tag, errTag := s.Repository.GetTagById(s.Db, *goal.TagId)
+		if errTag == gorm.ErrRecordNotFound {
+			// The tag was deleted, so nothing is linked any more
+			return
+		}
+		if errTag != nil {
+			err = errTag
+			return
+		}
+		filter.Tags = []string{tag.Name}
+	default:
+		return
+	}
+
+	total, _, err := s.Repository.SumTransactions(s.Db, filter)
+	saved = -total
+	return
+}
+
+func (s *service) getOwnedGoal(id int, userId int) (goal models.Goal, err error) {
+	goal, err = s.Repository.GetGoalById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	if goal.UserId != userId {
+		err = errors.New("unauthorized: goal does not belong to this user")
+		return
+	}
+	return
+}
+
+// buildGoal validates the request and turns it into a goal of the user.
+func (s *service) buildGoal(userId int, req models.RequestCreateGoal) (goal models.Goal, err error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		err = errors.New("goal name is required and cannot be empty")
+		return
+	}
+	if len(name) > maxGoalNameLength {
+		err = fmt.Errorf("goal name cannot be longer than %d characters", maxGoalNameLength)
+		return
+	}
+
+	if req.TargetAmount <= 0 {
+		err = errors.New("target_amount must be greater than 0")
+		return
+	}
+
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if req.StartDate != "" {
+		startDate, err = time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+		if err != nil {
+			err = errors.New("invalid start_date: use YYYY-MM-DD")
+			return
+		}
+	}
+
+	var targetDate *time.Time
+	if req.TargetDate != "" {
+		date, errDate := time.ParseInLocation("2006-01-02", req.TargetDate, time.Local)
+		if errDate != nil {
+			err = errors.New("invalid target_date: use YYYY-MM-DD")
+			return
+		}
+		if !date.After(startDate) {
+			err = errors.New("target_date must be after start_date")
+			return
+		}
+		targetDate = &date
+	}
+
+	if req.CategoryId != nil && req.TagId != nil {
+		err = errors.New("link either category_id or tag_id, not both")
+		return
+	}
+	if req.CategoryId != nil {
+		err = s.validateCategoryId(s.Db, *req.CategoryId)
+		if err != nil {
+			return
+		}
+	}
+	if req.TagId != nil {
+		_, err = s.getOwnedTag(*req.TagId, userId)
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New("tag not found")
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	account := strings.TrimSpace(req.Account)
+	if len(account) > maxAccountLength {
+		err = fmt.Errorf("account cannot be longer than %d characters", maxAccountLength)
+		return
+	}
+
+	goal = models.Goal{
+		UserId:       userId,
+		Name:         name,
+		TargetAmount: req.TargetAmount,
+		TargetDate:   targetDate,
+		StartDate:    startDate,
+		CategoryId:   req.CategoryId,
+		TagId:        req.TagId,
+		Account:      account,
+	}
+	return
+}
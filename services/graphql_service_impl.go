@@ -0,0 +1,34 @@
+package services
+
+import (
+	"go-crud-api/models"
+)
+
+// GetUsersByIds loads the users of a GraphQL batch in one query. Users
+// that do not exist are left out.
+func (s *service) GetUsersByIds(ids []int) (users []models.UserResponse, err error) {
+	found, err := s.Repository.FindUsersByIds(s.Db, ids)
+	if err != nil {
+		return
+	}
+
+	users = []models.UserResponse{}
+	for _, user := range found {
+		users = append(users, models.UserResponse{
+			Id:       user.Id,
+			Uuid:     user.Uuid,
+			Name:     user.Name,
+			Username: user.Username,
+			Role:     user.Role,
+		})
+	}
+	return
+}
+
+func (s *service) GetCategoriesByIds(ids []int) (categories []models.Category, err error) {
+	categories, err = s.Repository.GetCategoriesByIds(s.Db, ids)
+	if categories == nil {
+		categories = []models.Category{}
+	}
+	return
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go-crud-api/importer"
+	"go-crud-api/models"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxImportSize = 5 << 20 // 5 MB
+	maxImportRows = 5000
+)
+
+var errImportDryRun = errors.New("import dry run")
+
+// ImportTransactions parses a bank statement and inserts its rows through
+// createTransaction, so they get the same validation and rules as
+// CreateTransaction. Rows whose bank reference was imported before are
+// skipped, and rows that look like an existing transaction are flagged
+// instead of inserted unless AllowDuplicates is set. A dry run reports the
+// outcome without saving anything.
+func (s *service) ImportTransactions(userId int, req models.RequestImportTransactions) (response models.ResponseImportTransactions, err error) {
+	format := req.Format
+	if format == "" {
+		format, err = importer.DetectFormat(req.FileName)
+		if err != nil {
+			return
+		}
+	}
+	parser, err := importer.ForFormat(format)
+	if err != nil {
+		return
+	}
+
+	err = s.validateCategoryId(s.Db, req.CategoryId)
+	if err != nil {
+		return
+	}
+
+	window, err := parseDuplicateWindow(req.DuplicateWindow)
+	if err != nil {
+		return
+	}
+
+	content, err := io.ReadAll(io.LimitReader(req.Content, maxImportSize+1))
+	if err != nil {
+		return
+	}
+	if len(content) > maxImportSize {
+		err = fmt.Errorf("file is too large: maximum size is %d MB", maxImportSize>>20)
+		return
+	}
+
+	rows, err := parser.Parse(bytes.NewReader(content))
+	if err != nil {
+		return
+	}
+	if len(rows) == 0 {
+		err = errors.New("the file contains no transactions")
+		return
+	}
+	if len(rows) > maxImportRows {
+		err = fmt.Errorf("too many transactions: maximum is %d per import", maxImportRows)
+		return
+	}
+
+	response = models.ResponseImportTransactions{
+		Format:  format,
+		DryRun:  req.DryRun,
+		Total:   len(rows),
+		Results: []models.ImportRowResult{},
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		seenReferences := map[string]bool{}
+		importedIds := map[int]bool{}
+
+		for i, row := range rows {
+			transactionType := "income"
+			if row.Sign < 0 {
+				transactionType = "expense"
+			}
+
+			result := models.ImportRowResult{
+				Row:           i + 1,
+				Date:          row.Date.Format("2006-01-02"),
+				Amount:        row.Amount,
+				Type:          transactionType,
+				Payee:         row.Payee,
+				Memo:          row.Memo,
+				BankReference: row.ReferenceId,
+			}
+
+			// Overlapping statements repeat rows that were already imported
+			_, errFind := s.Repository.FindTransactionByBankReference(tx, userId, row.ReferenceId)
+			if errFind != nil && errFind != gorm.ErrRecordNotFound {
+				return errFind
+			}
+			if errFind == nil || seenReferences[row.ReferenceId] {
+				result.Status = "already_imported"
+				response.Results = append(response.Results, result)
+				continue
+			}
+			seenReferences[row.ReferenceId] = true
+
+			// Rules may replace the import's category, so it is only the default
+			createRequest := models.RequestCreateTransaction{
+				Amount:            row.Amount,
+				Type:              transactionType,
+				DefaultCategoryId: req.CategoryId,
+				Note:              truncateText(row.Memo, maxNoteLength),
+				Payee:             truncateText(row.Payee, maxPayeeLength),
+				OccurredAt:        row.Date.Format(time.RFC3339),
+				BankReference:     row.ReferenceId,
+			}
+
+			if !req.AllowDuplicates {
+				duplicateIds, errDuplicate := s.findImportDuplicates(tx, userId, createRequest, row.Date, window, importedIds)
+				if errDuplicate != nil {
+					return errDuplicate
+				}
+				if len(duplicateIds) > 0 {
+					result.Status = "possible_duplicate"
+					result.DuplicateOf = duplicateIds
+					response.Results = append(response.Results, result)
+					continue
+				}
+			}
+
+			if errSave := tx.SavePoint("import_row").Error; errSave != nil {
+				return errSave
+			}
+			transaction, errRow := s.createTransaction(tx, userId, createRequest)
+			if errRow != nil {
+				if errRollback := tx.RollbackTo("import_row").Error; errRollback != nil {
+					return errRollback
+				}
+				result.Status = "error"
+				result.Error = errRow.Error()
+				response.Results = append(response.Results, result)
+				continue
+			}
+			importedIds[transaction.Id] = true
+
+			result.Status = "imported"
+			if !req.DryRun {
+				transactionResponse := toTransactionResponse(transaction)
+				result.Transaction = &transactionResponse
+			}
+			response.Results = append(response.Results, result)
+		}
+
+		if req.DryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if err == errImportDryRun {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	for _, result := range response.Results {
+		switch result.Status {
+		case "imported":
+			response.Imported++
+		case "already_imported":
+			response.Skipped++
+		case "possible_duplicate":
+			response.Duplicates++
+		default:
+			response.Failed++
+		}
+	}
+	return
+}
+
+// findImportDuplicates returns the ids of existing transactions the row most
+// likely duplicates. Rules run first so the category matches the one the row
+// would be saved with; rows inserted earlier in the same import don't count.
+func (s *service) findImportDuplicates(tx *gorm.DB, userId int, req models.RequestCreateTransaction, occurredAt time.Time, window time.Duration, importedIds map[int]bool) (duplicateIds []int, err error) {
+	target, err := s.applyUserRules(tx, userId, ruleTarget{
+		Amount:     req.Amount,
+		Type:       req.Type,
+		Note:       req.Note,
+		Payee:      req.Payee,
+		CategoryId: req.CategoryId,
+	})
+	if err != nil {
+		return
+	}
+
+	duplicates, err := s.findLikelyDuplicates(tx, userId, models.Transaction{
+		Amount:     req.Amount,
+		Type:       req.Type,
+		CategoryId: newTransactionCategory(req, target),
+		OccurredAt: occurredAt,
+	}, window)
+	if err != nil {
+		return
+	}
+
+	for _, duplicate := range duplicates {
+		if !importedIds[duplicate.Id] {
+			duplicateIds = append(duplicateIds, duplicate.Id)
+		}
+	}
+	return
+}
+
+// truncateText cuts text to at most max bytes without splitting a rune.
+func truncateText(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	text = text[:max]
+	for !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	return text
+}
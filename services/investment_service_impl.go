@@ -0,0 +1,757 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go-crud-api/importer"
+	"go-crud-api/models"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	CostFIFO    = "fifo"
+	CostAverage = "average"
+
+	ActivityBuy      = "buy"
+	ActivitySell     = "sell"
+	ActivityDividend = "dividend"
+
+	maxPortfolioNameLength = 100
+	maxSymbolLength        = 30
+	maxPriceImportRows     = 10000
+
+	// unitsEpsilon absorbs float noise when comparing unit counts
+	unitsEpsilon = 1e-9
+)
+
+var holdingTypes = []string{"mutual_fund", "stock", "gold", "bond", "crypto", "other"}
+
+// activityLabels name the activity types in generated transaction notes
+var activityLabels = map[string]string{ActivityBuy: "Buy", ActivitySell: "Sell", ActivityDividend: "Dividend"}
+
+func (s *service) CreatePortfolio(userId int, req models.RequestCreatePortfolio) (portfolio models.Portfolio, err error) {
+	portfolio, err = buildPortfolio(userId, req)
+	if err != nil {
+		return
+	}
+
+	portfolio, err = s.Repository.CreatePortfolio(s.Db, portfolio)
+	return
+}
+
+func (s *service) GetPortfolios(userId int) (portfolios []models.Portfolio, err error) {
+	portfolios, err = s.Repository.GetPortfolios(s.Db, userId)
+	if portfolios == nil {
+		portfolios = []models.Portfolio{}
+	}
+	return
+}
+
+func (s *service) GetPortfolioById(req models.RequestGetPortfolioById, userId int) (portfolio models.Portfolio, err error) {
+	portfolio, err = s.getOwnedPortfolio(req.Id, userId)
+	return
+}
+
+func (s *service) UpdatePortfolio(id int, userId int, req models.RequestUpdatePortfolio) (portfolio models.Portfolio, err error) {
+	existingPortfolio, err := s.getOwnedPortfolio(id, userId)
+	if err != nil {
+		return
+	}
+
+	portfolio, err = buildPortfolio(userId, models.RequestCreatePortfolio(req))
+	if err != nil {
+		return
+	}
+	portfolio.Id = existingPortfolio.Id
+	portfolio.CreatedAt = existingPortfolio.CreatedAt
+
+	err = s.Repository.UpdatePortfolio(s.Db, portfolio)
+	if err != nil {
+		return
+	}
+
+	portfolio, err = s.Repository.GetPortfolioById(s.Db, id)
+	return
+}
+
+// DeletePortfolio removes the portfolio, its holdings and activities.
+// Transactions created for the activities stay.
+func (s *service) DeletePortfolio(id int, userId int) (err error) {
+	_, err = s.getOwnedPortfolio(id, userId)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		return s.Repository.DeletePortfolio(tx, id)
+	})
+	return
+}
+
+// GetPortfolioValuation values every holding on the date (default today)
+// from the activities up to that day and the latest price on or before it.
+// Without a price, the last buy or sell price is used.
+func (s *service) GetPortfolioValuation(req models.RequestGetPortfolioValuation, userId int) (response models.ResponsePortfolioValuation, err error) {
+	portfolio, err := s.getOwnedPortfolio(req.Id, userId)
+	if err != nil {
+		return
+	}
+
+	date := time.Now().Format("2006-01-02")
+	if req.Date != "" {
+		if _, errDate := time.Parse("2006-01-02", req.Date); errDate != nil {
+			err = errors.New("invalid date: use YYYY-MM-DD")
+			return
+		}
+		date = req.Date
+	}
+
+	method := portfolio.CostMethod
+	if req.Method != "" {
+		method = req.Method
+	}
+	if method != CostFIFO && method != CostAverage {
+		err = errors.New("invalid method: use fifo or average")
+		return
+	}
+
+	holdings, err := s.Repository.GetHoldings(s.Db, portfolio.Id)
+	if err != nil {
+		return
+	}
+
+	holdingIds := make([]int, len(holdings))
+	symbols := make([]string, len(holdings))
+	for i, holding := range holdings {
+		holdingIds[i] = holding.Id
+		symbols[i] = holding.Symbol
+	}
+
+	activities, err := s.Repository.GetInvestmentActivities(s.Db, holdingIds, date)
+	if err != nil {
+		return
+	}
+	byHolding := make(map[int][]models.InvestmentActivity, len(holdings))
+	for _, activity := range activities {
+		byHolding[activity.HoldingId] = append(byHolding[activity.HoldingId], activity)
+	}
+
+	prices, err := s.Repository.GetLatestInstrumentPrices(s.Db, userId, symbols, date)
+	if err != nil {
+		return
+	}
+	latestPrice := make(map[string]models.InstrumentPrice, len(prices))
+	for _, price := range prices {
+		latestPrice[price.Symbol] = price
+	}
+
+	response.Portfolio = portfolio
+	response.Date = date
+	response.CostMethod = method
+	response.Holdings = make([]models.HoldingValuation, 0, len(holdings))
+	for _, holding := range holdings {
+		position, errPosition := calculatePosition(byHolding[holding.Id], method)
+		if errPosition != nil {
+			err = errPosition
+			return
+		}
+
+		valuation := models.HoldingValuation{
+			Holding:      holding,
+			Units:        roundUnits(position.units),
+			CostBasis:    roundMoney(position.costBasis),
+			RealizedGain: roundMoney(position.realizedGain),
+			Dividends:    roundMoney(position.dividends),
+			PriceSource:  "none",
+		}
+		if position.units > unitsEpsilon {
+			valuation.AverageCost = roundMoney(position.costBasis / position.units)
+		}
+
+		if price, ok := latestPrice[holding.Symbol]; ok {
+			value, priceDate := price.Price, price.Date.Format("2006-01-02")
+			valuation.Price, valuation.PriceDate, valuation.PriceSource = &value, &priceDate, "price_history"
+		} else if position.lastTrade != nil {
+			value, priceDate := position.lastTrade.Price, position.lastTrade.Date.Format("2006-01-02")
+			valuation.Price, valuation.PriceDate, valuation.PriceSource = &value, &priceDate, "last_trade"
+		}
+
+		if valuation.Price != nil {
+			valuation.MarketValue = roundMoney(position.units * *valuation.Price)
+			valuation.UnrealizedGain = roundMoney(valuation.MarketValue - valuation.CostBasis)
+			if valuation.CostBasis > 0 {
+				valuation.UnrealizedGainPercent = roundMoney(valuation.UnrealizedGain / valuation.CostBasis * 100)
+			}
+		}
+
+		response.CostBasis += valuation.CostBasis
+		response.MarketValue += valuation.MarketValue
+		response.UnrealizedGain += valuation.UnrealizedGain
+		response.RealizedGain += valuation.RealizedGain
+		response.Dividends += valuation.Dividends
+		response.Holdings = append(response.Holdings, valuation)
+	}
+
+	response.CostBasis = roundMoney(response.CostBasis)
+	response.MarketValue = roundMoney(response.MarketValue)
+	response.UnrealizedGain = roundMoney(response.UnrealizedGain)
+	response.RealizedGain = roundMoney(response.RealizedGain)
+	response.Dividends = roundMoney(response.Dividends)
+	if response.CostBasis > 0 {
+		response.UnrealizedGainPercent = roundMoney(response.UnrealizedGain / response.CostBasis * 100)
+	}
+	return
+}
+
+func (s *service) CreateHolding(portfolioId int, userId int, req models.RequestCreateHolding) (holding models.Holding, err error) {
+	_, err = s.getOwnedPortfolio(portfolioId, userId)
+	if err != nil {
+		return
+	}
+
+	symbol, err := normalizeSymbol(req.Symbol)
+	if err != nil {
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = symbol
+	}
+	if len(name) > maxPortfolioNameLength {
+		err = fmt.Errorf("holding name cannot be longer than %d characters", maxPortfolioNameLength)
+		return
+	}
+
+	holdingType := req.Type
+	if holdingType == "" {
+		holdingType = "other"
+	}
+	if !slices.Contains(holdingTypes, holdingType) {
+		err = fmt.Errorf("type must be one of %s", strings.Join(holdingTypes, ", "))
+		return
+	}
+
+	_, err = s.Repository.FindHoldingBySymbol(s.Db, portfolioId, symbol)
+	if err == nil {
+		err = fmt.Errorf("holding %s already exists in this portfolio", symbol)
+		return
+	}
+	if err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	holding, err = s.Repository.CreateHolding(s.Db, models.Holding{
+		PortfolioId: portfolioId,
+		UserId:      userId,
+		Symbol:      symbol,
+		Name:        name,
+		Type:        holdingType,
+	})
+	return
+}
+
+func (s *service) GetHoldings(portfolioId int, userId int) (holdings []models.Holding, err error) {
+	_, err = s.getOwnedPortfolio(portfolioId, userId)
+	if err != nil {
+		return
+	}
+
+	holdings, err = s.Repository.GetHoldings(s.Db, portfolioId)
+	if holdings == nil {
+		holdings = []models.Holding{}
+	}
+	return
+}
+
+// DeleteHolding removes the holding and its activities. Transactions
+// created for the activities stay.
+func (s *service) DeleteHolding(req models.RequestGetHolding, userId int) (err error) {
+	holding, err := s.getOwnedHolding(req, userId)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		return s.Repository.DeleteHolding(tx, holding.Id)
+	})
+	return
+}
+
+// CreateInvestmentActivity records a buy, sell or dividend. A sell may not
+// exceed the units held on its date, and no later sell may end up
+// exceeding them either. With CreateTransaction the cash movement is also
+// saved through createTransaction, so the user's rules apply to it.
+func (s *service) CreateInvestmentActivity(req models.RequestGetHolding, userId int, activityReq models.RequestCreateInvestmentActivity) (activity models.InvestmentActivity, err error) {
+	holding, err := s.getOwnedHolding(req, userId)
+	if err != nil {
+		return
+	}
+
+	activity, err = buildInvestmentActivity(holding, activityReq)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		existing, errList := s.Repository.GetInvestmentActivities(tx, []int{holding.Id}, "")
+		if errList != nil {
+			return errList
+		}
+		if errCheck := checkActivitySequence(append(existing, activity)); errCheck != nil {
+			return errCheck
+		}
+
+		if activityReq.CreateTransaction {
+			transactionType := "income"
+			if activity.Type == ActivityBuy {
+				transactionType = "expense"
+			}
+			note := fmt.Sprintf("%s %s", activityLabels[activity.Type], holding.Symbol)
+			if activity.Type != ActivityDividend {
+				note = fmt.Sprintf("%s %s %s @ %s", activityLabels[activity.Type], strconv.FormatFloat(activity.Units, 'f', -1, 64), holding.Symbol, strconv.FormatFloat(activity.Price, 'f', -1, 64))
+			}
+
+			transaction, errCreate := s.createTransaction(tx, userId, models.RequestCreateTransaction{
+				Amount:     activity.Amount,
+				Type:       transactionType,
+				CategoryId: activityReq.CategoryId,
+				Note:       note,
+				Payee:      holding.Name,
+				OccurredAt: activity.Date.Format("2006-01-02"),
+			})
+			if errCreate != nil {
+				return errCreate
+			}
+			activity.TransactionId = &transaction.Id
+		}
+
+		created, errCreate := s.Repository.CreateInvestmentActivity(tx, activity)
+		if errCreate != nil {
+			return errCreate
+		}
+		activity = created
+		return nil
+	})
+	return
+}
+
+func (s *service) GetInvestmentActivities(req models.RequestGetHolding, userId int) (activities []models.InvestmentActivity, err error) {
+	holding, err := s.getOwnedHolding(req, userId)
+	if err != nil {
+		return
+	}
+
+	activities, err = s.Repository.GetInvestmentActivities(s.Db, []int{holding.Id}, "")
+	if activities == nil {
+		activities = []models.InvestmentActivity{}
+	}
+	return
+}
+
+// DeleteInvestmentActivity removes the activity unless a later sell would
+// then exceed the units held. A transaction created for it stays.
+func (s *service) DeleteInvestmentActivity(req models.RequestGetInvestmentActivity, userId int) (err error) {
+	holding, err := s.getOwnedHolding(models.RequestGetHolding{PortfolioId: req.PortfolioId, HoldingId: req.HoldingId}, userId)
+	if err != nil {
+		return
+	}
+
+	activity, err := s.Repository.GetInvestmentActivityById(s.Db, req.ActivityId)
+	if err != nil {
+		return
+	}
+	if activity.HoldingId != holding.Id {
+		err = gorm.ErrRecordNotFound
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		existing, errList := s.Repository.GetInvestmentActivities(tx, []int{holding.Id}, "")
+		if errList != nil {
+			return errList
+		}
+		remaining := make([]models.InvestmentActivity, 0, len(existing))
+		for _, other := range existing {
+			if other.Id != activity.Id {
+				remaining = append(remaining, other)
+			}
+		}
+		if errCheck := checkActivitySequence(remaining); errCheck != nil {
+			return fmt.Errorf("cannot delete the activity: %w", errCheck)
+		}
+
+		return s.Repository.DeleteInvestmentActivity(tx, activity.Id)
+	})
+	return
+}
+
+func (s *service) CreatePrice(userId int, req models.RequestCreatePrice) (price models.InstrumentPrice, err error) {
+	price, err = buildInstrumentPrice(userId, req.Symbol, req.Date, req.Price)
+	if err != nil {
+		return
+	}
+
+	price, err = s.Repository.SaveInstrumentPrice(s.Db, price)
+	return
+}
+
+func (s *service) GetPrices(userId int, req models.RequestGetPrices) (prices []models.InstrumentPrice, err error) {
+	symbol := ""
+	if req.Symbol != "" {
+		symbol, err = normalizeSymbol(req.Symbol)
+		if err != nil {
+			return
+		}
+	}
+	if req.StartDate != "" {
+		if _, errDate := time.Parse("2006-01-02", req.StartDate); errDate != nil {
+			err = errors.New("invalid start_date: use YYYY-MM-DD")
+			return
+		}
+	}
+	if req.EndDate != "" {
+		if _, errDate := time.Parse("2006-01-02", req.EndDate); errDate != nil {
+			err = errors.New("invalid end_date: use YYYY-MM-DD")
+			return
+		}
+	}
+
+	prices, err = s.Repository.GetInstrumentPrices(s.Db, userId, symbol, req.StartDate, req.EndDate)
+	if prices == nil {
+		prices = []models.InstrumentPrice{}
+	}
+	return
+}
+
+func (s *service) DeletePrice(req models.RequestGetPriceById, userId int) (err error) {
+	price, err := s.Repository.GetInstrumentPriceById(s.Db, req.Id)
+	if err != nil {
+		return
+	}
+
+	if price.UserId != userId {
+		err = errors.New("unauthorized: price does not belong to this user")
+		return
+	}
+
+	err = s.Repository.DeleteInstrumentPrice(s.Db, price.Id)
+	return
+}
+
+// ImportPrices loads a price history CSV. Every row is validated before
+// anything is saved; a row for a day that already has a price replaces it.
+func (s *service) ImportPrices(userId int, req models.RequestImportPrices) (response models.ResponseImportPrices, err error) {
+	content, err := io.ReadAll(io.LimitReader(req.Content, maxImportSize+1))
+	if err != nil {
+		return
+	}
+	if len(content) > maxImportSize {
+		err = fmt.Errorf("file is too large: maximum size is %d MB", maxImportSize>>20)
+		return
+	}
+
+	rows, err := importer.ParsePrices(bytes.NewReader(content), req.Symbol)
+	if err != nil {
+		return
+	}
+	if len(rows) == 0 {
+		err = errors.New("the file contains no prices")
+		return
+	}
+	if len(rows) > maxPriceImportRows {
+		err = fmt.Errorf("too many prices: maximum is %d per import", maxPriceImportRows)
+		return
+	}
+
+	prices := make([]models.InstrumentPrice, len(rows))
+	symbols := map[string]bool{}
+	for i, row := range rows {
+		prices[i], err = buildInstrumentPrice(userId, row.Symbol, row.Date.Format("2006-01-02"), row.Price)
+		if err != nil {
+			err = fmt.Errorf("line %d: %w", i+2, err)
+			return
+		}
+		symbols[prices[i].Symbol] = true
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		for _, price := range prices {
+			if _, errSave := s.Repository.SaveInstrumentPrice(tx, price); errSave != nil {
+				return errSave
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	response.Saved = len(prices)
+	response.Symbols = make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		response.Symbols = append(response.Symbols, symbol)
+	}
+	sort.Strings(response.Symbols)
+	return
+}
+
+// position is a holding after replaying its activities.
+type position struct {
+	units        float64
+	costBasis    float64
+	realizedGain float64
+	dividends    float64
+	lastTrade    *models.InvestmentActivity
+}
+
+// lot is the part of a buy not sold yet, for FIFO matching.
+type lot struct {
+	units       float64
+	costPerUnit float64
+}
+
+// calculatePosition replays the activities in order. FIFO sells the oldest
+// lots first; average cost sells at the running average. Fees are part of
+// the cost of a buy and reduce the proceeds of a sell.
+func calculatePosition(activities []models.InvestmentActivity, method string) (result position, err error) {
+	var lots []lot
+	for i := range activities {
+		activity := activities[i]
+		switch activity.Type {
+		case ActivityBuy:
+			result.units += activity.Units
+			result.costBasis += activity.Amount
+			lots = append(lots, lot{units: activity.Units, costPerUnit: activity.Amount / activity.Units})
+			result.lastTrade = &activities[i]
+		case ActivitySell:
+			if activity.Units > result.units+unitsEpsilon {
+				err = fmt.Errorf("selling %s units on %s exceeds the %s units held",
+					strconv.FormatFloat(activity.Units, 'f', -1, 64), activity.Date.Format("2006-01-02"), strconv.FormatFloat(roundUnits(result.units), 'f', -1, 64))
+				return
+			}
+
+			var soldCost float64
+			if method == CostAverage {
+				soldCost = result.costBasis / result.units * activity.Units
+			} else {
+				remaining := activity.Units
+				for remaining > unitsEpsilon && len(lots) > 0 {
+					taken := math.Min(remaining, lots[0].units)
+					soldCost += taken * lots[0].costPerUnit
+					lots[0].units -= taken
+					remaining -= taken
+					if lots[0].units <= unitsEpsilon {
+						lots = lots[1:]
+					}
+				}
+			}
+
+			result.units -= activity.Units
+			result.costBasis -= soldCost
+			if result.units <= unitsEpsilon {
+				result.units, result.costBasis, lots = 0, 0, nil
+			}
+			result.realizedGain += activity.Amount - soldCost
+			result.lastTrade = &activities[i]
+		case ActivityDividend:
+			result.dividends += activity.Amount
+		}
+	}
+	return
+}
+
+// checkActivitySequence sorts the activities by date, keeping the given
+// order within a day, and reports a sell that exceeds the units held.
+func checkActivitySequence(activities []models.InvestmentActivity) error {
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].Date.Before(activities[j].Date)
+	})
+	_, err := calculatePosition(activities, CostFIFO)
+	return err
+}
+
+func roundUnits(units float64) float64 {
+	return math.Round(units*1e8) / 1e8
+}
+
+func normalizeSymbol(symbol string) (string, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return "", errors.New("symbol is required")
+	}
+	if len(symbol) > maxSymbolLength {
+		return "", fmt.Errorf("symbol cannot be longer than %d characters", maxSymbolLength)
+	}
+	return symbol, nil
+}
+
+func (s *service) getOwnedPortfolio(id int, userId int) (portfolio models.Portfolio, err error) {
+	portfolio, err = s.Repository.GetPortfolioById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	if portfolio.UserId != userId {
+		err = errors.New("unauthorized: portfolio does not belong to this user")
+		return
+	}
+	return
+}
+
+// getOwnedHolding checks the portfolio is the user's and the holding is in
+// it.
+func (s *service) getOwnedHolding(req models.RequestGetHolding, userId int) (holding models.Holding, err error) {
+	_, err = s.getOwnedPortfolio(req.PortfolioId, userId)
+	if err != nil {
+		return
+	}
+
+	holding, err = s.Repository.GetHoldingById(s.Db, req.HoldingId)
+	if err != nil {
+		return
+	}
+	if holding.PortfolioId != req.PortfolioId {
+		err = gorm.ErrRecordNotFound
+		return
+	}
+	return
+}
+
+// buildPortfolio validates the request and turns it into a portfolio of the
+// user.
+func buildPortfolio(userId int, req models.RequestCreatePortfolio) (portfolio models.Portfolio, err error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		err = errors.New("portfolio name is required and cannot be empty")
+		return
+	}
+	if len(name) > maxPortfolioNameLength {
+		err = fmt.Errorf("portfolio name cannot be longer than %d characters", maxPortfolioNameLength)
+		return
+	}
+
+	method := req.CostMethod
+	if method == "" {
+		method = CostFIFO
+	}
+	if method != CostFIFO && method != CostAverage {
+		err = errors.New("cost_method must be fifo or average")
+		return
+	}
+
+	err = validateNote(req.Note)
+	if err != nil {
+		return
+	}
+
+	portfolio = models.Portfolio{
+		UserId:     userId,
+		Name:       name,
+		CostMethod: method,
+		Note:       req.Note,
+	}
+	return
+}
+
+// buildInvestmentActivity validates the request and computes the cash
+// amount of the activity.
+func buildInvestmentActivity(holding models.Holding, req models.RequestCreateInvestmentActivity) (activity models.InvestmentActivity, err error) {
+	date := startOfDay(time.Now())
+	if req.Date != "" {
+		date, err = time.ParseInLocation("2006-01-02", req.Date, time.Local)
+		if err != nil {
+			err = errors.New("invalid date: use YYYY-MM-DD")
+			return
+		}
+	}
+
+	if req.Fee < 0 {
+		err = errors.New("fee cannot be negative")
+		return
+	}
+
+	err = validateNote(req.Note)
+	if err != nil {
+		return
+	}
+
+	activity = models.InvestmentActivity{
+		HoldingId: holding.Id,
+		UserId:    holding.UserId,
+		Type:      req.Type,
+		Date:      date,
+		Fee:       req.Fee,
+		Note:      req.Note,
+	}
+
+	switch req.Type {
+	case ActivityBuy, ActivitySell:
+		if req.Units <= 0 {
+			err = errors.New("units must be greater than 0")
+			return
+		}
+		if req.Price <= 0 {
+			err = errors.New("price must be greater than 0")
+			return
+		}
+		activity.Units = req.Units
+		activity.Price = req.Price
+		if req.Type == ActivityBuy {
+			activity.Amount = roundMoney(req.Units*req.Price + req.Fee)
+		} else {
+			activity.Amount = roundMoney(req.Units*req.Price - req.Fee)
+			if activity.Amount < 0 {
+				err = errors.New("fee cannot exceed the sale proceeds")
+				return
+			}
+		}
+	case ActivityDividend:
+		if req.Amount <= 0 {
+			err = errors.New("amount must be greater than 0")
+			return
+		}
+		activity.Amount = req.Amount
+	default:
+		err = errors.New("type must be buy, sell or dividend")
+	}
+	return
+}
+
+func buildInstrumentPrice(userId int, symbol string, date string, value float64) (price models.InstrumentPrice, err error) {
+	symbol, err = normalizeSymbol(symbol)
+	if err != nil {
+		return
+	}
+
+	priceDate := startOfDay(time.Now())
+	if date != "" {
+		priceDate, err = time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			err = errors.New("invalid date: use YYYY-MM-DD")
+			return
+		}
+	}
+
+	if value <= 0 {
+		err = errors.New("price must be greater than 0")
+		return
+	}
+
+	price = models.InstrumentPrice{
+		UserId: userId,
+		Symbol: symbol,
+		Date:   priceDate,
+		Price:  value,
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"go-crud-api/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalculatePosition(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	buys := []models.InvestmentActivity{
+		{Type: ActivityBuy, Date: day(1), Units: 10, Amount: 1000},
+		{Type: ActivityBuy, Date: day(2), Units: 10, Amount: 2000},
+	}
+	activities := func(more ...models.InvestmentActivity) []models.InvestmentActivity {
+		return append(append([]models.InvestmentActivity{}, buys...), more...)
+	}
+
+	tests := []struct {
+		name             string
+		method           string
+		activities       []models.InvestmentActivity
+		wantUnits        float64
+		wantCostBasis    float64
+		wantRealizedGain float64
+		wantDividends    float64
+		wantError        string
+	}{
+		// FIFO sells the 10 units at 100 first, then 5 at 200
+		{"fifo partial sell", CostFIFO, activities(models.InvestmentActivity{Type: ActivitySell, Date: day(3), Units: 15, Amount: 3000}), 5, 1000, 1000, 0, ""},
+		// Average cost sells at 150 a unit
+		{"average partial sell", CostAverage, activities(models.InvestmentActivity{Type: ActivitySell, Date: day(3), Units: 15, Amount: 3000}), 5, 750, 750, 0, ""},
+		{"fifo sell everything", CostFIFO, activities(models.InvestmentActivity{Type: ActivitySell, Date: day(3), Units: 20, Amount: 4000}), 0, 0, 1000, 0, ""},
+		{"average sell everything", CostAverage, activities(models.InvestmentActivity{Type: ActivitySell, Date: day(3), Units: 20, Amount: 4000}), 0, 0, 1000, 0, ""},
+		{"dividends", CostFIFO, activities(models.InvestmentActivity{Type: ActivityDividend, Date: day(3), Amount: 50}), 20, 3000, 0, 50, ""},
+		{"fifo overselling", CostFIFO, activities(models.InvestmentActivity{Type: ActivitySell, Date: day(3), Units: 25, Amount: 5000}), 0, 0, 0, 0, "selling 25 units on 2026-10-03 exceeds the 20 units held"},
+		{"average overselling", CostAverage, activities(models.InvestmentActivity{Type: ActivitySell, Date: day(3), Units: 25, Amount: 5000}), 0, 0, 0, 0, "exceeds the 20 units held"},
+		{"selling before buying", CostFIFO, []models.InvestmentActivity{{Type: ActivitySell, Date: day(1), Units: 1, Amount: 100}}, 0, 0, 0, 0, "exceeds the 0 units held"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := calculatePosition(test.activities, test.method)
+			if test.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantError) {
+					t.Fatalf("expected an error containing %q, got %v", test.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if roundUnits(result.units) != test.wantUnits || roundMoney(result.costBasis) != test.wantCostBasis ||
+				roundMoney(result.realizedGain) != test.wantRealizedGain || roundMoney(result.dividends) != test.wantDividends {
+				t.Errorf("expected %v units, cost basis %v, realized gain %v and dividends %v, got %v, %v, %v and %v",
+					test.wantUnits, test.wantCostBasis, test.wantRealizedGain, test.wantDividends,
+					result.units, result.costBasis, result.realizedGain, result.dividends)
+			}
+		})
+	}
+}
@@ -0,0 +1,475 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-crud-api/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	AssetKindAsset     = "asset"
+	AssetKindLiability = "liability"
+
+	SnapshotManual    = "manual"
+	SnapshotScheduled = "scheduled"
+
+	maxAssetNameLength = 100
+	maxAssetTypeLength = 50
+
+	// defaultNetWorthMonths is how far back the series looks by default
+	defaultNetWorthMonths = 12
+)
+
+func (s *service) CreateAsset(userId int, req models.RequestCreateAsset) (asset models.ResponseAsset, err error) {
+	newAsset, err := buildAsset(userId, models.RequestUpdateAsset{Name: req.Name, Kind: req.Kind, Type: req.Type, Note: req.Note})
+	if err != nil {
+		return
+	}
+
+	var valuation *models.AssetValuation
+	if req.Value != nil {
+		valuation, err = buildAssetValuation(userId, models.RequestCreateAssetValuation{Value: *req.Value, Date: req.ValuedAt})
+		if err != nil {
+			return
+		}
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		created, errCreate := s.Repository.CreateAsset(tx, newAsset)
+		if errCreate != nil {
+			return errCreate
+		}
+		newAsset = created
+
+		if valuation != nil {
+			valuation.AssetId = created.Id
+			_, errCreate = s.Repository.CreateAssetValuation(tx, *valuation)
+		}
+		return errCreate
+	})
+	if err != nil {
+		return
+	}
+
+	asset, err = s.toAssetResponse(newAsset)
+	return
+}
+
+func (s *service) GetAssets(userId int) (assets []models.ResponseAsset, err error) {
+	list, err := s.Repository.GetAssets(s.Db, userId)
+	if err != nil {
+		return
+	}
+
+	latest, err := s.latestAssetValues(userId, time.Now())
+	if err != nil {
+		return
+	}
+
+	assets = make([]models.ResponseAsset, 0, len(list))
+	for _, asset := range list {
+		response := models.ResponseAsset{Asset: asset}
+		if valuation, ok := latest[asset.Id]; ok {
+			value, valuedAt := valuation.Value, valuation.ValuedAt.Format("2006-01-02")
+			response.CurrentValue = &value
+			response.ValuedAt = &valuedAt
+		}
+		assets = append(assets, response)
+	}
+	return
+}
+
+func (s *service) GetAssetById(req models.RequestGetAssetById, userId int) (asset models.ResponseAsset, err error) {
+	existingAsset, err := s.getOwnedAsset(req.Id, userId)
+	if err != nil {
+		return
+	}
+
+	asset, err = s.toAssetResponse(existingAsset)
+	return
+}
+
+func (s *service) UpdateAsset(id int, userId int, req models.RequestUpdateAsset) (asset models.ResponseAsset, err error) {
+	existingAsset, err := s.getOwnedAsset(id, userId)
+	if err != nil {
+		return
+	}
+
+	updated, err := buildAsset(userId, req)
+	if err != nil {
+		return
+	}
+	updated.Id = existingAsset.Id
+	updated.CreatedAt = existingAsset.CreatedAt
+
+	err = s.Repository.UpdateAsset(s.Db, updated)
+	if err != nil {
+		return
+	}
+
+	updated, err = s.Repository.GetAssetById(s.Db, id)
+	if err != nil {
+		return
+	}
+	asset, err = s.toAssetResponse(updated)
+	return
+}
+
+// DeleteAsset removes the asset and its valuations. Stored snapshots keep
+// the values they were taken with.
+func (s *service) DeleteAsset(id int, userId int) (err error) {
+	_, err = s.getOwnedAsset(id, userId)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		return s.Repository.DeleteAsset(tx, id)
+	})
+	return
+}
+
+func (s *service) CreateAssetValuation(assetId int, userId int, req models.RequestCreateAssetValuation) (valuation models.AssetValuation, err error) {
+	_, err = s.getOwnedAsset(assetId, userId)
+	if err != nil {
+		return
+	}
+
+	newValuation, err := buildAssetValuation(userId, req)
+	if err != nil {
+		return
+	}
+	newValuation.AssetId = assetId
+
+	valuation, err = s.Repository.CreateAssetValuation(s.Db, *newValuation)
+	return
+}
+
+func (s *service) GetAssetValuations(assetId int, userId int) (valuations []models.AssetValuation, err error) {
+	_, err = s.getOwnedAsset(assetId, userId)
+	if err != nil {
+		return
+	}
+
+	valuations, err = s.Repository.GetAssetValuations(s.Db, assetId)
+	if valuations == nil {
+		valuations = []models.AssetValuation{}
+	}
+	return
+}
+
+func (s *service) DeleteAssetValuation(req models.RequestGetAssetValuation, userId int) (err error) {
+	_, err = s.getOwnedAsset(req.AssetId, userId)
+	if err != nil {
+		return
+	}
+
+	valuation, err := s.Repository.GetAssetValuationById(s.Db, req.ValuationId)
+	if err != nil {
+		return
+	}
+	if valuation.AssetId != req.AssetId {
+		err = gorm.ErrRecordNotFound
+		return
+	}
+
+	err = s.Repository.DeleteAssetValuation(s.Db, valuation.Id)
+	return
+}
+
+// GetNetWorth calculates the current net worth without storing it.
+func (s *service) GetNetWorth(userId int) (netWorth models.NetWorth, err error) {
+	netWorth, err = s.calculateNetWorth(userId, time.Now())
+	return
+}
+
+func (s *service) CreateNetWorthSnapshot(userId int) (snapshot models.NetWorthSnapshot, err error) {
+	snapshot, err = s.saveNetWorthSnapshot(userId, SnapshotManual, time.Now())
+	return
+}
+
+// CreateScheduledNetWorthSnapshots stores today's snapshot of every user.
+// It is run by the scheduler; a failing user does not stop the others.
+func (s *service) CreateScheduledNetWorthSnapshots() (err error) {
+	_, users, err := s.Repository.GetAllUsers(s.Db, models.QueryPagination{Limit: -1, Offset: -1, Sort: models.QuerySort{Column: "id"}})
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	var failed []string
+	for _, user := range users {
+		_, errSnapshot := s.saveNetWorthSnapshot(user.Id, SnapshotScheduled, now)
+		if errSnapshot != nil {
+			failed = append(failed, fmt.Sprintf("user %d: %v", user.Id, errSnapshot))
+		}
+	}
+	if len(failed) > 0 {
+		err = fmt.Errorf("net worth snapshot failed for %d of %d users: %s", len(failed), len(users), strings.Join(failed, "; "))
+	}
+	return
+}
+
+// GetNetWorthSeries reports the stored snapshots as a time series. With a
+// week or month interval the last snapshot of each period is used. The
+// range defaults to the last twelve months.
+func (s *service) GetNetWorthSeries(req models.RequestGetNetWorthSeries, userId int) (points []models.NetWorthPoint, err error) {
+	now := time.Now()
+	startDate := now.AddDate(0, -defaultNetWorthMonths, 0).Format("2006-01-02")
+	endDate := now.Format("2006-01-02")
+	if req.StartDate != "" {
+		startDate = req.StartDate
+	}
+	if req.EndDate != "" {
+		endDate = req.EndDate
+	}
+	if _, errDate := time.Parse("2006-01-02", startDate); errDate != nil {
+		err = errors.New("invalid start_date: use YYYY-MM-DD")
+		return
+	}
+	if _, errDate := time.Parse("2006-01-02", endDate); errDate != nil {
+		err = errors.New("invalid end_date: use YYYY-MM-DD")
+		return
+	}
+
+	interval := req.Interval
+	if interval == "" {
+		interval = "day"
+	}
+	if interval != "day" && interval != "week" && interval != "month" {
+		err = errors.New("invalid interval: use day, week or month")
+		return
+	}
+
+	snapshots, err := s.Repository.GetNetWorthSnapshots(s.Db, userId, startDate, endDate)
+	if err != nil {
+		return
+	}
+
+	points = []models.NetWorthPoint{}
+	lastPeriod := ""
+	for _, snapshot := range snapshots {
+		period := snapshot.SnapshotDate.Format("2006-01-02")
+		switch interval {
+		case "week":
+			year, week := snapshot.SnapshotDate.ISOWeek()
+			period = fmt.Sprintf("%d-W%02d", year, week)
+		case "month":
+			period = snapshot.SnapshotDate.Format("2006-01")
+		}
+
+		point := models.NetWorthPoint{
+			Date:             snapshot.SnapshotDate.Format("2006-01-02"),
+			TotalAssets:      snapshot.TotalAssets,
+			TotalLiabilities: snapshot.TotalLiabilities,
+			NetWorth:         snapshot.NetWorth.NetWorth,
+		}
+		// Snapshots are ordered by date, so a later one in the same period
+		// replaces the point taken so far
+		if period == lastPeriod {
+			points[len(points)-1] = point
+		} else {
+			points = append(points, point)
+		}
+		lastPeriod = period
+	}
+
+	for i := 1; i < len(points); i++ {
+		points[i].Change = roundMoney(points[i].NetWorth - points[i-1].NetWorth)
+	}
+	return
+}
+
+func (s *service) saveNetWorthSnapshot(userId int, source string, now time.Time) (snapshot models.NetWorthSnapshot, err error) {
+	netWorth, err := s.calculateNetWorth(userId, now)
+	if err != nil {
+		return
+	}
+
+	snapshot, err = s.Repository.SaveNetWorthSnapshot(s.Db, models.NetWorthSnapshot{
+		UserId:       userId,
+		SnapshotDate: startOfDay(now),
+		Source:       source,
+		NetWorth:     netWorth,
+	})
+	return
+}
+
+// calculateNetWorth combines the cash balance (all income minus all
+// expense), the latest manual valuations and the outstanding principal of
+// the debts: lent money is an asset, borrowed money a liability.
+func (s *service) calculateNetWorth(userId int, now time.Time) (netWorth models.NetWorth, err error) {
+	cash, _, err := s.Repository.SumTransactions(s.Db, models.QueryTransactionFilter{UserId: userId})
+	if err != nil {
+		return
+	}
+	netWorth.CashBalance = roundMoney(cash)
+	netWorth.Items = []models.NetWorthItem{{Source: "cash", Name: "Cash balance", Kind: AssetKindAsset, Value: netWorth.CashBalance}}
+
+	assets, err := s.Repository.GetAssets(s.Db, userId)
+	if err != nil {
+		return
+	}
+	latest, err := s.latestAssetValues(userId, now)
+	if err != nil {
+		return
+	}
+	for _, asset := range assets {
+		valuation, ok := latest[asset.Id]
+		if !ok {
+			continue
+		}
+		if asset.Kind == AssetKindLiability {
+			netWorth.ManualLiabilities += valuation.Value
+		} else {
+			netWorth.ManualAssets += valuation.Value
+		}
+		netWorth.Items = append(netWorth.Items, models.NetWorthItem{Source: "asset", Id: asset.Id, Name: asset.Name, Kind: asset.Kind, Value: valuation.Value})
+	}
+
+	debts, err := s.Repository.GetDebts(s.Db, userId)
+	if err != nil {
+		return
+	}
+	for _, debt := range debts {
+		installments, _, errDebt := s.debtInstallments(debt)
+		if errDebt != nil {
+			err = errDebt
+			return
+		}
+		outstanding := outstandingPrincipal(debt, installments)
+		if outstanding == 0 {
+			continue
+		}
+
+		kind := AssetKindLiability
+		if debt.Direction == DebtLent {
+			kind = AssetKindAsset
+			netWorth.DebtsReceivable += outstanding
+		} else {
+			netWorth.DebtsPayable += outstanding
+		}
+		netWorth.Items = append(netWorth.Items, models.NetWorthItem{Source: "debt", Id: debt.Id, Name: debt.Name, Kind: kind, Value: outstanding})
+	}
+
+	netWorth.ManualAssets = roundMoney(netWorth.ManualAssets)
+	netWorth.ManualLiabilities = roundMoney(netWorth.ManualLiabilities)
+	netWorth.DebtsReceivable = roundMoney(netWorth.DebtsReceivable)
+	netWorth.DebtsPayable = roundMoney(netWorth.DebtsPayable)
+	netWorth.TotalAssets = roundMoney(netWorth.CashBalance + netWorth.ManualAssets + netWorth.DebtsReceivable)
+	netWorth.TotalLiabilities = roundMoney(netWorth.ManualLiabilities + netWorth.DebtsPayable)
+	netWorth.NetWorth = roundMoney(netWorth.TotalAssets - netWorth.TotalLiabilities)
+	return
+}
+
+// latestAssetValues maps asset ids of the user to their valuation in force
+// at t.
+func (s *service) latestAssetValues(userId int, t time.Time) (latest map[int]models.AssetValuation, err error) {
+	valuations, err := s.Repository.GetLatestAssetValuations(s.Db, userId, t.Format("2006-01-02"))
+	if err != nil {
+		return
+	}
+
+	latest = make(map[int]models.AssetValuation, len(valuations))
+	for _, valuation := range valuations {
+		latest[valuation.AssetId] = valuation
+	}
+	return
+}
+
+func (s *service) toAssetResponse(asset models.Asset) (response models.ResponseAsset, err error) {
+	latest, err := s.latestAssetValues(asset.UserId, time.Now())
+	if err != nil {
+		return
+	}
+
+	response.Asset = asset
+	if valuation, ok := latest[asset.Id]; ok {
+		value, valuedAt := valuation.Value, valuation.ValuedAt.Format("2006-01-02")
+		response.CurrentValue = &value
+		response.ValuedAt = &valuedAt
+	}
+	return
+}
+
+func (s *service) getOwnedAsset(id int, userId int) (asset models.Asset, err error) {
+	asset, err = s.Repository.GetAssetById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	if asset.UserId != userId {
+		err = errors.New("unauthorized: asset does not belong to this user")
+		return
+	}
+	return
+}
+
+// buildAsset validates the request and turns it into an asset of the user.
+func buildAsset(userId int, req models.RequestUpdateAsset) (asset models.Asset, err error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		err = errors.New("asset name is required and cannot be empty")
+		return
+	}
+	if len(name) > maxAssetNameLength {
+		err = fmt.Errorf("asset name cannot be longer than %d characters", maxAssetNameLength)
+		return
+	}
+
+	kind := req.Kind
+	if kind == "" {
+		kind = AssetKindAsset
+	}
+	if kind != AssetKindAsset && kind != AssetKindLiability {
+		err = errors.New("kind must be asset or liability")
+		return
+	}
+
+	assetType := strings.TrimSpace(req.Type)
+	if len(assetType) > maxAssetTypeLength {
+		err = fmt.Errorf("type cannot be longer than %d characters", maxAssetTypeLength)
+		return
+	}
+
+	err = validateNote(req.Note)
+	if err != nil {
+		return
+	}
+
+	asset = models.Asset{
+		UserId: userId,
+		Name:   name,
+		Kind:   kind,
+		Type:   assetType,
+		Note:   req.Note,
+	}
+	return
+}
+
+func buildAssetValuation(userId int, req models.RequestCreateAssetValuation) (valuation *models.AssetValuation, err error) {
+	if req.Value < 0 {
+		err = errors.New("value cannot be negative")
+		return
+	}
+
+	valuedAt := startOfDay(time.Now())
+	if req.Date != "" {
+		valuedAt, err = time.ParseInLocation("2006-01-02", req.Date, time.Local)
+		if err != nil {
+			err = errors.New("invalid date: use YYYY-MM-DD")
+			return
+		}
+	}
+
+	valuation = &models.AssetValuation{
+		UserId:   userId,
+		Value:    req.Value,
+		ValuedAt: valuedAt,
+	}
+	return
+}
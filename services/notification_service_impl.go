@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"go-crud-api/notify"
+	"log"
+	"math"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	NotificationBudgetThreshold  = "budget_threshold"
+	NotificationLargeTransaction = "large_transaction"
+	NotificationBillDue          = "bill_due"
+
+	maxBudgetThresholds        = 5
+	maxBudgetThresholdPercent  = 1000
+	maxNotificationEmailLength = 254
+	// notificationDeliveryTimeout bounds one delivery attempt per channel
+	notificationDeliveryTimeout = 30 * time.Second
+)
+
+var defaultBudgetThresholds = []int{80, 100}
+
+func (s *service) GetNotifications(req models.RequestGetNotifications) (response models.ResponseNotificationList, err error) {
+	if req.Unread != "" && req.Unread != "true" && req.Unread != "false" {
+		err = errors.New("invalid unread: use true or false")
+		return
+	}
+
+	pagination, err := helper.ParsePagination(req.RequestPagination, notificationSortColumns, "created_at:desc")
+	if err != nil {
+		return
+	}
+
+	count, notifications, err := s.Repository.GetNotifications(s.Db, req.UserId, req.Unread == "true", pagination)
+	if err != nil {
+		return
+	}
+	notifications, hasMore := helper.TrimCursorPage(notifications, pagination)
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+
+	unreadCount, err := s.Repository.CountUnreadNotifications(s.Db, req.UserId)
+	if err != nil {
+		return
+	}
+
+	response = models.ResponseNotificationList{
+		Count:       count,
+		UnreadCount: unreadCount,
+		Page:        pagination.Page,
+		Limit:       pagination.Limit,
+		Data:        notifications,
+	}
+
+	if pagination.UseCursor && len(notifications) > 0 {
+		first, last := notifications[0], notifications[len(notifications)-1]
+		column := pagination.Sort.Column
+		response.NextCursor, response.PrevCursor = helper.CursorLinks(pagination, hasMore,
+			notificationSortValue(first, column), first.Id, notificationSortValue(last, column), last.Id)
+	}
+	return
+}
+
+func (s *service) MarkNotificationRead(req models.RequestGetNotificationById, userId int) (notification models.Notification, err error) {
+	notification, err = s.getOwnedNotification(req.Id, userId)
+	if err != nil || notification.ReadAt != nil {
+		return
+	}
+
+	err = s.Repository.MarkNotificationsRead(s.Db, userId, notification.Id)
+	if err != nil {
+		return
+	}
+
+	notification, err = s.Repository.GetNotificationById(s.Db, notification.Id)
+	return
+}
+
+func (s *service) MarkAllNotificationsRead(userId int) (err error) {
+	err = s.Repository.MarkNotificationsRead(s.Db, userId, 0)
+	return
+}
+
+func (s *service) GetNotificationSettings(userId int) (settings models.NotificationSettings, err error) {
+	settings, err = s.notificationSettings(userId)
+	return
+}
+
+func (s *service) UpdateNotificationSettings(userId int, req models.RequestUpdateNotificationSettings) (settings models.NotificationSettings, err error) {
+	if len(req.BudgetThresholds) > maxBudgetThresholds {
+		err = fmt.Errorf("budget_thresholds cannot have more than %d values", maxBudgetThresholds)
+		return
+	}
+	thresholds := []int{}
+	for _, threshold := range req.BudgetThresholds {
+		if threshold < 1 || threshold > maxBudgetThresholdPercent {
+			err = fmt.Errorf("budget_thresholds must be between 1 and %d percent", maxBudgetThresholdPercent)
+			return
+		}
+		if !slices.Contains(thresholds, threshold) {
+			thresholds = append(thresholds, threshold)
+		}
+	}
+	slices.Sort(thresholds)
+
+	if req.LargeTransactionAmount < 0 {
+		err = errors.New("large_transaction_amount cannot be negative")
+		return
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if email != "" {
+		if _, ok := s.Notifiers["email"]; !ok {
+			err = errors.New("email notifications are not configured on this server")
+			return
+		}
+		address, errAddress := mail.ParseAddress(email)
+		if errAddress != nil || address.Address != email || len(email) > maxNotificationEmailLength {
+			err = errors.New("invalid email")
+			return
+		}
+	}
+
+	webhookUrl := strings.TrimSpace(req.WebhookUrl)
+	if webhookUrl != "" {
+		err = validateWebhookUrl("webhook_url", webhookUrl)
+		if err != nil {
+			return
+		}
+	}
+
+	billReminders := true
+	if req.BillReminders != nil {
+		billReminders = *req.BillReminders
+	}
+
+	settings, err = s.Repository.SaveNotificationSettings(s.Db, models.NotificationSettings{
+		UserId:                 userId,
+		BudgetThresholds:       thresholds,
+		LargeTransactionAmount: req.LargeTransactionAmount,
+		BillReminders:          billReminders,
+		Email:                  email,
+		WebhookUrl:             webhookUrl,
+	})
+	return
+}
+
+// CreateBillReminders notifies every user of the unpaid bill occurrences
+// that are within the bill's remind_days_before. It is run by the
+// scheduler; each occurrence is notified once and a failing user does not
+// stop the others.
+func (s *service) CreateBillReminders() (err error) {
+	_, users, err := s.Repository.GetAllUsers(s.Db, models.QueryPagination{Limit: -1, Offset: -1, Sort: models.QuerySort{Column: "id"}})
+	if err != nil {
+		return
+	}
+
+	var failed []string
+	for _, user := range users {
+		errReminders := s.createBillReminders(user.Id)
+		if errReminders != nil {
+			failed = append(failed, fmt.Sprintf("user %d: %v", user.Id, errReminders))
+		}
+	}
+	if len(failed) > 0 {
+		err = fmt.Errorf("bill reminders failed for %d of %d users: %s", len(failed), len(users), strings.Join(failed, "; "))
+	}
+	return
+}
+
+func (s *service) createBillReminders(userId int) (err error) {
+	settings, err := s.notificationSettings(userId)
+	if err != nil || !settings.BillReminders {
+		return
+	}
+
+	bills, err := s.Repository.GetBills(s.Db, userId)
+	if err != nil || len(bills) == 0 {
+		return
+	}
+	remindDays := make(map[int]int, len(bills))
+	for _, bill := range bills {
+		remindDays[bill.Id] = bill.RemindDaysBefore
+	}
+
+	today := startOfDay(time.Now())
+	occurrences, err := s.billOccurrences(bills, today, today.AddDate(0, 0, maxBillRemindDays))
+	if err != nil {
+		return
+	}
+
+	for _, occurrence := range occurrences {
+		if occurrence.Status != BillUnpaid {
+			continue
+		}
+		dueDate, errDate := time.ParseInLocation("2006-01-02", occurrence.DueDate, time.Local)
+		if errDate != nil {
+			err = errDate
+			return
+		}
+		days := int(math.Round(dueDate.Sub(today).Hours() / 24))
+		if days > remindDays[occurrence.BillId] {
+			continue
+		}
+
+		when := fmt.Sprintf("in %d days", days)
+		switch days {
+		case 0:
+			when = "today"
+		case 1:
+			when = "tomorrow"
+		}
+		err = s.raiseNotification(settings, models.Notification{
+			Type:    NotificationBillDue,
+			Key:     fmt.Sprintf("bill_due:%d:%s", occurrence.BillId, occurrence.DueDate),
+			Title:   fmt.Sprintf("%s is due %s", occurrence.Name, when),
+			Message: fmt.Sprintf("%s of %.2f is due on %s.", occurrence.Name, occurrence.Amount, occurrence.DueDate),
+			Data: map[string]interface{}{
+				"bill_id":  occurrence.BillId,
+				"due_date": occurrence.DueDate,
+				"amount":   occurrence.Amount,
+			},
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// HandleNotificationEvent is the notification subscriber of the event
+// dispatcher. It raises the alerts of created and updated transactions;
+// notification keys make a second delivery of the same event harmless.
+func (s *service) HandleNotificationEvent(event models.DomainEvent) (err error) {
+	if event.Type != EventTransactionCreated && event.Type != EventTransactionUpdated {
+		return
+	}
+
+	var data struct {
+		Id int `json:"id"`
+	}
+	err = json.Unmarshal(event.Payload, &data)
+	if err != nil {
+		return
+	}
+
+	// The event describes the transaction when it was changed, but alerts
+	// are about its current state
+	transaction, err := s.Repository.GetTransactionById(s.Db, data.Id)
+	if err == gorm.ErrRecordNotFound {
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	err = s.checkTransactionAlerts(transaction)
+	return
+}
+
+func (s *service) checkTransactionAlerts(transaction models.Transaction) (err error) {
+	settings, err := s.notificationSettings(transaction.UserId)
+	if err != nil {
+		return
+	}
+
+	if settings.LargeTransactionAmount > 0 && transaction.Amount >= settings.LargeTransactionAmount {
+		err = s.raiseNotification(settings, models.Notification{
+			Type:    NotificationLargeTransaction,
+			Key:     fmt.Sprintf("large_transaction:%d", transaction.Id),
+			Title:   fmt.Sprintf("Large %s of %.2f", transaction.Type, transaction.Amount),
+			Message: fmt.Sprintf("A %s of %.2f in %s was recorded on %s.", transaction.Type, transaction.Amount, transaction.Category.Name, transaction.OccurredAt.Local().Format("2006-01-02")),
+			Data: map[string]interface{}{
+				"transaction_id": transaction.Id,
+				"amount":         transaction.Amount,
+				"type":           transaction.Type,
+			},
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	if transaction.Type != "expense" || len(settings.BudgetThresholds) == 0 {
+		return
+	}
+	err = s.checkBudgetThresholds(settings, transaction)
+	return
+}
+
+// checkBudgetThresholds compares the spending of the transaction's envelope
+// in its cycle with the envelope's funds (carried in plus assigned). Only
+// the highest threshold passed is notified, once per envelope and cycle.
+func (s *service) checkBudgetThresholds(settings models.NotificationSettings, transaction models.Transaction) (err error) {
+	budget, err := s.GetEnvelopes(models.RequestGetEnvelopes{Date: transaction.OccurredAt.Local().Format("2006-01-02")}, transaction.UserId)
+	if err != nil {
+		return
+	}
+
+	for _, envelope := range budget.Envelopes {
+		if envelope.CategoryId != transaction.CategoryId {
+			continue
+		}
+
+		funds := envelope.CarriedIn + envelope.Assigned
+		spent := -envelope.Activity
+		if funds <= 0 || spent <= 0 {
+			return
+		}
+		percent := spent / funds * 100
+
+		thresholds := slices.Clone(settings.BudgetThresholds)
+		slices.Sort(thresholds)
+		slices.Reverse(thresholds)
+		for _, threshold := range thresholds {
+			if percent < float64(threshold) {
+				continue
+			}
+			err = s.raiseNotification(settings, models.Notification{
+				Type:    NotificationBudgetThreshold,
+				Key:     fmt.Sprintf("budget:%d:%s:%d", envelope.CategoryId, budget.CycleStart, threshold),
+				Title:   fmt.Sprintf("%s budget reached %d%%", envelope.CategoryName, threshold),
+				Message: fmt.Sprintf("You have spent %.2f of %.2f (%.0f%%) in %s for the cycle %s to %s.", roundMoney(spent), roundMoney(funds), percent, envelope.CategoryName, budget.CycleStart, budget.CycleEnd),
+				Data: map[string]interface{}{
+					"category_id": envelope.CategoryId,
+					"cycle_start": budget.CycleStart,
+					"threshold":   threshold,
+					"spent":       roundMoney(spent),
+					"budget":      roundMoney(funds),
+				},
+			})
+			return
+		}
+		return
+	}
+	return
+}
+
+// raiseNotification stores the notification in the user's inbox and, when
+// it is new, delivers it to the user's channels.
+func (s *service) raiseNotification(settings models.NotificationSettings, notification models.Notification) (err error) {
+	notification.UserId = settings.UserId
+	created, notification, err := s.Repository.CreateNotification(s.Db, notification)
+	if err != nil || !created {
+		return
+	}
+
+	s.deliverNotification(settings, notification)
+	return
+}
+
+// deliverNotification sends the notification to every channel the user has
+// an address for. Delivery runs in the background so a slow mail server or
+// webhook does not hold up the request; failures are logged.
+func (s *service) deliverNotification(settings models.NotificationSettings, notification models.Notification) {
+	recipients := map[string]string{
+		"email":   settings.Email,
+		"webhook": settings.WebhookUrl,
+	}
+	message := notify.Message{
+		Id:        notification.Id,
+		UserId:    notification.UserId,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Body:      notification.Message,
+		Data:      notification.Data,
+		CreatedAt: notification.CreatedAt,
+	}
+
+	for name, recipient := range recipients {
+		channel, ok := s.Notifiers[name]
+		if recipient == "" || !ok {
+			continue
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), notificationDeliveryTimeout)
+			defer cancel()
+			if err := channel.Send(ctx, recipient, message); err != nil {
+				log.Printf("notification %d: %s delivery failed: %v", notification.Id, name, err)
+			}
+		}()
+	}
+}
+
+// notificationSettings returns the user's settings or the defaults when
+// the user never saved any.
+func (s *service) notificationSettings(userId int) (settings models.NotificationSettings, err error) {
+	settings, err = s.Repository.GetNotificationSettings(s.Db, userId)
+	if err == gorm.ErrRecordNotFound {
+		settings = models.NotificationSettings{
+			UserId:           userId,
+			BudgetThresholds: slices.Clone(defaultBudgetThresholds),
+			BillReminders:    true,
+		}
+		err = nil
+	}
+	if settings.BudgetThresholds == nil {
+		settings.BudgetThresholds = []int{}
+	}
+	return
+}
+
+func (s *service) getOwnedNotification(id int, userId int) (notification models.Notification, err error) {
+	notification, err = s.Repository.GetNotificationById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	if notification.UserId != userId {
+		err = errors.New("unauthorized: notification does not belong to this user")
+		return
+	}
+	return
+}
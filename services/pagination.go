@@ -0,0 +1,78 @@
+package services
+
+import "go-crud-api/models"
+
+// Whitelisted sort columns per list endpoint, and the value of that column
+// for a row so it can be encoded into the next/prev cursors.
+
+var transactionSortColumns = []string{"occurred_at", "created_at", "updated_at", "amount", "type"}
+
+var categorySortColumns = []string{"created_at", "updated_at", "name"}
+
+var userSortColumns = []string{"created_at", "updated_at", "name", "username"}
+
+var tagSortColumns = []string{"name", "created_at", "updated_at"}
+
+var notificationSortColumns = []string{"created_at"}
+
+var webhookDeliverySortColumns = []string{"created_at"}
+
+var domainEventSortColumns = []string{"created_at"}
+
+func transactionSortValue(transaction models.Transaction, column string) interface{} {
+	switch column {
+	case "occurred_at":
+		return transaction.OccurredAt
+	case "updated_at":
+		return transaction.UpdatedAt
+	case "amount":
+		return transaction.Amount
+	case "type":
+		return transaction.Type
+	}
+	return transaction.CreatedAt
+}
+
+func categorySortValue(category models.Category, column string) interface{} {
+	switch column {
+	case "updated_at":
+		return category.UpdatedAt
+	case "name":
+		return category.Name
+	}
+	return category.CreatedAt
+}
+
+func userSortValue(user models.User, column string) interface{} {
+	switch column {
+	case "updated_at":
+		return user.UpdatedAt
+	case "name":
+		return user.Name
+	case "username":
+		return user.Username
+	}
+	return user.CreatedAt
+}
+
+func tagSortValue(tag models.Tag, column string) interface{} {
+	switch column {
+	case "created_at":
+		return tag.CreatedAt
+	case "updated_at":
+		return tag.UpdatedAt
+	}
+	return tag.Name
+}
+
+func notificationSortValue(notification models.Notification, column string) interface{} {
+	return notification.CreatedAt
+}
+
+func webhookDeliverySortValue(delivery models.WebhookDelivery, column string) interface{} {
+	return delivery.CreatedAt
+}
+
+func domainEventSortValue(event models.DomainEvent, column string) interface{} {
+	return event.CreatedAt
+}
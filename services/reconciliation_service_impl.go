@@ -0,0 +1,295 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-crud-api/models"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TransactionUncleared  = "uncleared"
+	TransactionCleared    = "cleared"
+	TransactionReconciled = "reconciled"
+
+	ReconciliationOpen      = "open"
+	ReconciliationFinalized = "finalized"
+
+	maxAccountLength = 100
+)
+
+var errTransactionLocked = errors.New("transaction is reconciled and locked: unlock it before editing")
+
+// UpdateTransactionStatus marks a transaction as cleared or uncleared.
+// Transactions only become reconciled by finalizing a reconciliation.
+func (s *service) UpdateTransactionStatus(id int, userId int, req models.RequestUpdateTransactionStatus) (response models.TransactionResponse, err error) {
+	if req.Status != TransactionUncleared && req.Status != TransactionCleared {
+		err = errors.New("status must be uncleared or cleared")
+		return
+	}
+
+	var transaction models.Transaction
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		errTx = s.checkTransactionEditable(tx, id, userId)
+		if errTx != nil {
+			return
+		}
+
+		errTx = s.Repository.UpdateTransactionFields(tx, id, map[string]interface{}{"status": req.Status})
+		if errTx != nil {
+			return
+		}
+
+		transaction, errTx = s.recordTransactionUpdated(tx, id)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	response = toTransactionResponse(transaction)
+	return
+}
+
+// UnlockTransaction takes a reconciled transaction out of its session so it
+// can be edited again. It goes back to cleared and will be picked up by the
+// next reconciliation.
+func (s *service) UnlockTransaction(id int, userId int) (response models.TransactionResponse, err error) {
+	err = s.checkTransactionOwner(s.Db, id, userId)
+	if err != nil {
+		return
+	}
+
+	transaction, err := s.Repository.GetTransactionById(s.Db, id)
+	if err != nil {
+		return
+	}
+	if transaction.Status != TransactionReconciled {
+		err = errors.New("transaction is not reconciled")
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		errTx = s.Repository.UpdateTransactionFields(tx, id, map[string]interface{}{
+			"status":            TransactionCleared,
+			"reconciliation_id": nil,
+		})
+		if errTx != nil {
+			return
+		}
+
+		transaction, errTx = s.recordTransactionUpdated(tx, id)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	response = toTransactionResponse(transaction)
+	return
+}
+
+func (s *service) CreateReconciliation(userId int, req models.RequestCreateReconciliation) (response models.ResponseReconciliation, err error) {
+	account := strings.TrimSpace(req.Account)
+	if account == "" {
+		err = errors.New("account is required and cannot be empty")
+		return
+	}
+	if len(account) > maxAccountLength {
+		err = fmt.Errorf("account cannot be longer than %d characters", maxAccountLength)
+		return
+	}
+
+	statementEndDate, err := time.ParseInLocation("2006-01-02", req.StatementEndDate, time.Local)
+	if err != nil {
+		err = errors.New("statement_end_date is required: use YYYY-MM-DD")
+		return
+	}
+
+	if req.StatementBalance == nil {
+		err = errors.New("statement_balance is required")
+		return
+	}
+
+	// Transactions carry no account, so sessions run one at a time
+	_, err = s.Repository.FindOpenReconciliation(s.Db, userId)
+	if err == nil {
+		err = errors.New("an open reconciliation already exists: finalize or delete it first")
+		return
+	}
+	if err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	reconciliation, err := s.Repository.CreateReconciliation(s.Db, models.Reconciliation{
+		UserId:           userId,
+		Account:          account,
+		StatementEndDate: statementEndDate,
+		StatementBalance: *req.StatementBalance,
+		Status:           ReconciliationOpen,
+	})
+	if err != nil {
+		return
+	}
+
+	response, err = s.reconciliationSummary(s.Db, reconciliation)
+	return
+}
+
+func (s *service) GetReconciliations(userId int) (reconciliations []models.Reconciliation, err error) {
+	reconciliations, err = s.Repository.GetReconciliations(s.Db, userId)
+	if reconciliations == nil {
+		reconciliations = []models.Reconciliation{}
+	}
+	return
+}
+
+func (s *service) GetReconciliationById(req models.RequestGetReconciliationById, userId int) (response models.ResponseReconciliation, err error) {
+	reconciliation, err := s.getOwnedReconciliation(req.Id, userId)
+	if err != nil {
+		return
+	}
+
+	response, err = s.reconciliationSummary(s.Db, reconciliation)
+	return
+}
+
+// FinalizeReconciliation reconciles the cleared transactions of the session,
+// which is only allowed once they add up to the statement balance.
+func (s *service) FinalizeReconciliation(id int, userId int) (response models.ResponseReconciliation, err error) {
+	reconciliation, err := s.getOwnedReconciliation(id, userId)
+	if err != nil {
+		return
+	}
+	if reconciliation.Status != ReconciliationOpen {
+		err = errors.New("reconciliation is already finalized")
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		// Locking the session makes a concurrent finalize wait, then see it
+		// is no longer open
+		reconciliation, errTx := s.Repository.LockReconciliationById(tx, id)
+		if errTx != nil {
+			return errTx
+		}
+		if reconciliation.Status != ReconciliationOpen {
+			return errors.New("reconciliation is already finalized")
+		}
+
+		summary, errTx := s.reconciliationSummary(tx, reconciliation)
+		if errTx != nil {
+			return errTx
+		}
+		if summary.Difference != 0 {
+			return fmt.Errorf("cannot finalize: difference is %.2f, it must be zero", summary.Difference)
+		}
+
+		endDate := reconciliation.StatementEndDate.Format("2006-01-02")
+		ids, errTx := s.Repository.ReconcileTransactions(tx, userId, endDate, reconciliation.Id)
+		if errTx != nil {
+			return errTx
+		}
+
+		// Sync, streams and webhooks learn the transactions are now locked
+		for _, transactionId := range ids {
+			_, errTx = s.recordTransactionUpdated(tx, transactionId)
+			if errTx != nil {
+				return errTx
+			}
+		}
+
+		return s.Repository.UpdateReconciliationFields(tx, reconciliation.Id, map[string]interface{}{
+			"status":       ReconciliationFinalized,
+			"finalized_at": time.Now(),
+		})
+	})
+	if err != nil {
+		return
+	}
+
+	reconciliation, err = s.Repository.GetReconciliationById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	response, err = s.reconciliationSummary(s.Db, reconciliation)
+	return
+}
+
+func (s *service) DeleteReconciliation(id int, userId int) (err error) {
+	reconciliation, err := s.getOwnedReconciliation(id, userId)
+	if err != nil {
+		return
+	}
+	if reconciliation.Status != ReconciliationOpen {
+		err = errors.New("finalized reconciliations cannot be deleted")
+		return
+	}
+
+	err = s.Repository.DeleteReconciliation(s.Db, id)
+	return
+}
+
+// reconciliationSummary works out the totals of a session. An open session
+// counts every reconciled transaction plus the cleared ones up to the
+// statement end date; a finalized one counts the transactions it reconciled.
+func (s *service) reconciliationSummary(db *gorm.DB, reconciliation models.Reconciliation) (response models.ResponseReconciliation, err error) {
+	response.Reconciliation = reconciliation
+
+	if reconciliation.Status == ReconciliationFinalized {
+		response.ClearedTotal, response.ClearedCount, err = s.Repository.SumTransactions(db, models.QueryTransactionFilter{
+			UserId:           reconciliation.UserId,
+			ReconciliationId: reconciliation.Id,
+		})
+		if err != nil {
+			return
+		}
+		response.ReconciledBalance = roundMoney(reconciliation.StatementBalance - response.ClearedTotal)
+	} else {
+		response.ReconciledBalance, _, err = s.Repository.SumTransactions(db, models.QueryTransactionFilter{
+			UserId:   reconciliation.UserId,
+			Statuses: []string{TransactionReconciled},
+		})
+		if err != nil {
+			return
+		}
+
+		response.ClearedTotal, response.ClearedCount, err = s.Repository.SumTransactions(db, models.QueryTransactionFilter{
+			UserId:   reconciliation.UserId,
+			Statuses: []string{TransactionCleared},
+			EndDate:  reconciliation.StatementEndDate.Format("2006-01-02"),
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	response.ReconciledBalance = roundMoney(response.ReconciledBalance)
+	response.ClearedTotal = roundMoney(response.ClearedTotal)
+	response.ClearedBalance = roundMoney(response.ReconciledBalance + response.ClearedTotal)
+	response.Difference = roundMoney(reconciliation.StatementBalance - response.ClearedBalance)
+	return
+}
+
+func (s *service) getOwnedReconciliation(id int, userId int) (reconciliation models.Reconciliation, err error) {
+	reconciliation, err = s.Repository.GetReconciliationById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	if reconciliation.UserId != userId {
+		err = errors.New("unauthorized: reconciliation does not belong to this user")
+		return
+	}
+	return
+}
+
+// roundMoney rounds to cents so float sums compare cleanly against zero.
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"go-crud-api/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFinalizeReconciliation(t *testing.T) {
+	tests := []struct {
+		name             string
+		statementBalance float64
+		wantDifference   float64
+		wantError        string
+		wantReconciled   int
+	}{
+		{"balanced", 75000, 0, "", 2},
+		{"difference left", 80000, 5000, "cannot finalize: difference is 5000.00", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestService(t)
+			day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.Local) }
+			income := createTestTransaction(t, s, models.Transaction{UserId: 1, Type: "income", Amount: 100000, OccurredAt: day(1), Status: TransactionCleared})
+			expense := createTestTransaction(t, s, models.Transaction{UserId: 1, Amount: 25000, OccurredAt: day(2), Status: TransactionCleared})
+			// Neither counts: not cleared yet, and after the statement
+			uncleared := createTestTransaction(t, s, models.Transaction{UserId: 1, Amount: 10000, OccurredAt: day(3)})
+			createTestTransaction(t, s, models.Transaction{UserId: 1, Amount: 5000, OccurredAt: day(20), Status: TransactionCleared})
+
+			created, err := s.CreateReconciliation(1, models.RequestCreateReconciliation{
+				Account:          "BCA",
+				StatementEndDate: "2026-10-15",
+				StatementBalance: &test.statementBalance,
+			})
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			if created.Difference != test.wantDifference {
+				t.Errorf("expected a difference of %.2f, got %.2f", test.wantDifference, created.Difference)
+			}
+
+			_, err = s.FinalizeReconciliation(created.Id, 1)
+			if test.wantError != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), test.wantError) {
+					t.Fatalf("expected %q, got %v", test.wantError, err)
+				}
+			} else if err != nil {
+				t.Fatalf("finalize: %v", err)
+			}
+
+			var reconciled int64
+			s.Db.Model(&models.Transaction{}).Where("status = ? AND reconciliation_id = ?", TransactionReconciled, created.Id).Count(&reconciled)
+			if reconciled != int64(test.wantReconciled) {
+				t.Errorf("expected %d reconciled transactions, got %d", test.wantReconciled, reconciled)
+			}
+			var events int64
+			s.Db.Model(&models.DomainEvent{}).Where("type = ?", EventTransactionUpdated).Count(&events)
+			if events != int64(test.wantReconciled) {
+				t.Errorf("expected %d transaction.updated events, got %d", test.wantReconciled, events)
+			}
+			if test.wantReconciled == 0 {
+				return
+			}
+
+			// Reconciled transactions are locked on every write path
+			amount := 1.0
+			writes := map[string]func() error{
+				"update": func() error {
+					_, err := s.UpdateTransaction(expense.Id, 1, models.RequestUpdateTransaction{Amount: 1, Type: "expense", CategoryId: "1"})
+					return err
+				},
+				"patch": func() error {
+					_, err := s.PatchTransaction(income.Id, 1, models.RequestPatchTransaction{Amount: models.Nullable[float64]{Set: true, Value: amount}})
+					return err
+				},
+				"delete": func() error {
+					return s.DeleteTransaction(expense.Id, 1)
+				},
+			}
+			for name, write := range writes {
+				if err := write(); !errors.Is(err, errTransactionLocked) {
+					t.Errorf("%s of a reconciled transaction: expected it to be locked, got %v", name, err)
+				}
+			}
+			if _, err := s.PatchTransaction(uncleared.Id, 1, models.RequestPatchTransaction{Amount: models.Nullable[float64]{Set: true, Value: amount}}); err != nil {
+				t.Errorf("patch of an uncleared transaction: %v", err)
+			}
+
+			_, err = s.FinalizeReconciliation(created.Id, 1)
+			if err == nil || !strings.Contains(err.Error(), "already finalized") {
+				t.Errorf("expected a second finalize to fail, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,516 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RuleMatchContains = "contains"
+	RuleMatchRegex    = "regex"
+
+	maxRuleNameLength = 100
+	// ruleApplyPageSize is how many transactions ApplyRules reads and writes
+	// at a time.
+	ruleApplyPageSize = 500
+)
+
+func (s *service) CreateRule(userId int, req models.RequestCreateRule) (rule models.Rule, err error) {
+	rule, err = s.buildRule(userId, req)
+	if err != nil {
+		return
+	}
+
+	rule, err = s.Repository.CreateRule(s.Db, rule)
+	return
+}
+
+func (s *service) GetRules(userId int) (rules []models.Rule, err error) {
+	rules, err = s.Repository.GetRules(s.Db, userId)
+	if rules == nil {
+		rules = []models.Rule{}
+	}
+	return
+}
+
+func (s *service) GetRuleById(req models.RequestGetRuleById, userId int) (rule models.Rule, err error) {
+	rule, err = s.getOwnedRule(req.Id, userId)
+	return
+}
+
+func (s *service) UpdateRule(id int, userId int, req models.RequestUpdateRule) (rule models.Rule, err error) {
+	existingRule, err := s.getOwnedRule(id, userId)
+	if err != nil {
+		return
+	}
+
+	rule, err = s.buildRule(userId, models.RequestCreateRule(req))
+	if err != nil {
+		return
+	}
+	rule.Id = existingRule.Id
+	rule.CreatedAt = existingRule.CreatedAt
+
+	err = s.Repository.UpdateRule(s.Db, rule)
+	if err != nil {
+		return
+	}
+
+	rule, err = s.Repository.GetRuleById(s.Db, id)
+	return
+}
+
+func (s *service) DeleteRule(id int, userId int) (err error) {
+	_, err = s.getOwnedRule(id, userId)
+	if err != nil {
+		return
+	}
+
+	err = s.Repository.DeleteRule(s.Db, id)
+	return
+}
+
+// ApplyRules runs the rules over the user's existing transactions in a date
+// range. On a dry run only the diff is returned.
+func (s *service) ApplyRules(userId int, req models.RequestApplyRules) (response models.ResponseApplyRules, err error) {
+	// Default to the current cycle, like GetBalance
+	startDate := req.StartDate
+	endDate := req.EndDate
+	if startDate == "" || endDate == "" {
+		startDate, endDate = currentCycle(time.Now())
+	}
+	if _, errDate := time.Parse("2006-01-02", startDate); errDate != nil {
+		err = errors.New("invalid start_date: use YYYY-MM-DD")
+		return
+	}
+	if _, errDate := time.Parse("2006-01-02", endDate); errDate != nil {
+		err = errors.New("invalid end_date: use YYYY-MM-DD")
+		return
+	}
+
+	rules, err := s.Repository.GetRules(s.Db, userId)
+	if err != nil {
+		return
+	}
+
+	// Explicitly selected rules run even when disabled, so a new rule can be
+	// tried out with a dry run before enabling it
+	if len(req.RuleIds) > 0 {
+		for _, id := range req.RuleIds {
+			if !slices.ContainsFunc(rules, func(rule models.Rule) bool { return rule.Id == id }) {
+				err = fmt.Errorf("rule %d not found", id)
+				return
+			}
+		}
+
+		var selected []models.Rule
+		for _, rule := range rules {
+			if slices.Contains(req.RuleIds, rule.Id) {
+				rule.Enabled = true
+				selected = append(selected, rule)
+			}
+		}
+		rules = selected
+	}
+
+	response = models.ResponseApplyRules{
+		DryRun:    req.DryRun,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Changes:   []models.RuleChange{},
+	}
+
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return
+	}
+
+	// Transactions are read in keyset pages and each page is written in its
+	// own database transaction, so a long range never holds everything in
+	// memory or one transaction open for the whole run
+	filter := models.QueryTransactionFilter{UserId: userId, StartDate: startDate, EndDate: endDate}
+	pagination := models.QueryPagination{
+		Limit:     ruleApplyPageSize,
+		Offset:    -1,
+		Sort:      models.QuerySort{Column: "occurred_at"},
+		UseCursor: true,
+	}
+	for {
+		_, transactions, errPage := s.Repository.GetTransactions(s.Db, filter, pagination)
+		if errPage != nil {
+			err = errPage
+			return
+		}
+		transactions, hasMore := helper.TrimCursorPage(transactions, pagination)
+		response.Scanned += len(transactions)
+
+		var changes []models.RuleChange
+		for _, transaction := range transactions {
+			// Reconciled transactions are locked
+			if transaction.Status == TransactionReconciled {
+				continue
+			}
+
+			before := newRuleTarget(transaction)
+			after, ruleIds := runRules(compiled, before)
+			if !after.changedFrom(before) {
+				continue
+			}
+
+			changes = append(changes, models.RuleChange{
+				TransactionId: transaction.Id,
+				RuleIds:       ruleIds,
+				Before:        before.fields(),
+				After:         after.fields(),
+			})
+		}
+
+		if !req.DryRun && len(changes) > 0 {
+			err = s.Db.Transaction(func(tx *gorm.DB) error {
+				for _, change := range changes {
+					errChange := s.applyRuleChange(tx, userId, change)
+					if errChange != nil {
+						return errChange
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return
+			}
+		}
+		response.Changes = append(response.Changes, changes...)
+		response.Changed = len(response.Changes)
+
+		if !hasMore {
+			return
+		}
+		last := transactions[len(transactions)-1]
+		pagination.Cursor = &models.Cursor{
+			Sort:  "occurred_at",
+			Value: transactionSortValue(last, "occurred_at"),
+			Id:    last.Id,
+		}
+	}
+}
+
+// applyRuleChange writes one retroactive diff. Rules only ever add tags, so
+// the tags missing from Before are attached.
+func (s *service) applyRuleChange(tx *gorm.DB, userId int, change models.RuleChange) (err error) {
+	updateData := map[string]interface{}{}
+	if change.After.CategoryId != change.Before.CategoryId {
+		err = s.validateCategoryId(tx, change.After.CategoryId)
+		if err != nil {
+			return
+		}
+		updateData["category_id"] = change.After.CategoryId
+	}
+	if change.After.Payee != change.Before.Payee {
+		updateData["payee"] = change.After.Payee
+	}
+	if len(updateData) > 0 {
+		err = s.Repository.UpdateTransactionFields(tx, change.TransactionId, updateData)
+		if err != nil {
+			return
+		}
+	}
+
+	var addedTags []string
+	for _, name := range change.After.Tags {
+		if !containsTagName(change.Before.Tags, name) {
+			addedTags = append(addedTags, name)
+		}
+	}
+	if len(addedTags) > 0 {
+		tags, errTags := s.resolveTags(tx, userId, addedTags, true)
+		if errTags != nil {
+			err = errTags
+			return
+		}
+		err = s.Repository.AddTransactionTags(tx, change.TransactionId, tags)
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = s.recordTransactionUpdated(tx, change.TransactionId)
+	return
+}
+
+// applyUserRules runs the user's enabled rules over a transaction that is
+// about to be created.
+func (s *service) applyUserRules(db *gorm.DB, userId int, target ruleTarget) (result ruleTarget, err error) {
+	rules, err := s.Repository.GetRules(db, userId)
+	if err != nil {
+		return
+	}
+
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return
+	}
+
+	result, _ = runRules(compiled, target)
+	return
+}
+
+// newTransactionCategory picks the category of a new transaction: an
+// explicit category_id wins over the rules, and the default of an import
+// only fills in when no rule set one.
+func newTransactionCategory(req models.RequestCreateTransaction, target ruleTarget) int {
+	switch {
+	case req.CategoryId != 0:
+		return req.CategoryId
+	case target.CategoryId != 0:
+		return target.CategoryId
+	}
+	return req.DefaultCategoryId
+}
+
+func (s *service) getOwnedRule(id int, userId int) (rule models.Rule, err error) {
+	rule, err = s.Repository.GetRuleById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	if rule.UserId != userId {
+		err = errors.New("unauthorized: rule does not belong to this user")
+		return
+	}
+	return
+}
+
+// buildRule validates the request and turns it into a rule of the user.
+func (s *service) buildRule(userId int, req models.RequestCreateRule) (rule models.Rule, err error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		err = errors.New("rule name is required and cannot be empty")
+		return
+	}
+	if len(name) > maxRuleNameLength {
+		err = fmt.Errorf("rule name cannot be longer than %d characters", maxRuleNameLength)
+		return
+	}
+
+	matchMode := req.MatchMode
+	if matchMode == "" {
+		matchMode = RuleMatchContains
+	}
+	if matchMode != RuleMatchContains && matchMode != RuleMatchRegex {
+		err = errors.New("match_mode must be contains or regex")
+		return
+	}
+	if matchMode == RuleMatchRegex {
+		if _, errRegex := regexp.Compile(req.PayeeMatch); errRegex != nil {
+			err = errors.New("invalid payee_match regex: " + errRegex.Error())
+			return
+		}
+		if _, errRegex := regexp.Compile(req.NoteMatch); errRegex != nil {
+			err = errors.New("invalid note_match regex: " + errRegex.Error())
+			return
+		}
+	}
+
+	if (req.MinAmount != nil && *req.MinAmount < 0) || (req.MaxAmount != nil && *req.MaxAmount < 0) {
+		err = errors.New("min_amount and max_amount cannot be negative")
+		return
+	}
+	if req.MinAmount != nil && req.MaxAmount != nil && *req.MinAmount > *req.MaxAmount {
+		err = errors.New("min_amount cannot be greater than max_amount")
+		return
+	}
+
+	if req.PayeeMatch == "" && req.NoteMatch == "" && req.MinAmount == nil && req.MaxAmount == nil && req.Type == "" {
+		err = errors.New("rule needs at least one condition")
+		return
+	}
+
+	if req.SetCategoryId != nil {
+		err = s.validateCategoryId(s.Db, *req.SetCategoryId)
+		if err != nil {
+			return
+		}
+	}
+
+	addTags, err := normalizeTagNames(req.AddTags)
+	if err != nil {
+		return
+	}
+
+	err = validatePayee(req.SetPayee)
+	if err != nil {
+		return
+	}
+
+	if req.SetCategoryId == nil && len(addTags) == 0 && req.SetPayee == "" {
+		err = errors.New("rule needs at least one action")
+		return
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	rule = models.Rule{
+		UserId:        userId,
+		Name:          name,
+		Priority:      req.Priority,
+		Enabled:       enabled,
+		MatchMode:     matchMode,
+		PayeeMatch:    req.PayeeMatch,
+		NoteMatch:     req.NoteMatch,
+		MinAmount:     req.MinAmount,
+		MaxAmount:     req.MaxAmount,
+		Type:          req.Type,
+		SetCategoryId: req.SetCategoryId,
+		AddTags:       addTags,
+		SetPayee:      req.SetPayee,
+	}
+	return
+}
+
+// ruleTarget is the part of a transaction the rules look at and change.
+type ruleTarget struct {
+	Amount     float64
+	Type       string
+	Note       string
+	Payee      string
+	CategoryId int
+	Tags       []string
+}
+
+func newRuleTarget(transaction models.Transaction) ruleTarget {
+	target := ruleTarget{
+		Amount:     transaction.Amount,
+		Type:       transaction.Type,
+		Note:       transaction.Note,
+		Payee:      transaction.Payee,
+		CategoryId: transaction.CategoryId,
+		Tags:       []string{},
+	}
+	for _, tag := range transaction.Tags {
+		target.Tags = append(target.Tags, tag.Name)
+	}
+	return target
+}
+
+func (t ruleTarget) changedFrom(before ruleTarget) bool {
+	return t.CategoryId != before.CategoryId || t.Payee != before.Payee || len(t.Tags) != len(before.Tags)
+}
+
+func (t ruleTarget) fields() models.RuleFields {
+	return models.RuleFields{CategoryId: t.CategoryId, Payee: t.Payee, Tags: t.Tags}
+}
+
+// compiledRule is an enabled rule with its regex conditions compiled once.
+type compiledRule struct {
+	models.Rule
+	payeePattern *regexp.Regexp
+	notePattern  *regexp.Regexp
+}
+
+// compileRules compiles the enabled rules. Patterns are validated when a rule
+// is saved, but one changed in the database since fails here instead of
+// panicking.
+func compileRules(rules []models.Rule) (compiled []compiledRule, err error) {
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+
+		item := compiledRule{Rule: rule}
+		if rule.MatchMode == RuleMatchRegex {
+			if rule.PayeeMatch != "" {
+				item.payeePattern, err = regexp.Compile(rule.PayeeMatch)
+				if err != nil {
+					return nil, fmt.Errorf("rule %d has an invalid payee_match regex: %v", rule.Id, err)
+				}
+			}
+			if rule.NoteMatch != "" {
+				item.notePattern, err = regexp.Compile(rule.NoteMatch)
+				if err != nil {
+					return nil, fmt.Errorf("rule %d has an invalid note_match regex: %v", rule.Id, err)
+				}
+			}
+		}
+		compiled = append(compiled, item)
+	}
+	return
+}
+
+func (r compiledRule) matches(target ruleTarget) bool {
+	if !matchRuleText(r.PayeeMatch, r.payeePattern, target.Payee) {
+		return false
+	}
+	if !matchRuleText(r.NoteMatch, r.notePattern, target.Note) {
+		return false
+	}
+	if r.MinAmount != nil && target.Amount < *r.MinAmount {
+		return false
+	}
+	if r.MaxAmount != nil && target.Amount > *r.MaxAmount {
+		return false
+	}
+	if r.Type != "" && !strings.EqualFold(r.Type, target.Type) {
+		return false
+	}
+	return true
+}
+
+// matchRuleText reports whether value matches the condition; contains is
+// case-insensitive and an empty condition always matches.
+func matchRuleText(condition string, pattern *regexp.Regexp, value string) bool {
+	if condition == "" {
+		return true
+	}
+	if pattern != nil {
+		return pattern.MatchString(value)
+	}
+	return strings.Contains(strings.ToLower(value), strings.ToLower(condition))
+}
+
+// runRules applies the matching rules in priority order. Conditions are
+// checked against the transaction as entered, the first matching rule that
+// sets the category or payee wins, and tags of every matching rule are added.
+func runRules(rules []compiledRule, target ruleTarget) (result ruleTarget, ruleIds []int) {
+	result = target
+	result.Tags = slices.Clone(target.Tags)
+	categorySet, payeeSet := false, false
+
+	for _, rule := range rules {
+		if !rule.matches(target) {
+			continue
+		}
+		ruleIds = append(ruleIds, rule.Id)
+
+		if rule.SetCategoryId != nil && !categorySet {
+			result.CategoryId = *rule.SetCategoryId
+			categorySet = true
+		}
+		if rule.SetPayee != "" && !payeeSet {
+			result.Payee = rule.SetPayee
+			payeeSet = true
+		}
+		for _, name := range rule.AddTags {
+			if !containsTagName(result.Tags, name) {
+				result.Tags = append(result.Tags, name)
+			}
+		}
+	}
+	return
+}
+
+func containsTagName(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(existing string) bool {
+		return strings.EqualFold(existing, name)
+	})
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"go-crud-api/models"
+	"slices"
+	"testing"
+)
+
+func TestNewTransactionRules(t *testing.T) {
+	transport, groceries := 2, 3
+	tests := []struct {
+		name         string
+		req          models.RequestCreateTransaction
+		wantCategory int
+		wantPayee    string
+		wantTags     []string
+	}{
+		{
+			// Both rules match: the lower priority number sets the category
+			// and payee, and the tags of both are added
+			name:         "priority",
+			req:          models.RequestCreateTransaction{Amount: 25000, Type: "expense", Payee: "GOJEK *ride"},
+			wantCategory: transport,
+			wantPayee:    "Gojek",
+			wantTags:     []string{"ojol", "harian"},
+		},
+		{
+			name:         "explicit category",
+			req:          models.RequestCreateTransaction{Amount: 25000, Type: "expense", Payee: "GOJEK *ride", CategoryId: 1},
+			wantCategory: 1,
+			wantPayee:    "Gojek",
+			wantTags:     []string{"ojol", "harian"},
+		},
+		{
+			// Only the second rule matches above 100000
+			name:         "amount condition",
+			req:          models.RequestCreateTransaction{Amount: 150000, Type: "expense", Payee: "gojek mart"},
+			wantCategory: groceries,
+			wantTags:     []string{"harian"},
+			wantPayee:    "gojek mart",
+		},
+		{
+			name:         "import default",
+			req:          models.RequestCreateTransaction{Amount: 25000, Type: "expense", Payee: "Warung", DefaultCategoryId: 1},
+			wantCategory: 1,
+			wantPayee:    "Warung",
+			wantTags:     []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestService(t)
+			for _, id := range []int{transport, groceries} {
+				s.Db.Create(&models.Category{Id: id, Uuid: newUuid(), Name: "Kategori"})
+			}
+
+			// Created in reverse priority order
+			maxAmount := 100000.0
+			for _, rule := range []models.Rule{
+				{UserId: 1, Name: "Gojek", Priority: 20, Enabled: true, MatchMode: RuleMatchContains, PayeeMatch: "gojek", SetCategoryId: &groceries, AddTags: []string{"harian"}},
+				{UserId: 1, Name: "Ojol", Priority: 10, Enabled: true, MatchMode: RuleMatchRegex, PayeeMatch: "(?i)^gojek \\*", MaxAmount: &maxAmount, SetCategoryId: &transport, SetPayee: "Gojek", AddTags: []string{"ojol"}},
+				{UserId: 1, Name: "Disabled", Priority: 0, Enabled: false, PayeeMatch: "gojek", SetPayee: "Never"},
+				{UserId: 2, Name: "Other user", Priority: 0, Enabled: true, PayeeMatch: "gojek", SetPayee: "Never"},
+			} {
+				if err := s.Db.Create(&rule).Error; err != nil {
+					t.Fatalf("create rule: %v", err)
+				}
+			}
+
+			transaction, err := s.CreateTransaction(1, test.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var tags []string
+			for _, tag := range transaction.Tags {
+				tags = append(tags, tag.Name)
+			}
+			slices.Sort(tags)
+			wantTags := slices.Sorted(slices.Values(test.wantTags))
+			if transaction.Category.Id != test.wantCategory || transaction.Payee != test.wantPayee || !slices.Equal(tags, wantTags) {
+				t.Errorf("expected category %d, payee %q and tags %v, got category %d, payee %q and tags %v",
+					test.wantCategory, test.wantPayee, wantTags, transaction.Category.Id, transaction.Payee, tags)
+			}
+		})
+	}
+}
+
+func TestCompileRulesRejectsInvalidPattern(t *testing.T) {
+	rules := []models.Rule{
+		{Id: 1, Enabled: true, MatchMode: RuleMatchRegex, PayeeMatch: "gojek"},
+		{Id: 2, Enabled: true, MatchMode: RuleMatchRegex, NoteMatch: "(unclosed"},
+	}
+	if _, err := compileRules(rules); err == nil {
+		t.Fatal("expected an error for the invalid pattern")
+	}
+
+	// A disabled rule is not compiled
+	rules[1].Enabled = false
+	if compiled, err := compileRules(rules); err != nil || len(compiled) != 1 {
+		t.Fatalf("expected one compiled rule, got %d and %v", len(compiled), err)
+	}
+}
@@ -12,16 +12,153 @@ type Service interface {
 	GetCategories(req models.RequestGetCategories) (response models.ResponseCategoryList, err error)
 	GetCategoryById(req models.RequestGetCategoryById) (category models.Category, err error)
 	UpdateCategory(id int, req models.RequestUpdateCategory) (err error)
+	PatchCategory(id int, req models.RequestPatchCategory) (category models.Category, err error)
 	DeleteCategory(id int) (err error)
 	CreateTransaction(userId int, req models.RequestCreateTransaction) (response models.TransactionResponse, err error)
 	GetTransactions(req models.RequestGetTransactions) (response models.ResponseTransactionList, err error)
 	GetTransactionById(req models.RequestGetTransactionById, userId int) (response models.TransactionResponse, err error)
 	UpdateTransaction(id int, userId int, req models.RequestUpdateTransaction) (response models.TransactionResponse, err error)
+	PatchTransaction(id int, userId int, req models.RequestPatchTransaction) (response models.TransactionResponse, err error)
 	DeleteTransaction(id int, userId int) (err error)
+	BulkTransactions(userId int, req models.RequestBulkTransactions) (response models.ResponseBulkTransactions, err error)
+	ImportTransactions(userId int, req models.RequestImportTransactions) (response models.ResponseImportTransactions, err error)
 	GetBalance(req models.RequestGetBalance) (response models.ResponseBalance, err error)
 	// Admin user management
 	GetAllUsers(req models.RequestGetAllUsers) (response models.ResponseUserList, err error)
 	AdminCreateUser(req models.RequestCreateUser) (user models.User, err error)
 	AdminUpdateUser(id int, req models.RequestUpdateUser) (user models.User, err error)
+	AdminPatchUser(id int, req models.RequestPatchUser) (user models.User, err error)
 	AdminDeleteUser(id int) (err error)
+	// Tags
+	CreateTag(userId int, req models.RequestCreateTag) (tag models.Tag, err error)
+	GetTags(req models.RequestGetTags) (response models.ResponseTagList, err error)
+	GetTagById(req models.RequestGetTagById, userId int) (tag models.Tag, err error)
+	UpdateTag(id int, userId int, req models.RequestUpdateTag) (tag models.Tag, err error)
+	DeleteTag(id int, userId int) (err error)
+	GetTagReport(req models.RequestGetTagReport) (response models.ResponseTagReport, err error)
+	// Rules
+	CreateRule(userId int, req models.RequestCreateRule) (rule models.Rule, err error)
+	GetRules(userId int) (rules []models.Rule, err error)
+	GetRuleById(req models.RequestGetRuleById, userId int) (rule models.Rule, err error)
+	UpdateRule(id int, userId int, req models.RequestUpdateRule) (rule models.Rule, err error)
+	DeleteRule(id int, userId int) (err error)
+	ApplyRules(userId int, req models.RequestApplyRules) (response models.ResponseApplyRules, err error)
+	// Duplicates
+	GetDuplicates(req models.RequestGetDuplicates) (response models.ResponseDuplicateList, err error)
+	MergeDuplicate(userId int, req models.RequestMergeDuplicate) (response models.TransactionResponse, err error)
+	DismissDuplicate(userId int, req models.RequestDismissDuplicate) (dismissal models.DuplicateDismissal, err error)
+	// Reconciliations
+	UpdateTransactionStatus(id int, userId int, req models.RequestUpdateTransactionStatus) (response models.TransactionResponse, err error)
+	UnlockTransaction(id int, userId int) (response models.TransactionResponse, err error)
+	CreateReconciliation(userId int, req models.RequestCreateReconciliation) (response models.ResponseReconciliation, err error)
+	GetReconciliations(userId int) (reconciliations []models.Reconciliation, err error)
+	GetReconciliationById(req models.RequestGetReconciliationById, userId int) (response models.ResponseReconciliation, err error)
+	FinalizeReconciliation(id int, userId int) (response models.ResponseReconciliation, err error)
+	DeleteReconciliation(id int, userId int) (err error)
+	// Goals
+	CreateGoal(userId int, req models.RequestCreateGoal) (goal models.Goal, err error)
+	GetGoals(userId int) (goals []models.Goal, err error)
+	GetGoalById(req models.RequestGetGoalById, userId int) (goal models.Goal, err error)
+	UpdateGoal(id int, userId int, req models.RequestUpdateGoal) (goal models.Goal, err error)
+	DeleteGoal(id int, userId int) (err error)
+	GetGoalProgress(req models.RequestGetGoalById, userId int) (response models.ResponseGoalProgress, err error)
+	CreateGoalContribution(goalId int, userId int, req models.RequestCreateGoalContribution) (contribution models.GoalContribution, err error)
+	GetGoalContributions(goalId int, userId int) (contributions []models.GoalContribution, err error)
+	DeleteGoalContribution(req models.RequestGetGoalContribution, userId int) (err error)
+	// Debts
+	CreateDebt(userId int, req models.RequestCreateDebt) (debt models.Debt, err error)
+	GetDebts(userId int) (debts []models.Debt, err error)
+	GetDebtById(req models.RequestGetDebtById, userId int) (debt models.Debt, err error)
+	UpdateDebt(id int, userId int, req models.RequestUpdateDebt) (debt models.Debt, err error)
+	DeleteDebt(id int, userId int) (err error)
+	GetDebtSchedule(req models.RequestGetDebtById, userId int) (response models.ResponseDebtSchedule, err error)
+	GetDebtSummary(req models.RequestGetDebtById, userId int, upcomingLimit int) (response models.ResponseDebtSummary, err error)
+	CreateDebtPayment(debtId int, userId int, req models.RequestCreateDebtPayment) (payment models.DebtPayment, err error)
+	GetDebtPayments(debtId int, userId int) (payments []models.DebtPayment, err error)
+	DeleteDebtPayment(req models.RequestGetDebtPayment, userId int) (err error)
+	// Bills
+	CreateBill(userId int, req models.RequestCreateBill) (bill models.Bill, err error)
+	GetBills(userId int) (bills []models.Bill, err error)
+	GetBillById(req models.RequestGetBillById, userId int) (bill models.Bill, err error)
+	UpdateBill(id int, userId int, req models.RequestUpdateBill) (bill models.Bill, err error)
+	DeleteBill(id int, userId int) (err error)
+	GetBillOccurrences(req models.RequestGetBillOccurrences, userId int) (occurrences []models.BillOccurrence, err error)
+	GetUpcomingBills(userId int, days int) (occurrences []models.BillOccurrence, err error)
+	CreateBillPayment(billId int, userId int, req models.RequestCreateBillPayment) (payment models.BillPayment, err error)
+	DeleteBillPayment(req models.RequestGetBillPayment, userId int) (err error)
+	CreateCalendarToken(userId int) (token string, err error)
+	DeleteCalendarToken(userId int) (err error)
+	GetBillCalendar(token string) (calendar []byte, err error)
+	// Net worth
+	CreateAsset(userId int, req models.RequestCreateAsset) (asset models.ResponseAsset, err error)
+	GetAssets(userId int) (assets []models.ResponseAsset, err error)
+	GetAssetById(req models.RequestGetAssetById, userId int) (asset models.ResponseAsset, err error)
+	UpdateAsset(id int, userId int, req models.RequestUpdateAsset) (asset models.ResponseAsset, err error)
+	DeleteAsset(id int, userId int) (err error)
+	CreateAssetValuation(assetId int, userId int, req models.RequestCreateAssetValuation) (valuation models.AssetValuation, err error)
+	GetAssetValuations(assetId int, userId int) (valuations []models.AssetValuation, err error)
+	DeleteAssetValuation(req models.RequestGetAssetValuation, userId int) (err error)
+	GetNetWorth(userId int) (netWorth models.NetWorth, err error)
+	CreateNetWorthSnapshot(userId int) (snapshot models.NetWorthSnapshot, err error)
+	CreateScheduledNetWorthSnapshots() (err error)
+	GetNetWorthSeries(req models.RequestGetNetWorthSeries, userId int) (points []models.NetWorthPoint, err error)
+	// Investments
+	CreatePortfolio(userId int, req models.RequestCreatePortfolio) (portfolio models.Portfolio, err error)
+	GetPortfolios(userId int) (portfolios []models.Portfolio, err error)
+	GetPortfolioById(req models.RequestGetPortfolioById, userId int) (portfolio models.Portfolio, err error)
+	UpdatePortfolio(id int, userId int, req models.RequestUpdatePortfolio) (portfolio models.Portfolio, err error)
+	DeletePortfolio(id int, userId int) (err error)
+	GetPortfolioValuation(req models.RequestGetPortfolioValuation, userId int) (response models.ResponsePortfolioValuation, err error)
+	CreateHolding(portfolioId int, userId int, req models.RequestCreateHolding) (holding models.Holding, err error)
+	GetHoldings(portfolioId int, userId int) (holdings []models.Holding, err error)
+	DeleteHolding(req models.RequestGetHolding, userId int) (err error)
+	CreateInvestmentActivity(req models.RequestGetHolding, userId int, activityReq models.RequestCreateInvestmentActivity) (activity models.InvestmentActivity, err error)
+	GetInvestmentActivities(req models.RequestGetHolding, userId int) (activities []models.InvestmentActivity, err error)
+	DeleteInvestmentActivity(req models.RequestGetInvestmentActivity, userId int) (err error)
+	CreatePrice(userId int, req models.RequestCreatePrice) (price models.InstrumentPrice, err error)
+	GetPrices(userId int, req models.RequestGetPrices) (prices []models.InstrumentPrice, err error)
+	DeletePrice(req models.RequestGetPriceById, userId int) (err error)
+	ImportPrices(userId int, req models.RequestImportPrices) (response models.ResponseImportPrices, err error)
+	// Envelopes
+	GetEnvelopes(req models.RequestGetEnvelopes, userId int) (response models.ResponseEnvelopes, err error)
+	SetEnvelopeCategory(req models.RequestSetEnvelopeCategory, userId int) (envelope models.EnvelopeCategory, err error)
+	DeleteEnvelopeCategory(req models.RequestGetEnvelopeCategory, userId int) (err error)
+	AssignEnvelope(req models.RequestAssignEnvelope, userId int) (assignment models.EnvelopeAssignment, err error)
+	// Notifications
+	GetNotifications(req models.RequestGetNotifications) (response models.ResponseNotificationList, err error)
+	MarkNotificationRead(req models.RequestGetNotificationById, userId int) (notification models.Notification, err error)
+	MarkAllNotificationsRead(userId int) (err error)
+	GetNotificationSettings(userId int) (settings models.NotificationSettings, err error)
+	UpdateNotificationSettings(userId int, req models.RequestUpdateNotificationSettings) (settings models.NotificationSettings, err error)
+	CreateBillReminders() (err error)
+	HandleNotificationEvent(event models.DomainEvent) (err error)
+	// Webhooks
+	CreateWebhookSubscription(req models.RequestCreateWebhookSubscription) (response models.ResponseWebhookSubscription, err error)
+	GetWebhookSubscriptions() (subscriptions []models.WebhookSubscription, err error)
+	GetWebhookSubscriptionById(req models.RequestGetWebhookSubscriptionById) (subscription models.WebhookSubscription, err error)
+	UpdateWebhookSubscription(id int, req models.RequestUpdateWebhookSubscription) (response models.ResponseWebhookSubscription, err error)
+	DeleteWebhookSubscription(id int) (err error)
+	GetWebhookDeliveries(req models.RequestGetWebhookDeliveries) (response models.ResponseWebhookDeliveryList, err error)
+	RedeliverWebhook(req models.RequestGetWebhookDelivery) (delivery models.WebhookDelivery, err error)
+	ProcessWebhookDeliveries() (err error)
+	HandleWebhookEvent(event models.DomainEvent) (err error)
+	// Domain events
+	GetDomainEvents(req models.RequestGetDomainEvents) (response models.ResponseDomainEventList, err error)
+	GetEventSubscribers() (subscribers []models.EventSubscriber, err error)
+	ReplayEvents(req models.RequestReplayEvents) (subscriber models.EventSubscriber, err error)
+	// Stream tickets
+	CreateStreamTicket(userId int) (response models.ResponseStreamTicket, err error)
+	RedeemStreamTicket(ticket string) (user models.User, err error)
+	// Sync
+	PullSync(req models.RequestSync, userId int) (response models.ResponseSync, err error)
+	PushSync(req models.RequestPushSync, userId int) (response models.ResponsePushSync, err error)
+	ResolvePublicId(entity string, raw string, userId int) (id int, err error)
+	// GraphQL
+	GetUsersByIds(ids []int) (users []models.UserResponse, err error)
+	GetCategoriesByIds(ids []int) (categories []models.Category, err error)
+	// Attachments
+	UploadAttachment(transactionId int, userId int, req models.RequestUploadAttachment) (attachment models.Attachment, err error)
+	GetAttachments(transactionId int, userId int) (attachments []models.Attachment, err error)
+	GetAttachmentContent(req models.RequestGetAttachment, userId int) (response models.ResponseAttachmentContent, err error)
+	DeleteAttachment(transactionId int, attachmentId int, userId int) (err error)
 }
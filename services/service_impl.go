@@ -4,21 +4,28 @@ import (
 	"errors"
 	"go-crud-api/helper"
 	"go-crud-api/models"
+	"go-crud-api/notify"
 	"go-crud-api/repository"
+	"go-crud-api/storage"
 	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+var errAdminSignUp = errors.New("forbidden: only an admin can create another admin")
+
 type service struct {
 	Repository repository.Repository
 	Db         *gorm.DB
+	Storage    storage.Storage
+	Notifiers  map[string]notify.Channel
 }
 
-func NewService(repository repository.Repository, db *gorm.DB) Service {
-	return &service{Repository: repository, Db: db}
+func NewService(repository repository.Repository, db *gorm.DB, storage storage.Storage, notifiers map[string]notify.Channel) Service {
+	return &service{Repository: repository, Db: db, Storage: storage, Notifiers: notifiers}
 }
 
 func (s *service) GetUserById(req models.RequestGetUserById) (user models.User, err error) {
@@ -49,23 +56,34 @@ func (s *service) CreateUser(req models.RequestSignUp) (user models.User, err er
 	}
 
 	// Set default role to "user" if not provided
-	role := req.Role
-	if role == "" {
-		role = "user"
+	role, err := normalizeRole(req.Role)
+	if err != nil {
+		return
+	}
+
+	// Anyone can sign up, so only the first admin may sign up as one; later
+	// admins are created by an admin
+	if role == "admin" {
+		admins, errCount := s.Repository.CountUsersByRole(s.Db, "admin")
+		if errCount != nil {
+			err = errCount
+			return
+		}
+		if admins > 0 {
+			err = errAdminSignUp
+			return
+		}
 	}
 
 	user = models.User{
+		Uuid:     req.Uuid,
 		Name:     req.Name,
 		Username: req.Username,
 		Password: string(passwordHash),
 		Role:     role,
 	}
 
-	err = s.Repository.CreateUser(s.Db, user)
-	if err != nil {
-		return
-	}
-
+	user, err = s.createUser(user)
 	return
 }
 
@@ -92,60 +110,40 @@ func (s *service) Login(req models.RequestLogin) (response models.ResponseLogin,
 }
 
 func (s *service) CreateCategory(req models.RequestCreateCategory) (category models.Category, err error) {
-	// Validasi: Name tidak boleh kosong atau hanya spasi
-	if len(req.Name) < 1 || req.Name == "" {
-		err = errors.New("category name is required and cannot be empty")
-		return
-	}
-
-	// Trim spasi dan validasi ulang
-	trimmedName := req.Name
-	for i := 0; i < len(trimmedName); i++ {
-		if trimmedName[i] != ' ' {
-			break
-		}
-		if i == len(trimmedName)-1 {
-			err = errors.New("category name cannot contain only spaces")
-			return
-		}
-	}
-
-	hasNonSpace := false
-	for i := 0; i < len(req.Name); i++ {
-		if req.Name[i] != ' ' {
-			hasNonSpace = true
-			break
-		}
-	}
-	if !hasNonSpace {
-		err = errors.New("category name cannot contain only spaces")
-		return
-	}
-
-	// Validasi: Cek duplikat
-	var existingCategory models.Category
-	err = s.Db.Where("LOWER(name) = LOWER(?)", req.Name).First(&existingCategory).Error
-	if err == nil {
-		err = errors.New("category name already exists")
-		return
-	}
-	if err != gorm.ErrRecordNotFound {
+	// Validasi: Name tidak boleh kosong, hanya spasi, atau duplikat
+	err = s.validateCategoryName(req.Name, 0)
+	if err != nil {
 		return
 	}
 
 	category = models.Category{
 		Name: req.Name,
 	}
-	category, err = s.Repository.CreateCategory(s.Db, category)
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		category.Uuid, errTx = s.newPublicId(tx, &models.Category{}, req.Uuid)
+		if errTx != nil {
+			return
+		}
+		category, errTx = s.Repository.CreateCategory(tx, category)
+		if errTx != nil {
+			return
+		}
+		return s.recordCategoryEvent(tx, EventCategoryCreated, category)
+	})
 	return
 }
 
 func (s *service) GetCategories(req models.RequestGetCategories) (response models.ResponseCategoryList, err error) {
-	pagination := helper.SetPaginationFromQuery(req.Limit, req.Page)
+	pagination, err := helper.ParsePagination(req.RequestPagination, categorySortColumns, "created_at:asc")
+	if err != nil {
+		return
+	}
+
 	count, categories, err := s.Repository.GetCategories(s.Db, req.Name, pagination)
 	if err != nil {
 		return
 	}
+	categories, hasMore := helper.TrimCursorPage(categories, pagination)
 
 	response = models.ResponseCategoryList{
 		Count: count,
@@ -153,6 +151,13 @@ func (s *service) GetCategories(req models.RequestGetCategories) (response model
 		Limit: pagination.Limit,
 		Data:  categories,
 	}
+
+	if pagination.UseCursor && len(categories) > 0 {
+		first, last := categories[0], categories[len(categories)-1]
+		column := pagination.Sort.Column
+		response.NextCursor, response.PrevCursor = helper.CursorLinks(pagination, hasMore,
+			categorySortValue(first, column), first.Id, categorySortValue(last, column), last.Id)
+	}
 	return
 }
 
@@ -162,167 +167,219 @@ func (s *service) GetCategoryById(req models.RequestGetCategoryById) (category m
 }
 
 func (s *service) UpdateCategory(id int, req models.RequestUpdateCategory) (err error) {
-	// Validasi: Name tidak boleh kosong atau hanya spasi
-	if req.Name == "" {
-		err = errors.New("category name is required and cannot be empty")
+	// Validasi: Name tidak boleh kosong, hanya spasi, atau duplikat (selain category yang sedang di-update)
+	err = s.validateCategoryName(req.Name, id)
+	if err != nil {
 		return
 	}
 
-	hasNonSpace := false
-	for i := 0; i < len(req.Name); i++ {
-		if req.Name[i] != ' ' {
-			hasNonSpace = true
-			break
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		errTx = s.Repository.UpdateCategory(tx, id, req.Name)
+		if errTx != nil {
+			return
 		}
-	}
-	if !hasNonSpace {
-		err = errors.New("category name cannot contain only spaces")
+		_, errTx = s.recordCategoryUpdated(tx, id)
 		return
-	}
+	})
+	return
+}
 
-	// Validasi: Cek duplikat (selain category yang sedang di-update)
-	var existingCategory models.Category
-	err = s.Db.Where("LOWER(name) = LOWER(?) AND id != ?", req.Name, id).First(&existingCategory).Error
-	if err == nil {
-		err = errors.New("category name already exists")
+func (s *service) PatchCategory(id int, req models.RequestPatchCategory) (category models.Category, err error) {
+	category, err = s.Repository.GetCategoryById(s.Db, id)
+	if err != nil {
 		return
 	}
-	if err != gorm.ErrRecordNotFound {
-		return
+
+	if req.Name.Set {
+		if req.Name.Null {
+			err = errors.New("category name is required and cannot be empty")
+			return
+		}
+		err = s.validateCategoryName(req.Name.Value, id)
+		if err != nil {
+			return
+		}
+
+		err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+			errTx = s.Repository.UpdateCategory(tx, id, req.Name.Value)
+			if errTx != nil {
+				return
+			}
+			_, errTx = s.recordCategoryUpdated(tx, id)
+			return
+		})
+		if err != nil {
+			return
+		}
 	}
 
-	err = s.Repository.UpdateCategory(s.Db, id, req.Name)
+	category, err = s.Repository.GetCategoryById(s.Db, id)
 	return
 }
 
 func (s *service) DeleteCategory(id int) (err error) {
-	err = s.Repository.DeleteCategory(s.Db, id)
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		category, errTx := s.Repository.GetCategoryById(tx, id)
+		if errTx == gorm.ErrRecordNotFound {
+			// Nothing to delete
+			return nil
+		}
+		if errTx != nil {
+			return
+		}
+
+		errTx = s.Repository.DeleteCategory(tx, id)
+		if errTx != nil {
+			return
+		}
+		return s.recordEvent(tx, EventCategoryDeleted, 0, id, deletedEntity{Id: id, Uuid: category.Uuid})
+	})
 	return
 }
 
 func (s *service) CreateTransaction(userId int, req models.RequestCreateTransaction) (response models.TransactionResponse, err error) {
+	var transaction models.Transaction
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		transaction, errTx = s.createTransaction(tx, userId, req)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	response = toTransactionResponse(transaction)
+	return
+}
+
+// createTransaction validates and inserts a transaction through db, so
+// callers already inside a DB transaction go through the same path.
+func (s *service) createTransaction(db *gorm.DB, userId int, req models.RequestCreateTransaction) (transaction models.Transaction, err error) {
 	// Validasi: Amount tidak boleh 0 atau negatif
-	if req.Amount <= 0 {
-		err = errors.New("amount must be greater than 0")
+	err = validateAmount(req.Amount)
+	if err != nil {
 		return
 	}
 
 	// Validasi: Type tidak boleh kosong atau hanya spasi
-	if req.Type == "" {
-		err = errors.New("type is required and cannot be empty")
+	err = validateTransactionType(req.Type)
+	if err != nil {
 		return
 	}
 
-	hasNonSpace := false
-	for i := 0; i < len(req.Type); i++ {
-		if req.Type[i] != ' ' {
-			hasNonSpace = true
-			break
-		}
+	err = validateNote(req.Note)
+	if err != nil {
+		return
 	}
-	if !hasNonSpace {
-		err = errors.New("type cannot contain only spaces")
+
+	err = validatePayee(req.Payee)
+	if err != nil {
 		return
 	}
 
-	// Validasi: CategoryId wajib diisi
-	if req.CategoryId <= 0 {
-		err = errors.New("category_id is required")
+	occurredAt, err := parseOccurredAt(req.OccurredAt)
+	if err != nil {
 		return
 	}
 
-	// Validasi: Cek apakah category exists
-	_, err = s.Repository.GetCategoryById(s.Db, req.CategoryId)
+	tagNames, err := normalizeTagNames(req.Tags)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			err = errors.New("category not found")
-		}
 		return
 	}
 
-	transaction := models.Transaction{
-		UserId:     userId,
+	// The user's rules may fix the category, payee and tags before saving
+	target, err := s.applyUserRules(db, userId, ruleTarget{
 		Amount:     req.Amount,
 		Type:       req.Type,
+		Note:       req.Note,
+		Payee:      req.Payee,
 		CategoryId: req.CategoryId,
+		Tags:       tagNames,
+	})
+	if err != nil {
+		return
 	}
-	transaction, err = s.Repository.CreateTransaction(s.Db, transaction)
+
+	// Validasi: CategoryId wajib diisi (atau diisi rule) dan category harus ada
+	target.CategoryId = newTransactionCategory(req, target)
+	err = s.validateCategoryId(db, target.CategoryId)
 	if err != nil {
 		return
 	}
+	tagNames = target.Tags
 
-	response = models.TransactionResponse{
-		Id: transaction.Id,
-		User: models.UserSimpleResponse{
-			Id:   transaction.User.Id,
-			Name: transaction.User.Name,
-		},
-		Amount: transaction.Amount,
-		Type:   transaction.Type,
-		Category: models.CategorySimpleResponse{
-			Id:   transaction.Category.Id,
-			Name: transaction.Category.Name,
-		},
-		CreatedAt: transaction.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: transaction.UpdatedAt.Format("2006-01-02 15:04:05"),
+	transaction = models.Transaction{
+		UserId:        userId,
+		Amount:        req.Amount,
+		Type:          req.Type,
+		CategoryId:    target.CategoryId,
+		Note:          req.Note,
+		Payee:         target.Payee,
+		OccurredAt:    occurredAt,
+		BankReference: req.BankReference,
+	}
+	transaction.Uuid, err = s.newPublicId(db, &models.Transaction{}, req.Uuid)
+	if err != nil {
+		return
+	}
+	transaction, err = s.Repository.CreateTransaction(db, transaction)
+	if err != nil {
+		return
 	}
-	return
-}
 
-func (s *service) GetTransactions(req models.RequestGetTransactions) (response models.ResponseTransactionList, err error) {
-	pagination := helper.SetPaginationFromQuery(req.Limit, req.Page)
-	var categoryId int
-	if req.CategoryId != "" {
-		categoryId, err = strconv.Atoi(req.CategoryId)
+	if len(tagNames) > 0 {
+		err = s.setTransactionTags(db, transaction.Id, userId, tagNames)
 		if err != nil {
 			return
 		}
 	}
 
+	transaction, err = s.Repository.GetTransactionById(db, transaction.Id)
+	if err != nil {
+		return
+	}
+
+	err = s.recordTransactionEvent(db, EventTransactionCreated, transaction)
+	return
+}
+
+func (s *service) GetTransactions(req models.RequestGetTransactions) (response models.ResponseTransactionList, err error) {
+	pagination, err := helper.ParsePagination(req.RequestPagination, transactionSortColumns, "occurred_at:desc")
+	if err != nil {
+		return
+	}
+
+	filter, err := parseTransactionFilter(req)
+	if err != nil {
+		return
+	}
+
 	// Set default date range if not provided (27th of previous month to 26th of current month)
 	startDate := req.StartDate
 	endDate := req.EndDate
 	if startDate == "" || endDate == "" {
-		now := time.Now()
-		// Calculate start date: 27th of previous month
-		if now.Day() >= 27 {
-			startDate = time.Date(now.Year(), now.Month(), 27, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
-		} else {
-			prevMonth := now.AddDate(0, -1, 0)
-			startDate = time.Date(prevMonth.Year(), prevMonth.Month(), 27, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
-		}
-		// Calculate end date: 26th of current month
-		if now.Day() >= 27 {
-			nextMonth := now.AddDate(0, 1, 0)
-			endDate = time.Date(nextMonth.Year(), nextMonth.Month(), 26, 23, 59, 59, 0, now.Location()).Format("2006-01-02")
-		} else {
-			endDate = time.Date(now.Year(), now.Month(), 26, 23, 59, 59, 0, now.Location()).Format("2006-01-02")
-		}
+		startDate, endDate = currentCycle(time.Now())
+	}
+
+	filter.StartDate, filter.StartTime, err = parseDateBound(startDate, "start_date")
+	if err != nil {
+		return
+	}
+	filter.EndDate, filter.EndTime, err = parseDateBound(endDate, "end_date")
+	if err != nil {
+		return
 	}
 
-	count, transactions, err := s.Repository.GetTransactions(s.Db, req.UserId, categoryId, req.Type, startDate, endDate, pagination)
+	count, transactions, err := s.Repository.GetTransactions(s.Db, filter, pagination)
 	if err != nil {
 		return
 	}
 
+	transactions, hasMore := helper.TrimCursorPage(transactions, pagination)
+
 	// Transform to response format
 	transactionResponses := []models.TransactionResponse{}
 	for _, transaction := range transactions {
-		transactionResponses = append(transactionResponses, models.TransactionResponse{
-			Id: transaction.Id,
-			User: models.UserSimpleResponse{
-				Id:   transaction.User.Id,
-				Name: transaction.User.Name,
-			},
-			Amount: transaction.Amount,
-			Type:   transaction.Type,
-			Category: models.CategorySimpleResponse{
-				Id:   transaction.Category.Id,
-				Name: transaction.Category.Name,
-			},
-			CreatedAt: transaction.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt: transaction.UpdatedAt.Format("2006-01-02 15:04:05"),
-		})
+		transactionResponses = append(transactionResponses, toTransactionResponse(transaction))
 	}
 
 	response = models.ResponseTransactionList{
@@ -331,6 +388,107 @@ func (s *service) GetTransactions(req models.RequestGetTransactions) (response m
 		Limit: pagination.Limit,
 		Data:  transactionResponses,
 	}
+
+	if pagination.UseCursor && len(transactions) > 0 {
+		first, last := transactions[0], transactions[len(transactions)-1]
+		column := pagination.Sort.Column
+		response.NextCursor, response.PrevCursor = helper.CursorLinks(pagination, hasMore,
+			transactionSortValue(first, column), first.Id, transactionSortValue(last, column), last.Id)
+	}
+	return
+}
+
+// parseTransactionFilter converts the raw query values into a filter. The
+// date bounds are handled separately because they have defaults.
+func parseTransactionFilter(req models.RequestGetTransactions) (filter models.QueryTransactionFilter, err error) {
+	filter.UserId = req.UserId
+	filter.Search = strings.TrimSpace(req.Search)
+	filter.Types = splitQueryValues(req.Types)
+	filter.ExcludeTypes = splitQueryValues(req.ExcludeTypes)
+	filter.Tags = splitQueryValues(req.Tags)
+	filter.ExcludeTags = splitQueryValues(req.ExcludeTags)
+	filter.Statuses = splitQueryValues(req.Statuses)
+
+	filter.CategoryIds, err = parseIdList(req.CategoryIds, "category_id")
+	if err != nil {
+		return
+	}
+	filter.ExcludeCategoryIds, err = parseIdList(req.ExcludeCategoryIds, "exclude_category_id")
+	if err != nil {
+		return
+	}
+
+	filter.MinAmount, err = parseAmountBound(req.MinAmount, "min_amount")
+	if err != nil {
+		return
+	}
+	filter.MaxAmount, err = parseAmountBound(req.MaxAmount, "max_amount")
+	if err != nil {
+		return
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		err = errors.New("invalid amount range: min_amount is greater than max_amount")
+		return
+	}
+	return
+}
+
+// splitQueryValues accepts both repeated params (?type=a&type=b) and comma
+// separated values (?type=a,b).
+func splitQueryValues(values []string) (result []string) {
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return
+}
+
+func parseIdList(values []string, field string) (ids []int, err error) {
+	for _, value := range splitQueryValues(values) {
+		id, errAtoi := strconv.Atoi(value)
+		if errAtoi != nil || id <= 0 {
+			err = errors.New("invalid " + field + ": " + value)
+			return
+		}
+		ids = append(ids, id)
+	}
+	return
+}
+
+func parseAmountBound(value string, field string) (amount *float64, err error) {
+	if value == "" {
+		return
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		err = errors.New("invalid " + field + ": must be a non-negative number")
+		return
+	}
+	amount = &parsed
+	return
+}
+
+// parseDateBound accepts a plain date, compared by day, or an RFC 3339
+// datetime, compared exactly.
+func parseDateBound(value string, field string) (date string, datetime *time.Time, err error) {
+	if value == "" {
+		return
+	}
+	if _, errDate := time.Parse("2006-01-02", value); errDate == nil {
+		date = value
+		return
+	}
+	// An unescaped "+07:00" offset arrives as " 07:00" in the query string
+	parsed, err := time.Parse(time.RFC3339, strings.Replace(value, " ", "+", 1))
+	if err != nil {
+		err = errors.New("invalid " + field + ": use YYYY-MM-DD or RFC 3339 datetime")
+		return
+	}
+	datetime = &parsed
 	return
 }
 
@@ -346,46 +504,20 @@ func (s *service) GetTransactionById(req models.RequestGetTransactionById, userI
 		return
 	}
 
-	response = models.TransactionResponse{
-		Id: transaction.Id,
-		User: models.UserSimpleResponse{
-			Id:   transaction.User.Id,
-			Name: transaction.User.Name,
-		},
-		Amount: transaction.Amount,
-		Type:   transaction.Type,
-		Category: models.CategorySimpleResponse{
-			Id:   transaction.Category.Id,
-			Name: transaction.Category.Name,
-		},
-		CreatedAt: transaction.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: transaction.UpdatedAt.Format("2006-01-02 15:04:05"),
-	}
+	response = toTransactionResponse(transaction)
 	return
 }
 
 func (s *service) UpdateTransaction(id int, userId int, req models.RequestUpdateTransaction) (response models.TransactionResponse, err error) {
 	// Validasi: Amount tidak boleh 0 atau negatif
-	if req.Amount <= 0 {
-		err = errors.New("amount must be greater than 0")
+	err = validateAmount(req.Amount)
+	if err != nil {
 		return
 	}
 
 	// Validasi: Type tidak boleh kosong atau hanya spasi
-	if req.Type == "" {
-		err = errors.New("type is required and cannot be empty")
-		return
-	}
-
-	hasNonSpace := false
-	for i := 0; i < len(req.Type); i++ {
-		if req.Type[i] != ' ' {
-			hasNonSpace = true
-			break
-		}
-	}
-	if !hasNonSpace {
-		err = errors.New("type cannot contain only spaces")
+	err = validateTransactionType(req.Type)
+	if err != nil {
 		return
 	}
 
@@ -395,10 +527,22 @@ func (s *service) UpdateTransaction(id int, userId int, req models.RequestUpdate
 		return
 	}
 
-	categoryId, err := strconv.Atoi(req.CategoryId)
-	if err != nil {
-		err = errors.New("invalid category_id format")
-		return
+	// category_id is a string here, so its uuid is resolved in place
+	var categoryId int
+	if IsUuid(req.CategoryId) {
+		categoryId, err = s.ResolvePublicId(PublicIdCategory, req.CategoryId, userId)
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New("category not found")
+		}
+		if err != nil {
+			return
+		}
+	} else {
+		categoryId, err = strconv.Atoi(req.CategoryId)
+		if err != nil {
+			err = errors.New("invalid category_id format")
+			return
+		}
 	}
 
 	if categoryId <= 0 {
@@ -407,22 +551,23 @@ func (s *service) UpdateTransaction(id int, userId int, req models.RequestUpdate
 	}
 
 	// Validasi: Cek apakah category exists
-	_, err = s.Repository.GetCategoryById(s.Db, categoryId)
+	err = s.validateCategoryId(s.Db, categoryId)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			err = errors.New("category not found")
-		}
 		return
 	}
 
-	// Check if transaction exists and belongs to user
-	existingTransaction, err := s.Repository.GetTransactionById(s.Db, id)
+	err = validateNote(req.Note)
 	if err != nil {
 		return
 	}
 
-	if existingTransaction.UserId != userId {
-		err = errors.New("unauthorized: transaction does not belong to this user")
+	err = validatePayee(req.Payee)
+	if err != nil {
+		return
+	}
+
+	tagNames, err := normalizeTagNames(req.Tags)
+	if err != nil {
 		return
 	}
 
@@ -431,40 +576,235 @@ func (s *service) UpdateTransaction(id int, userId int, req models.RequestUpdate
 		"amount":      req.Amount,
 		"type":        req.Type,
 		"category_id": categoryId,
+		"note":        req.Note,
+		"payee":       req.Payee,
+	}
+
+	if req.OccurredAt != "" {
+		occurredAt, errParse := parseOccurredAt(req.OccurredAt)
+		if errParse != nil {
+			err = errParse
+			return
+		}
+		updateData["occurred_at"] = occurredAt
 	}
 
-	err = s.Db.Model(&models.Transaction{}).Where("id = ?", id).Updates(updateData).Error
+	var updatedTransaction models.Transaction
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		// Check if transaction exists, belongs to user and is not locked
+		errTx = s.checkTransactionEditable(tx, id, userId)
+		if errTx != nil {
+			return
+		}
+
+		errTx = s.Repository.UpdateTransactionFields(tx, id, updateData)
+		if errTx != nil {
+			return
+		}
+		if req.Tags != nil {
+			errTx = s.setTransactionTags(tx, id, userId, tagNames)
+			if errTx != nil {
+				return
+			}
+		}
+
+		// Get updated transaction with relations
+		updatedTransaction, errTx = s.recordTransactionUpdated(tx, id)
+		return
+	})
 	if err != nil {
 		return
 	}
 
-	// Get updated transaction with relations
-	updatedTransaction, err := s.Repository.GetTransactionById(s.Db, id)
+	response = toTransactionResponse(updatedTransaction)
+	return
+}
+
+func (s *service) PatchTransaction(id int, userId int, req models.RequestPatchTransaction) (response models.TransactionResponse, err error) {
+	var transaction models.Transaction
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		transaction, errTx = s.patchTransaction(tx, id, userId, req)
+		return
+	})
 	if err != nil {
 		return
 	}
 
-	response = models.TransactionResponse{
-		Id: updatedTransaction.Id,
-		User: models.UserSimpleResponse{
-			Id:   updatedTransaction.User.Id,
-			Name: updatedTransaction.User.Name,
-		},
-		Amount: updatedTransaction.Amount,
-		Type:   updatedTransaction.Type,
-		Category: models.CategorySimpleResponse{
-			Id:   updatedTransaction.Category.Id,
-			Name: updatedTransaction.Category.Name,
-		},
-		CreatedAt: updatedTransaction.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: updatedTransaction.UpdatedAt.Format("2006-01-02 15:04:05"),
+	response = toTransactionResponse(transaction)
+	return
+}
+
+func (s *service) patchTransaction(db *gorm.DB, id int, userId int, req models.RequestPatchTransaction) (transaction models.Transaction, err error) {
+	// Check if transaction exists, belongs to user and is not locked
+	err = s.checkTransactionEditable(db, id, userId)
+	if err != nil {
+		return
+	}
+
+	// Only fields present in the patch are validated and updated
+	updateData := map[string]interface{}{}
+
+	if req.Amount.Set {
+		if req.Amount.Null {
+			err = errors.New("amount cannot be null")
+			return
+		}
+		err = validateAmount(req.Amount.Value)
+		if err != nil {
+			return
+		}
+		updateData["amount"] = req.Amount.Value
+	}
+
+	if req.Type.Set {
+		if req.Type.Null {
+			err = errors.New("type cannot be null")
+			return
+		}
+		err = validateTransactionType(req.Type.Value)
+		if err != nil {
+			return
+		}
+		updateData["type"] = req.Type.Value
+	}
+
+	if req.CategoryId.Set {
+		if req.CategoryId.Null {
+			err = errors.New("category_id cannot be null")
+			return
+		}
+		err = s.validateCategoryId(db, req.CategoryId.Value)
+		if err != nil {
+			return
+		}
+		updateData["category_id"] = req.CategoryId.Value
+	}
+
+	// Note and payee are optional, so null clears them
+	if req.Note.Set {
+		err = validateNote(req.Note.Value)
+		if err != nil {
+			return
+		}
+		updateData["note"] = req.Note.Value
 	}
+
+	if req.Payee.Set {
+		err = validatePayee(req.Payee.Value)
+		if err != nil {
+			return
+		}
+		updateData["payee"] = req.Payee.Value
+	}
+
+	if req.OccurredAt.Set {
+		if req.OccurredAt.Null || req.OccurredAt.Value == "" {
+			err = errors.New("occurred_at cannot be null")
+			return
+		}
+		occurredAt, errParse := parseOccurredAt(req.OccurredAt.Value)
+		if errParse != nil {
+			err = errParse
+			return
+		}
+		updateData["occurred_at"] = occurredAt
+	}
+
+	// Tags are optional, so null clears them
+	var tagNames []string
+	if req.Tags.Set {
+		tagNames, err = normalizeTagNames(req.Tags.Value)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(updateData) > 0 {
+		err = s.Repository.UpdateTransactionFields(db, id, updateData)
+		if err != nil {
+			return
+		}
+	}
+
+	if req.Tags.Set {
+		err = s.setTransactionTags(db, id, userId, tagNames)
+		if err != nil {
+			return
+		}
+	}
+
+	transaction, err = s.recordTransactionUpdated(db, id)
 	return
 }
 
 func (s *service) DeleteTransaction(id int, userId int) (err error) {
-	// Check if transaction exists and belongs to user
-	transaction, err := s.Repository.GetTransactionById(s.Db, id)
+	var attachments []models.Attachment
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		attachments, errTx = s.deleteTransaction(tx, id, userId)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	s.removeStoredFiles(attachments)
+	return
+}
+
+// deleteTransaction removes the transaction and its attachment rows. The
+// attachment files are returned so the caller can remove them once the DB
+// transaction has committed.
+func (s *service) deleteTransaction(db *gorm.DB, id int, userId int) (attachments []models.Attachment, err error) {
+	// Check if transaction exists, belongs to user and is not locked
+	err = s.checkTransactionEditable(db, id, userId)
+	if err != nil {
+		return
+	}
+	transaction, err := s.Repository.GetTransactionById(db, id)
+	if err != nil {
+		return
+	}
+
+	attachments, err = s.Repository.GetAttachments(db, id)
+	if err != nil {
+		return
+	}
+
+	err = s.Repository.DeleteAttachmentsByTransaction(db, id)
+	if err != nil {
+		return
+	}
+
+	err = s.Repository.DeleteTransaction(db, id)
+	if err != nil {
+		return
+	}
+
+	err = s.recordEvent(db, EventTransactionDeleted, userId, id, deletedEntity{Id: id, Uuid: transaction.Uuid, UserId: userId})
+	return
+}
+
+// checkTransactionOwner returns an error when the transaction does not exist
+// or is not owned by userId.
+func (s *service) checkTransactionOwner(db *gorm.DB, id int, userId int) (err error) {
+	transaction, err := s.Repository.GetTransactionById(db, id)
+	if err != nil {
+		return
+	}
+
+	if transaction.UserId != userId {
+		err = errors.New("unauthorized: transaction does not belong to this user")
+		return
+	}
+	return
+}
+
+// checkTransactionEditable is checkTransactionOwner plus the reconciliation
+// lock: reconciled transactions cannot change until they are unlocked.
+// The row is read FOR UPDATE, so inside a DB transaction a finalize cannot
+// reconcile it between the check and the write.
+func (s *service) checkTransactionEditable(db *gorm.DB, id int, userId int) (err error) {
+	transaction, err := s.Repository.LockTransactionById(db, id)
 	if err != nil {
 		return
 	}
@@ -474,30 +814,56 @@ func (s *service) DeleteTransaction(id int, userId int) (err error) {
 		return
 	}
 
-	err = s.Repository.DeleteTransaction(s.Db, id)
+	if transaction.Status == TransactionReconciled {
+		err = errTransactionLocked
+		return
+	}
 	return
 }
 
+func toTagSimpleResponses(tags []models.Tag) []models.TagSimpleResponse {
+	responses := []models.TagSimpleResponse{}
+	for _, tag := range tags {
+		responses = append(responses, models.TagSimpleResponse{Id: tag.Id, Name: tag.Name})
+	}
+	return responses
+}
+
+func toTransactionResponse(transaction models.Transaction) models.TransactionResponse {
+	return models.TransactionResponse{
+		Id:   transaction.Id,
+		Uuid: transaction.Uuid,
+		User: models.UserSimpleResponse{
+			Id:   transaction.User.Id,
+			Uuid: transaction.User.Uuid,
+			Name: transaction.User.Name,
+		},
+		Amount: transaction.Amount,
+		Type:   transaction.Type,
+		Category: models.CategorySimpleResponse{
+			Id:   transaction.Category.Id,
+			Uuid: transaction.Category.Uuid,
+			Name: transaction.Category.Name,
+		},
+		Note:             transaction.Note,
+		Payee:            transaction.Payee,
+		OccurredAt:       transaction.OccurredAt.Format("2006-01-02 15:04:05"),
+		Tags:             toTagSimpleResponses(transaction.Tags),
+		Status:           transaction.Status,
+		ReconciliationId: transaction.ReconciliationId,
+		BankReference:    transaction.BankReference,
+		CreatedAt:        transaction.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:        transaction.UpdatedAt.Format("2006-01-02 15:04:05"),
+		Version:          transaction.UpdatedAt.Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	}
+}
+
 func (s *service) GetBalance(req models.RequestGetBalance) (response models.ResponseBalance, err error) {
 	// Set default date range if not provided (27th of previous month to 26th of current month)
 	startDate := req.StartDate
 	endDate := req.EndDate
 	if startDate == "" || endDate == "" {
-		now := time.Now()
-		// Calculate start date: 27th of previous month
-		if now.Day() >= 27 {
-			startDate = time.Date(now.Year(), now.Month(), 27, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
-		} else {
-			prevMonth := now.AddDate(0, -1, 0)
-			startDate = time.Date(prevMonth.Year(), prevMonth.Month(), 27, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
-		}
-		// Calculate end date: 26th of current month
-		if now.Day() >= 27 {
-			nextMonth := now.AddDate(0, 1, 0)
-			endDate = time.Date(nextMonth.Year(), nextMonth.Month(), 26, 23, 59, 59, 0, now.Location()).Format("2006-01-02")
-		} else {
-			endDate = time.Date(now.Year(), now.Month(), 26, 23, 59, 59, 0, now.Location()).Format("2006-01-02")
-		}
+		startDate, endDate = currentCycle(time.Now())
 	}
 
 	totalIncome, totalExpense, err := s.Repository.GetBalanceByDateRange(s.Db, req.UserId, startDate, endDate)
@@ -519,17 +885,22 @@ func (s *service) GetBalance(req models.RequestGetBalance) (response models.Resp
 
 // Admin user management methods
 func (s *service) GetAllUsers(req models.RequestGetAllUsers) (response models.ResponseUserList, err error) {
-	pagination := helper.SetPaginationFromQuery(req.Limit, req.Page)
+	pagination, err := helper.ParsePagination(req.RequestPagination, userSortColumns, "created_at:desc")
+	if err != nil {
+		return
+	}
 
 	count, users, err := s.Repository.GetAllUsers(s.Db, pagination)
 	if err != nil {
 		return
 	}
+	users, hasMore := helper.TrimCursorPage(users, pagination)
 
 	userResponses := []models.UserResponse{}
 	for _, user := range users {
 		userResponses = append(userResponses, models.UserResponse{
 			Id:       user.Id,
+			Uuid:     user.Uuid,
 			Name:     user.Name,
 			Username: user.Username,
 			Role:     user.Role,
@@ -543,22 +914,26 @@ func (s *service) GetAllUsers(req models.RequestGetAllUsers) (response models.Re
 		Data:  userResponses,
 	}
 
+	if pagination.UseCursor && len(users) > 0 {
+		first, last := users[0], users[len(users)-1]
+		column := pagination.Sort.Column
+		response.NextCursor, response.PrevCursor = helper.CursorLinks(pagination, hasMore,
+			userSortValue(first, column), first.Id, userSortValue(last, column), last.Id)
+	}
+
 	return
 }
 
 func (s *service) AdminCreateUser(req models.RequestCreateUser) (user models.User, err error) {
-	if len(req.Name) < 1 || len(req.Username) < 1 || len(req.Password) < 1 {
-		err = errors.New("invalid data requested")
-		return
-	}
-
-	existingUser, err := s.Repository.FindUserByUsername(s.Db, req.Username)
-	if err != nil && err != gorm.ErrRecordNotFound {
-		return
+	for _, value := range []string{req.Name, req.Username, req.Password} {
+		err = validateUserField(value)
+		if err != nil {
+			return
+		}
 	}
 
-	if existingUser.Id != 0 {
-		err = errors.New("username already used")
+	err = s.validateUsername(req.Username, 0)
+	if err != nil {
 		return
 	}
 
@@ -568,23 +943,41 @@ func (s *service) AdminCreateUser(req models.RequestCreateUser) (user models.Use
 	}
 
 	// Set default role to "user" if not provided
-	role := req.Role
-	if role == "" {
-		role = "user"
+	role, err := normalizeRole(req.Role)
+	if err != nil {
+		return
 	}
 
 	user = models.User{
+		Uuid:     req.Uuid,
 		Name:     req.Name,
 		Username: req.Username,
 		Password: string(passwordHash),
 		Role:     role,
 	}
 
-	err = s.Repository.CreateUser(s.Db, user)
-	if err != nil {
-		return
-	}
+	user, err = s.createUser(user)
+	return
+}
 
+// createUser inserts the user and records user.created with it.
+func (s *service) createUser(user models.User) (created models.User, err error) {
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		user.Uuid, errTx = s.newPublicId(tx, &models.User{}, user.Uuid)
+		if errTx != nil {
+			return
+		}
+		errTx = s.Repository.CreateUser(tx, user)
+		if errTx != nil {
+			return
+		}
+
+		created, errTx = s.Repository.FindUserByUsername(tx, user.Username)
+		if errTx != nil {
+			return
+		}
+		return s.recordUserEvent(tx, EventUserCreated, created)
+	})
 	return
 }
 
@@ -627,16 +1020,100 @@ func (s *service) AdminUpdateUser(id int, req models.RequestUpdateUser) (user mo
 	}
 
 	if req.Role != "" {
-		updateData.Role = req.Role
+		updateData.Role, err = normalizeRole(req.Role)
+		if err != nil {
+			return
+		}
 	}
 
-	err = s.Repository.UpdateUser(s.Db, id, updateData)
+	before := user
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		errTx = s.Repository.UpdateUser(tx, id, updateData)
+		if errTx != nil {
+			return
+		}
+
+		// Get updated user
+		user, errTx = s.recordUserUpdated(tx, before)
+		return
+	})
+	return
+}
+
+func (s *service) AdminPatchUser(id int, req models.RequestPatchUser) (user models.User, err error) {
+	// Check if user exists
+	user, err = s.Repository.FindUserById(s.Db, id)
 	if err != nil {
 		return
 	}
 
-	// Get updated user
-	user, err = s.Repository.FindUserById(s.Db, id)
+	// Only fields present in the patch are validated and updated. Name,
+	// username and password are required on create so they cannot be nulled;
+	// a null role falls back to the default "user".
+	updateData := map[string]interface{}{}
+
+	if req.Name.Set {
+		if req.Name.Null {
+			err = errors.New("name cannot be null")
+			return
+		}
+		err = validateUserField(req.Name.Value)
+		if err != nil {
+			return
+		}
+		updateData["name"] = req.Name.Value
+	}
+
+	if req.Username.Set {
+		if req.Username.Null {
+			err = errors.New("username cannot be null")
+			return
+		}
+		err = s.validateUsername(req.Username.Value, id)
+		if err != nil {
+			return
+		}
+		updateData["username"] = req.Username.Value
+	}
+
+	if req.Password.Set {
+		if req.Password.Null {
+			err = errors.New("password cannot be null")
+			return
+		}
+		err = validateUserField(req.Password.Value)
+		if err != nil {
+			return
+		}
+		passwordHash, errHash := bcrypt.GenerateFromPassword([]byte(req.Password.Value), bcrypt.MinCost)
+		if errHash != nil {
+			err = errHash
+			return
+		}
+		updateData["password"] = string(passwordHash)
+	}
+
+	if req.Role.Set {
+		role, errRole := normalizeRole(req.Role.Value)
+		if errRole != nil {
+			err = errRole
+			return
+		}
+		updateData["role"] = role
+	}
+
+	before := user
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		if len(updateData) > 0 {
+			errTx = s.Repository.UpdateUserFields(tx, id, updateData)
+			if errTx != nil {
+				return
+			}
+		}
+
+		user, errTx = s.recordUserUpdated(tx, before)
+		return
+	})
 	return
 }
 
@@ -652,6 +1129,12 @@ func (s *service) AdminDeleteUser(id int) (err error) {
 		return
 	}
 
-	err = s.Repository.DeleteUser(s.Db, id)
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		errTx := s.Repository.DeleteUser(tx, id)
+		if errTx != nil {
+			return errTx
+		}
+		return s.recordUserEvent(tx, EventUserDeleted, user)
+	})
 	return
 }
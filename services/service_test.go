@@ -0,0 +1,68 @@
+package services
+
+import (
+	"go-crud-api/models"
+	"go-crud-api/repository"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestService returns a service over an in-memory SQLite database with
+// the tables of the app. SQLite ignores the FOR UPDATE of locking reads, and
+// one connection keeps every query on the same database.
+func newTestService(t *testing.T) *service {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDb, err := db.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDb.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDb.Close() })
+
+	err = db.AutoMigrate(&models.User{}, &models.Category{}, &models.Tag{}, &models.Transaction{}, &models.Attachment{}, &models.Rule{}, &models.DuplicateDismissal{}, &models.Reconciliation{}, &models.Goal{}, &models.GoalContribution{}, &models.Debt{}, &models.DebtPayment{}, &models.Bill{}, &models.BillPayment{}, &models.CalendarFeed{}, &models.Asset{}, &models.AssetValuation{}, &models.NetWorthSnapshot{}, &models.Portfolio{}, &models.Holding{}, &models.InvestmentActivity{}, &models.InstrumentPrice{}, &models.EnvelopeCategory{}, &models.EnvelopeAssignment{}, &models.Notification{}, &models.NotificationSettings{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.DomainEvent{}, &models.EventOffset{}, &models.StreamTicket{})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	// Two users sharing one category
+	for _, user := range []models.User{
+		{Id: 1, Uuid: newUuid(), Name: "Budi", Username: "budi", Role: "user"},
+		{Id: 2, Uuid: newUuid(), Name: "Sari", Username: "sari", Role: "user"},
+	} {
+		if err = db.Create(&user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	if err = db.Create(&models.Category{Id: 1, Uuid: newUuid(), Name: "Makan"}).Error; err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+
+	return &service{Repository: repository.NewRepository(), Db: db}
+}
+
+// createTestTransaction inserts an expense of the user, in category 1 unless
+// another one is set.
+func createTestTransaction(t *testing.T, s *service, transaction models.Transaction) models.Transaction {
+	t.Helper()
+	transaction.Uuid = newUuid()
+	if transaction.CategoryId == 0 {
+		transaction.CategoryId = 1
+	}
+	if transaction.Type == "" {
+		transaction.Type = "expense"
+	}
+	if transaction.Status == "" {
+		transaction.Status = TransactionUncleared
+	}
+	if err := s.Db.Create(&transaction).Error; err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+	return transaction
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go-crud-api/models"
+	"time"
+)
+
+// streamTicketTtl is how long a stream ticket can be used. It is only
+// meant to cover opening the connection right after it was issued.
+const streamTicketTtl = 30 * time.Second
+
+// CreateStreamTicket issues a single-use ticket for opening a stream with
+// ?ticket= instead of the JWT, which would end up in access logs.
+func (s *service) CreateStreamTicket(userId int) (response models.ResponseStreamTicket, err error) {
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return
+	}
+	ticket := hex.EncodeToString(secret)
+	now := time.Now()
+
+	err = s.Repository.DeleteExpiredStreamTickets(s.Db, now)
+	if err != nil {
+		return
+	}
+
+	err = s.Repository.CreateStreamTicket(s.Db, models.StreamTicket{
+		UserId:    userId,
+		TokenHash: hashStreamTicket(ticket),
+		ExpiresAt: now.Add(streamTicketTtl),
+	})
+	if err != nil {
+		return
+	}
+
+	response.Ticket = ticket
+	response.ExpiresAt = now.Add(streamTicketTtl)
+	return
+}
+
+// RedeemStreamTicket uses up the ticket and returns its user.
+func (s *service) RedeemStreamTicket(ticket string) (user models.User, err error) {
+	streamTicket, err := s.Repository.ConsumeStreamTicket(s.Db, hashStreamTicket(ticket), time.Now())
+	if err != nil {
+		err = errors.New("invalid ticket")
+		return
+	}
+
+	return s.GetUserById(models.RequestGetUserById{Id: streamTicket.UserId})
+}
+
+func hashStreamTicket(ticket string) string {
+	sum := sha256.Sum256([]byte(ticket))
+	return hex.EncodeToString(sum[:])
+}
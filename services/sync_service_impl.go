@@ -0,0 +1,417 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-crud-api/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	SyncApplied  = "applied"
+	SyncConflict = "conflict"
+	SyncError    = "error"
+
+	defaultSyncLimit = 1000
+	maxSyncLimit     = 5000
+	maxSyncChanges   = 500
+)
+
+// syncEventTypes are the domain events a pull is built from.
+var syncEventTypes = []string{
+	EventTransactionCreated, EventTransactionUpdated, EventTransactionDeleted,
+	EventCategoryCreated, EventCategoryUpdated, EventCategoryDeleted,
+	EventTagCreated, EventTagUpdated, EventTagDeleted,
+}
+
+var errSyncTokenAhead = errors.New("invalid since: the token is ahead of the server, sync again without since")
+
+// PullSync returns the user's transactions, tags and the shared categories
+// that changed after the token, compacted to the last change of each. The
+// token is the sequence of the last domain event included. Without a token
+// everything is returned along with a token to continue from.
+func (s *service) PullSync(req models.RequestSync, userId int) (response models.ResponseSync, err error) {
+	limit := defaultSyncLimit
+	if req.Limit != "" {
+		limit, err = strconv.Atoi(req.Limit)
+		if err != nil || limit < 1 || limit > maxSyncLimit {
+			err = fmt.Errorf("invalid limit: use 1 to %d", maxSyncLimit)
+			return
+		}
+	}
+
+	latest, err := s.Repository.GetLatestEventSequence(s.Db)
+	if err != nil {
+		return
+	}
+
+	response = models.ResponseSync{
+		Transactions: models.SyncTransactionChanges{Upserted: []models.TransactionResponse{}, Deleted: []models.SyncTombstone{}},
+		Categories:   models.SyncCategoryChanges{Upserted: []models.Category{}, Deleted: []models.SyncTombstone{}},
+		Tags:         models.SyncTagChanges{Upserted: []models.Tag{}, Deleted: []models.SyncTombstone{}},
+	}
+
+	if req.Since == "" {
+		err = s.fullSync(&response, userId)
+		response.Full = true
+		response.Token = encodeSyncToken(latest)
+		return
+	}
+
+	since, err := decodeSyncToken(req.Since)
+	if err != nil {
+		return
+	}
+	if since > latest {
+		err = errSyncTokenAhead
+		return
+	}
+
+	events, err := s.Repository.GetUserDomainEventsAfter(s.Db, since, userId, syncEventTypes, limit+1)
+	if err != nil {
+		return
+	}
+	if len(events) > limit {
+		events = events[:limit]
+		response.HasMore = true
+	}
+
+	response.Token = req.Since
+	if len(events) > 0 {
+		response.Token = encodeSyncToken(*events[len(events)-1].Sequence)
+	}
+
+	err = compactSyncEvents(&response, events)
+	return
+}
+
+// fullSync fills the response with the current state. Changes committed
+// while it reads may come again on the next pull, which only repeats them.
+func (s *service) fullSync(response *models.ResponseSync, userId int) (err error) {
+	transactions, err := s.Repository.GetUserTransactions(s.Db, userId)
+	if err != nil {
+		return
+	}
+	for _, transaction := range transactions {
+		response.Transactions.Upserted = append(response.Transactions.Upserted, toTransactionResponse(transaction))
+	}
+
+	categories, err := s.Repository.GetAllCategories(s.Db)
+	if err != nil {
+		return
+	}
+	response.Categories.Upserted = append(response.Categories.Upserted, categories...)
+
+	tags, err := s.Repository.GetUserTags(s.Db, userId)
+	if err != nil {
+		return
+	}
+	response.Tags.Upserted = append(response.Tags.Upserted, tags...)
+	return
+}
+
+// compactSyncEvents keeps the last event of every entity, in the order of
+// those last events, as an upsert of its payload or a tombstone.
+func compactSyncEvents(response *models.ResponseSync, events []models.DomainEvent) (err error) {
+	last := map[string]int{}
+	for i, event := range events {
+		entity, _, _ := strings.Cut(event.Type, ".")
+		last[fmt.Sprintf("%s:%d", entity, event.AggregateId)] = i
+	}
+
+	for i, event := range events {
+		entity, action, _ := strings.Cut(event.Type, ".")
+		if last[fmt.Sprintf("%s:%d", entity, event.AggregateId)] != i {
+			continue
+		}
+
+		if action == "deleted" {
+			var tombstone models.SyncTombstone
+			err = json.Unmarshal(event.Payload, &tombstone)
+			if err != nil {
+				return
+			}
+			switch entity {
+			case "transaction":
+				response.Transactions.Deleted = append(response.Transactions.Deleted, tombstone)
+			case "category":
+				response.Categories.Deleted = append(response.Categories.Deleted, tombstone)
+			case "tag":
+				response.Tags.Deleted = append(response.Tags.Deleted, tombstone)
+			}
+			continue
+		}
+
+		switch entity {
+		case "transaction":
+			var transaction models.TransactionResponse
+			err = json.Unmarshal(event.Payload, &transaction)
+			response.Transactions.Upserted = append(response.Transactions.Upserted, transaction)
+		case "category":
+			var category models.Category
+			err = json.Unmarshal(event.Payload, &category)
+			response.Categories.Upserted = append(response.Categories.Upserted, category)
+		case "tag":
+			var tag models.Tag
+			err = json.Unmarshal(event.Payload, &tag)
+			response.Tags.Upserted = append(response.Tags.Upserted, tag)
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// PushSync applies a batch of offline changes in order. Each change is
+// applied or skipped on its own; see README for the conflict rules.
+func (s *service) PushSync(req models.RequestPushSync, userId int) (response models.ResponsePushSync, err error) {
+	if len(req.Changes) == 0 || len(req.Changes) > maxSyncChanges {
+		err = fmt.Errorf("changes must have between 1 and %d items", maxSyncChanges)
+		return
+	}
+
+	var removedAttachments []models.Attachment
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		for i, change := range req.Changes {
+			if errSave := tx.SavePoint("sync_change").Error; errSave != nil {
+				return errSave
+			}
+
+			result, attachments, errChange := s.applySyncChange(tx, userId, change)
+			result.Index = i
+			result.Entity = change.Entity
+			result.Op = change.Op
+			if result.Uuid == "" {
+				result.Uuid = change.Uuid
+			}
+			if errChange != nil {
+				result.Status = SyncError
+				result.Error = errChange.Error()
+				result.Current = nil
+			}
+
+			if result.Status != SyncApplied {
+				if errRollback := tx.RollbackTo("sync_change").Error; errRollback != nil {
+					return errRollback
+				}
+			} else {
+				removedAttachments = append(removedAttachments, attachments...)
+			}
+			response.Results = append(response.Results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	s.removeStoredFiles(removedAttachments)
+
+	for _, result := range response.Results {
+		switch result.Status {
+		case SyncApplied:
+			response.Applied++
+		case SyncConflict:
+			response.Conflicts++
+		default:
+			response.Failed++
+		}
+	}
+	return
+}
+
+func (s *service) applySyncChange(tx *gorm.DB, userId int, change models.SyncChange) (result models.SyncChangeResult, attachments []models.Attachment, err error) {
+	if change.Op != "upsert" && change.Op != "delete" {
+		err = errors.New("invalid op: use upsert or delete")
+		return
+	}
+
+	result.Uuid, err = normalizeUuid("uuid", change.Uuid)
+	if err != nil {
+		return
+	}
+
+	switch change.Entity {
+	case "transaction":
+		result, attachments, err = s.applyTransactionSyncChange(tx, userId, change, result.Uuid)
+	case "tag":
+		result, err = s.applyTagSyncChange(tx, userId, change, result.Uuid)
+	case "category":
+		err = errors.New("categories are managed by admins and cannot be pushed")
+	default:
+		err = errors.New("invalid entity: use transaction or tag")
+	}
+	return
+}
+
+func (s *service) applyTransactionSyncChange(tx *gorm.DB, userId int, change models.SyncChange, uuid string) (result models.SyncChangeResult, attachments []models.Attachment, err error) {
+	result.Uuid = uuid
+
+	existing, err := s.Repository.GetTransactionByUuid(tx, uuid)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+	found := err == nil
+	err = nil
+
+	if found {
+		if existing.UserId != userId {
+			err = errors.New("unauthorized: transaction does not belong to this user")
+			return
+		}
+		if change.BaseUpdatedAt != "" && !sameSyncVersion(change.BaseUpdatedAt, existing.UpdatedAt) {
+			result.Status = SyncConflict
+			result.Current = toTransactionResponse(existing)
+			return
+		}
+	} else if change.BaseUpdatedAt != "" && change.Op == "upsert" {
+		// Deleted on the server after the client last saw it
+		result.Status = SyncConflict
+		return
+	}
+
+	result.Status = SyncApplied
+	if change.Op == "delete" {
+		if found {
+			attachments, err = s.deleteTransaction(tx, existing.Id, userId)
+		}
+		return
+	}
+
+	var req models.RequestCreateTransaction
+	err = decodeSyncData(change.Data, &req)
+	if err != nil {
+		return
+	}
+
+	var transaction models.Transaction
+	if !found {
+		req.Uuid = uuid
+		transaction, err = s.createTransaction(tx, userId, req)
+	} else {
+		patch := models.RequestPatchTransaction{
+			Amount:     models.Nullable[float64]{Set: true, Value: req.Amount},
+			Type:       models.Nullable[string]{Set: true, Value: req.Type},
+			CategoryId: models.Nullable[int]{Set: true, Value: req.CategoryId},
+			Note:       models.Nullable[string]{Set: true, Value: req.Note},
+			Payee:      models.Nullable[string]{Set: true, Value: req.Payee},
+			Tags:       models.Nullable[[]string]{Set: true, Value: req.Tags},
+		}
+		if req.OccurredAt != "" {
+			patch.OccurredAt = models.Nullable[string]{Set: true, Value: req.OccurredAt}
+		}
+		transaction, err = s.patchTransaction(tx, existing.Id, userId, patch)
+	}
+	if err != nil {
+		return
+	}
+	result.Current = toTransactionResponse(transaction)
+	return
+}
+
+func (s *service) applyTagSyncChange(tx *gorm.DB, userId int, change models.SyncChange, uuid string) (result models.SyncChangeResult, err error) {
+	result.Uuid = uuid
+
+	existing, err := s.Repository.GetTagByUuid(tx, uuid)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+	found := err == nil
+	err = nil
+
+	if found {
+		if existing.UserId != userId {
+			err = errors.New("unauthorized: tag does not belong to this user")
+			return
+		}
+		if change.BaseUpdatedAt != "" && !sameSyncVersion(change.BaseUpdatedAt, existing.UpdatedAt) {
+			result.Status = SyncConflict
+			result.Current = existing
+			return
+		}
+	} else if change.BaseUpdatedAt != "" && change.Op == "upsert" {
+		result.Status = SyncConflict
+		return
+	}
+
+	result.Status = SyncApplied
+	if change.Op == "delete" {
+		if found {
+			err = s.deleteTag(tx, existing)
+		}
+		return
+	}
+
+	var data struct {
+		Name string `json:"name"`
+	}
+	err = decodeSyncData(change.Data, &data)
+	if err != nil {
+		return
+	}
+	name := strings.TrimSpace(data.Name)
+
+	var tag models.Tag
+	if !found {
+		err = s.validateTagName(tx, userId, name, 0)
+		if err != nil {
+			return
+		}
+		tag, err = s.createTag(tx, userId, name, uuid)
+	} else {
+		err = s.validateTagName(tx, userId, name, existing.Id)
+		if err != nil {
+			return
+		}
+		tag, err = s.updateTag(tx, existing.Id, name)
+	}
+	if err != nil {
+		return
+	}
+	result.Current = tag
+	return
+}
+
+func decodeSyncData(data json.RawMessage, target interface{}) error {
+	if len(data) == 0 || string(data) == "null" {
+		return errors.New("data is required for upsert")
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("invalid data: %v", err)
+	}
+	return nil
+}
+
+// sameSyncVersion compares the updated_at a client last pulled with the
+// server's, at the microseconds the database keeps: the version of a
+// transaction or the updated_at of a tag, both RFC 3339. Two writes in the
+// same second are still told apart.
+func sameSyncVersion(base string, updatedAt time.Time) bool {
+	parsed, err := time.Parse(time.RFC3339Nano, base)
+	return err == nil && parsed.Equal(updatedAt.Truncate(time.Microsecond))
+}
+
+func encodeSyncToken(sequence int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("seq:" + strconv.FormatInt(sequence, 10)))
+}
+
+func decodeSyncToken(token string) (sequence int64, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		value, found := strings.CutPrefix(string(data), "seq:")
+		sequence, err = strconv.ParseInt(value, 10, 64)
+		if !found || sequence < 0 {
+			err = errors.New("bad token")
+		}
+	}
+	if err != nil {
+		err = errors.New("invalid since: use the token of a previous sync")
+	}
+	return
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"encoding/json"
+	"go-crud-api/models"
+	"testing"
+	"time"
+)
+
+func TestPushSyncConflicts(t *testing.T) {
+	// Two writes in the same second differ only below the second
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 123456000, time.UTC)
+	version := updatedAt.Format(time.RFC3339Nano)
+	data := json.RawMessage(`{"amount": 75000, "type": "expense", "category_id": 1}`)
+
+	tests := []struct {
+		name        string
+		change      func(uuid string) models.SyncChange
+		wantStatus  string
+		wantCurrent bool
+		wantAmount  float64 // stored afterwards, 0 once deleted
+	}{
+		{
+			name: "matching version applied",
+			change: func(uuid string) models.SyncChange {
+				return models.SyncChange{Entity: "transaction", Op: "upsert", Uuid: uuid, BaseUpdatedAt: version, Data: data}
+			},
+			wantStatus:  SyncApplied,
+			wantCurrent: true,
+			wantAmount:  75000,
+		},
+		{
+			name: "stale version in the same second",
+			change: func(uuid string) models.SyncChange {
+				return models.SyncChange{Entity: "transaction", Op: "upsert", Uuid: uuid, BaseUpdatedAt: updatedAt.Truncate(time.Second).Format(time.RFC3339), Data: data}
+			},
+			wantStatus:  SyncConflict,
+			wantCurrent: true,
+			wantAmount:  50000,
+		},
+		{
+			name: "no base is last write wins",
+			change: func(uuid string) models.SyncChange {
+				return models.SyncChange{Entity: "transaction", Op: "upsert", Uuid: uuid, Data: data}
+			},
+			wantStatus:  SyncApplied,
+			wantCurrent: true,
+			wantAmount:  75000,
+		},
+		{
+			name: "stale delete",
+			change: func(uuid string) models.SyncChange {
+				return models.SyncChange{Entity: "transaction", Op: "delete", Uuid: uuid, BaseUpdatedAt: updatedAt.Add(-time.Minute).Format(time.RFC3339Nano)}
+			},
+			wantStatus:  SyncConflict,
+			wantCurrent: true,
+			wantAmount:  50000,
+		},
+		{
+			name: "matching delete",
+			change: func(uuid string) models.SyncChange {
+				return models.SyncChange{Entity: "transaction", Op: "delete", Uuid: uuid, BaseUpdatedAt: version}
+			},
+			wantStatus: SyncApplied,
+		},
+		{
+			// Deleted on the server after the client last saw it
+			name: "upsert of a deleted transaction",
+			change: func(string) models.SyncChange {
+				return models.SyncChange{Entity: "transaction", Op: "upsert", Uuid: newUuid(), BaseUpdatedAt: version, Data: data}
+			},
+			wantStatus: SyncConflict,
+			wantAmount: 50000,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestService(t)
+			transaction := createTestTransaction(t, s, models.Transaction{UserId: 1, Amount: 50000, OccurredAt: updatedAt, UpdatedAt: updatedAt})
+
+			response, err := s.PushSync(models.RequestPushSync{Changes: []models.SyncChange{test.change(transaction.Uuid)}}, 1)
+			if err != nil {
+				t.Fatalf("push sync: %v", err)
+			}
+			result := response.Results[0]
+			if result.Status != test.wantStatus {
+				t.Fatalf("expected status %s, got %s (%s)", test.wantStatus, result.Status, result.Error)
+			}
+			if (result.Current != nil) != test.wantCurrent {
+				t.Errorf("expected current %v, got %+v", test.wantCurrent, result.Current)
+			}
+			if result.Status == SyncConflict {
+				if response.Conflicts != 1 || response.Applied != 0 {
+					t.Errorf("expected 1 conflict and nothing applied, got %+v", response)
+				}
+				if test.wantCurrent {
+					current, ok := result.Current.(models.TransactionResponse)
+					if !ok || current.Amount != 50000 || current.Version != version {
+						t.Errorf("expected the server copy at version %s, got %+v", version, result.Current)
+					}
+				}
+			}
+
+			var stored models.Transaction
+			s.Db.Where("id = ?", transaction.Id).Limit(1).Find(&stored)
+			if stored.Amount != test.wantAmount {
+				t.Errorf("expected stored amount %v, got %v", test.wantAmount, stored.Amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const maxTagNameLength = 50
+
+func (s *service) CreateTag(userId int, req models.RequestCreateTag) (tag models.Tag, err error) {
+	name := strings.TrimSpace(req.Name)
+	err = s.validateTagName(s.Db, userId, name, 0)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		tag, errTx = s.createTag(tx, userId, name, "")
+		return
+	})
+	return
+}
+
+// createTag inserts the tag, with a new uuid when none is given, and
+// records tag.created.
+func (s *service) createTag(db *gorm.DB, userId int, name string, uuid string) (tag models.Tag, err error) {
+	if uuid == "" {
+		uuid = newUuid()
+	}
+
+	tag, err = s.Repository.CreateTag(db, models.Tag{Uuid: uuid, UserId: userId, Name: name})
+	if err != nil {
+		return
+	}
+
+	err = s.recordTagEvent(db, EventTagCreated, tag)
+	return
+}
+
+func (s *service) GetTags(req models.RequestGetTags) (response models.ResponseTagList, err error) {
+	pagination, err := helper.ParsePagination(req.RequestPagination, tagSortColumns, "name:asc")
+	if err != nil {
+		return
+	}
+
+	count, tags, err := s.Repository.GetTags(s.Db, req.UserId, req.Name, pagination)
+	if err != nil {
+		return
+	}
+	tags, hasMore := helper.TrimCursorPage(tags, pagination)
+
+	response = models.ResponseTagList{
+		Count: count,
+		Page:  pagination.Page,
+		Limit: pagination.Limit,
+		Data:  tags,
+	}
+
+	if pagination.UseCursor && len(tags) > 0 {
+		first, last := tags[0], tags[len(tags)-1]
+		column := pagination.Sort.Column
+		response.NextCursor, response.PrevCursor = helper.CursorLinks(pagination, hasMore,
+			tagSortValue(first, column), first.Id, tagSortValue(last, column), last.Id)
+	}
+	return
+}
+
+func (s *service) GetTagById(req models.RequestGetTagById, userId int) (tag models.Tag, err error) {
+	tag, err = s.getOwnedTag(req.Id, userId)
+	return
+}
+
+func (s *service) UpdateTag(id int, userId int, req models.RequestUpdateTag) (tag models.Tag, err error) {
+	_, err = s.getOwnedTag(id, userId)
+	if err != nil {
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	err = s.validateTagName(s.Db, userId, name, id)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) (errTx error) {
+		tag, errTx = s.updateTag(tx, id, name)
+		return
+	})
+	return
+}
+
+func (s *service) updateTag(db *gorm.DB, id int, name string) (tag models.Tag, err error) {
+	err = s.Repository.UpdateTag(db, id, name)
+	if err != nil {
+		return
+	}
+
+	tag, err = s.Repository.GetTagById(db, id)
+	if err != nil {
+		return
+	}
+
+	err = s.recordTagEvent(db, EventTagUpdated, tag)
+	return
+}
+
+func (s *service) DeleteTag(id int, userId int) (err error) {
+	tag, err := s.getOwnedTag(id, userId)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		return s.deleteTag(tx, tag)
+	})
+	return
+}
+
+func (s *service) deleteTag(db *gorm.DB, tag models.Tag) (err error) {
+	transactionIds, err := s.Repository.DeleteTag(db, tag.Id)
+	if err != nil {
+		return
+	}
+
+	// The transactions lose the tag, so sync and streams see them change
+	for _, transactionId := range transactionIds {
+		_, err = s.recordTransactionUpdated(db, transactionId)
+		if err != nil {
+			return
+		}
+	}
+
+	err = s.recordEvent(db, EventTagDeleted, tag.UserId, tag.Id, deletedEntity{Id: tag.Id, Uuid: tag.Uuid, UserId: tag.UserId})
+	return
+}
+
+func (s *service) GetTagReport(req models.RequestGetTagReport) (response models.ResponseTagReport, err error) {
+	// Default to the current cycle, like GetBalance
+	startDate := req.StartDate
+	endDate := req.EndDate
+	if startDate == "" || endDate == "" {
+		startDate, endDate = currentCycle(time.Now())
+	}
+
+	totals, err := s.Repository.GetTagTotals(s.Db, req.UserId, startDate, endDate)
+	if err != nil {
+		return
+	}
+	if totals == nil {
+		totals = []models.TagTotal{}
+	}
+
+	response = models.ResponseTagReport{
+		UserId:    req.UserId,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Data:      totals,
+	}
+	return
+}
+
+func (s *service) getOwnedTag(id int, userId int) (tag models.Tag, err error) {
+	tag, err = s.Repository.GetTagById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	if tag.UserId != userId {
+		err = errors.New("unauthorized: tag does not belong to this user")
+		return
+	}
+	return
+}
+
+// validateTagName checks the name and that the user has no other tag
+// (excludeId aside, 0 for none) with the same name case-insensitively.
+func (s *service) validateTagName(db *gorm.DB, userId int, name string, excludeId int) error {
+	err := validateTagNameFormat(name)
+	if err != nil {
+		return err
+	}
+
+	existingTag, err := s.Repository.FindTagByName(db, userId, name)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	if existingTag.Id != 0 && existingTag.Id != excludeId {
+		return errors.New("tag name already exists")
+	}
+	return nil
+}
+
+func validateTagNameFormat(name string) error {
+	if name == "" {
+		return errors.New("tag name is required and cannot be empty")
+	}
+	if len(name) > maxTagNameLength {
+		return fmt.Errorf("tag name cannot be longer than %d characters", maxTagNameLength)
+	}
+	return nil
+}
+
+// normalizeTagNames trims and de-duplicates (case-insensitively) the tag
+// names sent with a transaction, validating each one.
+func normalizeTagNames(names []string) (normalized []string, err error) {
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		err = validateTagNameFormat(name)
+		if err != nil {
+			return
+		}
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		normalized = append(normalized, name)
+	}
+	return
+}
+
+// resolveTags returns the user's tags with the given names, creating the
+// missing ones when create is true and skipping them otherwise.
+func (s *service) resolveTags(db *gorm.DB, userId int, names []string, create bool) (tags []models.Tag, err error) {
+	for _, name := range names {
+		tag, errFind := s.Repository.FindTagByName(db, userId, name)
+		if errFind != nil && errFind != gorm.ErrRecordNotFound {
+			err = errFind
+			return
+		}
+
+		if errFind == gorm.ErrRecordNotFound {
+			if !create {
+				continue
+			}
+			tag, err = s.createTag(db, userId, name, "")
+			if err != nil {
+				return
+			}
+		}
+		tags = append(tags, tag)
+	}
+	return
+}
+
+// setTransactionTags replaces the tags of a transaction with the named ones.
+func (s *service) setTransactionTags(db *gorm.DB, transactionId int, userId int, names []string) (err error) {
+	tags, err := s.resolveTags(db, userId, names, true)
+	if err != nil {
+		return
+	}
+	err = s.Repository.ReplaceTransactionTags(db, transactionId, tags)
+	return
+}
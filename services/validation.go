@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-crud-api/models"
+	"go-crud-api/notify"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Validation rules shared by the create, update and patch paths so that a
+// field is checked the same way no matter which endpoint sets it.
+
+func validateAmount(amount float64) error {
+	if amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	return nil
+}
+
+func validateTransactionType(transactionType string) error {
+	if transactionType == "" {
+		return errors.New("type is required and cannot be empty")
+	}
+	if strings.TrimLeft(transactionType, " ") == "" {
+		return errors.New("type cannot contain only spaces")
+	}
+	return nil
+}
+
+func (s *service) validateCategoryId(db *gorm.DB, categoryId int) error {
+	if categoryId <= 0 {
+		return errors.New("category_id is required")
+	}
+
+	_, err := s.Repository.GetCategoryById(db, categoryId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New("category not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// validateCategoryName checks the name and that no other category (excludeId
+// aside, 0 for none) already uses it case-insensitively.
+func (s *service) validateCategoryName(name string, excludeId int) error {
+	if name == "" {
+		return errors.New("category name is required and cannot be empty")
+	}
+	if strings.TrimLeft(name, " ") == "" {
+		return errors.New("category name cannot contain only spaces")
+	}
+
+	var existingCategory models.Category
+	err := s.Db.Where("LOWER(name) = LOWER(?) AND id != ?", name, excludeId).First(&existingCategory).Error
+	if err == nil {
+		return errors.New("category name already exists")
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return nil
+}
+
+func validateUserField(value string) error {
+	if len(value) < 1 {
+		return errors.New("invalid data requested")
+	}
+	return nil
+}
+
+// normalizeRole checks that the role is admin or user. An empty role is the
+// default "user".
+func normalizeRole(role string) (string, error) {
+	switch role {
+	case "":
+		return "user", nil
+	case "admin", "user":
+		return role, nil
+	}
+	return "", errors.New("invalid role: must be admin or user")
+}
+
+// validateUsername checks that the username is not taken by another user
+// (excludeId aside, 0 for none).
+func (s *service) validateUsername(username string, excludeId int) error {
+	if err := validateUserField(username); err != nil {
+		return err
+	}
+
+	existingUser, err := s.Repository.FindUserByUsername(s.Db, username)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	if existingUser.Id != 0 && existingUser.Id != excludeId {
+		return errors.New("username already used")
+	}
+	return nil
+}
+
+const (
+	maxNoteLength  = 500
+	maxPayeeLength = 100
+)
+
+func validateNote(note string) error {
+	if len(note) > maxNoteLength {
+		return fmt.Errorf("note cannot be longer than %d characters", maxNoteLength)
+	}
+	return nil
+}
+
+func validatePayee(payee string) error {
+	if len(payee) > maxPayeeLength {
+		return fmt.Errorf("payee cannot be longer than %d characters", maxPayeeLength)
+	}
+	return nil
+}
+
+// parseOccurredAt accepts a plain date (midnight local time) or an RFC 3339
+// datetime. An empty value means now.
+func parseOccurredAt(value string) (occurredAt time.Time, err error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+	occurredAt, err = time.ParseInLocation("2006-01-02", value, time.Local)
+	if err == nil {
+		return
+	}
+	occurredAt, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		err = errors.New("invalid occurred_at: use YYYY-MM-DD or RFC 3339 datetime")
+	}
+	return
+}
+
+const (
+	maxWebhookUrlLength  = 2048
+	webhookLookupTimeout = 5 * time.Second
+)
+
+// validateWebhookUrl checks that raw is an absolute http or https URL whose
+// host resolves to public addresses only; field names the request field in
+// the error. The webhook clients check the address again when they connect.
+func validateWebhookUrl(field string, raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" || len(raw) > maxWebhookUrlLength {
+		return fmt.Errorf("invalid %s: use an http or https URL", field)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookLookupTimeout)
+	defer cancel()
+	if err = notify.CheckPublicHost(ctx, parsed.Hostname()); err != nil {
+		return fmt.Errorf("invalid %s: the host must resolve to a public internet address", field)
+	}
+	return nil
+}
+
+// newUuid returns a time-ordered (v7) UUID for a new row.
+func newUuid() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
+
+// normalizeUuid validates a client-supplied UUID and returns it in the
+// canonical lowercase form.
+func normalizeUuid(field string, raw string) (string, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil || len(raw) != 36 {
+		return "", fmt.Errorf("invalid %s: use a UUID like 0190b3c4-8a8e-7c4e-9f4e-6e2f1c9a2b3d", field)
+	}
+	return id.String(), nil
+}
+
+// Entities with a public id that routes accept in place of the integer id.
+const (
+	PublicIdUser        = "user"
+	PublicIdCategory    = "category"
+	PublicIdTransaction = "transaction"
+	PublicIdTag         = "tag"
+)
+
+var publicIdModels = map[string]interface{}{
+	PublicIdUser:        &models.User{},
+	PublicIdCategory:    &models.Category{},
+	PublicIdTransaction: &models.Transaction{},
+	PublicIdTag:         &models.Tag{},
+}
+
+// publicIdOwned are the entities that belong to a user. Their public ids only
+// resolve for their owner.
+var publicIdOwned = map[string]bool{
+	PublicIdTransaction: true,
+	PublicIdTag:         true,
+}
+
+// IsUuid reports whether raw looks like a public id rather than an integer
+// id.
+func IsUuid(raw string) bool {
+	_, err := uuid.Parse(raw)
+	return err == nil && len(raw) == 36
+}
+
+// ResolvePublicId returns the integer id of the entity with the public id.
+// Another user's transaction or tag is not found, like a public id that does
+// not exist, so a uuid never tells whether it exists for someone else.
+func (s *service) ResolvePublicId(entity string, raw string, userId int) (id int, err error) {
+	model, ok := publicIdModels[entity]
+	if !ok {
+		err = fmt.Errorf("invalid entity: %s has no public id", entity)
+		return
+	}
+
+	publicId, err := normalizeUuid("id", raw)
+	if err != nil {
+		return
+	}
+
+	ownerId := 0
+	if publicIdOwned[entity] {
+		ownerId = userId
+	}
+	id, err = s.Repository.FindIdByUuid(s.Db, model, publicId, ownerId)
+	return
+}
+
+// newPublicId returns the client-supplied public id for a new row of the
+// model, or a new one when the client left it out.
+func (s *service) newPublicId(db *gorm.DB, model interface{}, raw string) (publicId string, err error) {
+	if raw == "" {
+		publicId = newUuid()
+		return
+	}
+
+	publicId, err = normalizeUuid("uuid", raw)
+	if err != nil {
+		return
+	}
+
+	_, err = s.Repository.FindIdByUuid(db, model, publicId, 0)
+	if err == nil {
+		err = errors.New("invalid uuid: already used")
+		return
+	}
+	if err == gorm.ErrRecordNotFound {
+		err = nil
+	}
+	return
+}
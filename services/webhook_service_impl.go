@@ -0,0 +1,422 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-crud-api/helper"
+	"go-crud-api/models"
+	"go-crud-api/notify"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	WebhookPending   = "pending"
+	WebhookSucceeded = "succeeded"
+	WebhookFailed    = "failed"
+
+	maxWebhookDescriptionLength = 255
+	minWebhookSecretLength      = 16
+	maxWebhookSecretLength      = 256
+
+	// maxWebhookAttempts is how often a delivery is tried before it fails
+	maxWebhookAttempts = 8
+	// The wait after the n-th failed attempt is webhookRetryBase * 2^(n-1),
+	// at most webhookMaxRetryDelay
+	webhookRetryBase     = 30 * time.Second
+	webhookMaxRetryDelay = time.Hour
+	webhookTimeout       = 10 * time.Second
+	// webhookBatchSize deliveries are claimed per run and held for
+	// webhookLease, longer than sending all of them can take
+	webhookBatchSize = 20
+	webhookLease     = 5 * time.Minute
+	// maxWebhookErrorLength bounds the error text kept in the delivery log
+	maxWebhookErrorLength = 500
+)
+
+// webhookClient does not follow redirects so payloads only go to the
+// subscribed URL, and only connects to public addresses.
+var webhookClient = notify.NewPublicClient(webhookTimeout)
+
+// CreateWebhookSubscription returns the secret once; a random one is
+// generated when the request has none.
+func (s *service) CreateWebhookSubscription(req models.RequestCreateWebhookSubscription) (response models.ResponseWebhookSubscription, err error) {
+	subscription, err := buildWebhookSubscription(req)
+	if err != nil {
+		return
+	}
+	if subscription.Secret == "" {
+		var secret string
+		secret, err = randomHex(24)
+		if err != nil {
+			return
+		}
+		subscription.Secret = "whsec_" + secret
+	}
+
+	subscription, err = s.Repository.CreateWebhookSubscription(s.Db, subscription)
+	if err != nil {
+		return
+	}
+
+	response = models.ResponseWebhookSubscription{WebhookSubscription: subscription, Secret: subscription.Secret}
+	return
+}
+
+func (s *service) GetWebhookSubscriptions() (subscriptions []models.WebhookSubscription, err error) {
+	subscriptions, err = s.Repository.GetWebhookSubscriptions(s.Db)
+	if subscriptions == nil {
+		subscriptions = []models.WebhookSubscription{}
+	}
+	return
+}
+
+func (s *service) GetWebhookSubscriptionById(req models.RequestGetWebhookSubscriptionById) (subscription models.WebhookSubscription, err error) {
+	subscription, err = s.Repository.GetWebhookSubscriptionById(s.Db, req.Id)
+	return
+}
+
+// UpdateWebhookSubscription replaces the subscription. The secret is kept
+// when none is given, and only returned when it was changed.
+func (s *service) UpdateWebhookSubscription(id int, req models.RequestUpdateWebhookSubscription) (response models.ResponseWebhookSubscription, err error) {
+	existing, err := s.Repository.GetWebhookSubscriptionById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	subscription, err := buildWebhookSubscription(models.RequestCreateWebhookSubscription(req))
+	if err != nil {
+		return
+	}
+	subscription.Id = existing.Id
+	subscription.CreatedAt = existing.CreatedAt
+	if subscription.Secret == "" {
+		subscription.Secret = existing.Secret
+	}
+
+	err = s.Repository.UpdateWebhookSubscription(s.Db, subscription)
+	if err != nil {
+		return
+	}
+
+	subscription, err = s.Repository.GetWebhookSubscriptionById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	response.WebhookSubscription = subscription
+	if req.Secret != "" {
+		response.Secret = subscription.Secret
+	}
+	return
+}
+
+func (s *service) DeleteWebhookSubscription(id int) (err error) {
+	_, err = s.Repository.GetWebhookSubscriptionById(s.Db, id)
+	if err != nil {
+		return
+	}
+
+	err = s.Db.Transaction(func(tx *gorm.DB) error {
+		return s.Repository.DeleteWebhookSubscription(tx, id)
+	})
+	return
+}
+
+func (s *service) GetWebhookDeliveries(req models.RequestGetWebhookDeliveries) (response models.ResponseWebhookDeliveryList, err error) {
+	_, err = s.Repository.GetWebhookSubscriptionById(s.Db, req.SubscriptionId)
+	if err != nil {
+		return
+	}
+
+	if req.Status != "" && req.Status != WebhookPending && req.Status != WebhookSucceeded && req.Status != WebhookFailed {
+		err = errors.New("invalid status: use pending, succeeded or failed")
+		return
+	}
+
+	pagination, err := helper.ParsePagination(req.RequestPagination, webhookDeliverySortColumns, "created_at:desc")
+	if err != nil {
+		return
+	}
+
+	count, deliveries, err := s.Repository.GetWebhookDeliveries(s.Db, req.SubscriptionId, req.Status, pagination)
+	if err != nil {
+		return
+	}
+	deliveries, hasMore := helper.TrimCursorPage(deliveries, pagination)
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+
+	response = models.ResponseWebhookDeliveryList{
+		Count: count,
+		Page:  pagination.Page,
+		Limit: pagination.Limit,
+		Data:  deliveries,
+	}
+
+	if pagination.UseCursor && len(deliveries) > 0 {
+		first, last := deliveries[0], deliveries[len(deliveries)-1]
+		column := pagination.Sort.Column
+		response.NextCursor, response.PrevCursor = helper.CursorLinks(pagination, hasMore,
+			webhookDeliverySortValue(first, column), first.Id, webhookDeliverySortValue(last, column), last.Id)
+	}
+	return
+}
+
+// RedeliverWebhook queues the event of a finished delivery again as a new
+// delivery, so the log of the original stays intact. The event id is kept
+// for receivers that deduplicate.
+func (s *service) RedeliverWebhook(req models.RequestGetWebhookDelivery) (delivery models.WebhookDelivery, err error) {
+	_, err = s.Repository.GetWebhookSubscriptionById(s.Db, req.SubscriptionId)
+	if err != nil {
+		return
+	}
+
+	original, err := s.Repository.GetWebhookDeliveryById(s.Db, req.DeliveryId)
+	if err != nil {
+		return
+	}
+	if original.SubscriptionId != req.SubscriptionId {
+		err = gorm.ErrRecordNotFound
+		return
+	}
+	if original.Status == WebhookPending {
+		err = errors.New("delivery is still pending")
+		return
+	}
+
+	now := time.Now()
+	deliveries := []models.WebhookDelivery{{
+		SubscriptionId: original.SubscriptionId,
+		EventId:        original.EventId,
+		Event:          original.Event,
+		Payload:        original.Payload,
+		Status:         WebhookPending,
+		NextAttemptAt:  &now,
+		RedeliveryOf:   &original.Id,
+	}}
+	err = s.Repository.CreateWebhookDeliveries(s.Db, deliveries)
+	if err != nil {
+		return
+	}
+
+	delivery = deliveries[0]
+	return
+}
+
+// ProcessWebhookDeliveries sends the queued deliveries that are due. It is
+// run by the scheduler; each delivery records its own outcome, and failed
+// attempts are retried with exponential backoff.
+func (s *service) ProcessWebhookDeliveries() (err error) {
+	now := time.Now()
+	deliveries, err := s.Repository.ClaimWebhookDeliveries(s.Db, now, now.Add(webhookLease), webhookBatchSize)
+	if err != nil {
+		return
+	}
+
+	subscriptions := map[int]models.WebhookSubscription{}
+	var failed []string
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionId]
+		if !ok {
+			subscription, err = s.Repository.GetWebhookSubscriptionById(s.Db, delivery.SubscriptionId)
+			if err != nil && err != gorm.ErrRecordNotFound {
+				return
+			}
+			err = nil
+			subscriptions[delivery.SubscriptionId] = subscription
+		}
+
+		errDelivery := s.attemptWebhookDelivery(subscription, delivery)
+		if errDelivery != nil {
+			failed = append(failed, fmt.Sprintf("delivery %d: %v", delivery.Id, errDelivery))
+		}
+	}
+	if len(failed) > 0 {
+		err = fmt.Errorf("webhook deliveries failed to save for %d of %d: %s", len(failed), len(deliveries), strings.Join(failed, "; "))
+	}
+	return
+}
+
+// attemptWebhookDelivery sends the delivery once and records the outcome.
+// The returned error is only about saving that outcome.
+func (s *service) attemptWebhookDelivery(subscription models.WebhookSubscription, delivery models.WebhookDelivery) (err error) {
+	now := time.Now()
+
+	if subscription.Id == 0 || !subscription.Active {
+		err = s.Repository.UpdateWebhookDeliveryFields(s.Db, delivery.Id, map[string]interface{}{
+			"status":          WebhookFailed,
+			"next_attempt_at": nil,
+			"last_error":      "subscription is inactive",
+		})
+		return
+	}
+
+	statusCode, errSend := sendWebhook(subscription, delivery)
+	attempts := delivery.Attempts + 1
+	fields := map[string]interface{}{
+		"attempts":        attempts,
+		"last_attempt_at": now,
+		"response_status": statusCode,
+	}
+
+	switch {
+	case errSend == nil:
+		fields["status"] = WebhookSucceeded
+		fields["delivered_at"] = time.Now()
+		fields["next_attempt_at"] = nil
+		fields["last_error"] = ""
+	case attempts >= maxWebhookAttempts:
+		fields["status"] = WebhookFailed
+		fields["next_attempt_at"] = nil
+		fields["last_error"] = truncateText(errSend.Error(), maxWebhookErrorLength)
+	default:
+		fields["next_attempt_at"] = now.Add(webhookRetryDelay(attempts))
+		fields["last_error"] = truncateText(errSend.Error(), maxWebhookErrorLength)
+	}
+
+	err = s.Repository.UpdateWebhookDeliveryFields(s.Db, delivery.Id, fields)
+	return
+}
+
+// webhookRetryDelay is the wait after the given number of failed attempts.
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxRetryDelay)
+}
+
+// sendWebhook posts the delivery's payload to the subscription, signed with
+// its secret. Any 2xx response counts as delivered.
+func sendWebhook(subscription models.WebhookSubscription, delivery models.WebhookDelivery) (statusCode int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	payload := []byte(delivery.Payload)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "go-crud-api-webhooks")
+	request.Header.Set("X-Webhook-Event", delivery.Event)
+	request.Header.Set("X-Webhook-Id", delivery.EventId)
+	request.Header.Set("X-Webhook-Delivery", strconv.Itoa(delivery.Id))
+	request.Header.Set("X-Webhook-Signature", helper.SignWebhook(subscription.Secret, time.Now().Unix(), payload))
+
+	response, err := webhookClient.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	statusCode = response.StatusCode
+	if statusCode < 200 || statusCode >= 300 {
+		err = fmt.Errorf("subscriber responded with status %d", statusCode)
+	}
+	return
+}
+
+// HandleWebhookEvent is the webhook subscriber of the event dispatcher. It
+// queues a delivery of the event for every active subscription to it; the
+// event id stays the same when the event is handed over again, so receivers
+// can drop duplicates.
+func (s *service) HandleWebhookEvent(event models.DomainEvent) (err error) {
+	subscriptions, err := s.Repository.GetWebhookSubscriptions(s.Db)
+	if err != nil {
+		return
+	}
+
+	eventId := fmt.Sprintf("evt_%d", event.Id)
+	payload, err := json.Marshal(models.WebhookEvent{
+		Id:        eventId,
+		Event:     event.Type,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	var deliveries []models.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.Active || !slices.Contains(subscription.Events, event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionId: subscription.Id,
+			EventId:        eventId,
+			Event:          event.Type,
+			Payload:        string(payload),
+			Status:         WebhookPending,
+			NextAttemptAt:  &now,
+		})
+	}
+
+	err = s.Repository.CreateWebhookDeliveries(s.Db, deliveries)
+	return
+}
+
+// buildWebhookSubscription validates the request. The secret is left empty
+// when none was given.
+func buildWebhookSubscription(req models.RequestCreateWebhookSubscription) (subscription models.WebhookSubscription, err error) {
+	url := strings.TrimSpace(req.Url)
+	err = validateWebhookUrl("url", url)
+	if err != nil {
+		return
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if len(description) > maxWebhookDescriptionLength {
+		err = fmt.Errorf("description cannot be longer than %d characters", maxWebhookDescriptionLength)
+		return
+	}
+
+	if len(req.Events) == 0 {
+		err = errors.New("events is required")
+		return
+	}
+	events := []string{}
+	for _, event := range req.Events {
+		if !slices.Contains(domainEventTypes, event) {
+			err = fmt.Errorf("invalid event %q: use one of %s", event, strings.Join(domainEventTypes, ", "))
+			return
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	if req.Secret != "" && (len(req.Secret) < minWebhookSecretLength || len(req.Secret) > maxWebhookSecretLength) {
+		err = fmt.Errorf("secret must be between %d and %d characters", minWebhookSecretLength, maxWebhookSecretLength)
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	subscription = models.WebhookSubscription{
+		Url:         url,
+		Description: description,
+		Events:      events,
+		Secret:      req.Secret,
+		Active:      active,
+	}
+	return
+}
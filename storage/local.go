@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStorage struct {
+	Root string
+}
+
+// NewLocalStorage stores files under root on the local filesystem.
+func NewLocalStorage(root string) Storage {
+	return &localStorage{Root: root}
+}
+
+func (l *localStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if strings.Contains(key, "..") {
+		return "", errors.New("invalid storage key")
+	}
+	return filepath.Join(l.Root, cleaned), nil
+}
+
+func (l *localStorage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) (err error) {
+	path, err := l.path(key)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return
+	}
+
+	_, err = io.Copy(file, content)
+	errClose := file.Close()
+	if err == nil {
+		err = errClose
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return
+}
+
+func (l *localStorage) Get(ctx context.Context, key string) (content io.ReadCloser, err error) {
+	path, err := l.path(key)
+	if err != nil {
+		return
+	}
+
+	content, err = os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = ErrNotFound
+	}
+	return
+}
+
+func (l *localStorage) Delete(ctx context.Context, key string) (err error) {
+	path, err := l.path(key)
+	if err != nil {
+		return
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type S3Config struct {
+	Endpoint  string // host[:port], e.g. "s3.amazonaws.com" or "localhost:9000" for MinIO
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
+type s3Storage struct {
+	Client *minio.Client
+	Bucket string
+}
+
+// NewS3Storage stores files in a bucket of any S3-compatible service
+// (AWS S3, MinIO, ...). The bucket is created when it does not exist yet.
+func NewS3Storage(config S3Config) (Storage, error) {
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKey, config.SecretKey, ""),
+		Secure: config.UseSSL,
+		Region: config.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, config.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		err = client.MakeBucket(ctx, config.Bucket, minio.MakeBucketOptions{Region: config.Region})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &s3Storage{Client: client, Bucket: config.Bucket}, nil
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) (err error) {
+	_, err = s.Client.PutObject(ctx, s.Bucket, key, content, size, minio.PutObjectOptions{ContentType: contentType})
+	return
+}
+
+func (s *s3Storage) Get(ctx context.Context, key string) (content io.ReadCloser, err error) {
+	object, err := s.Client.GetObject(ctx, s.Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return
+	}
+
+	// GetObject is lazy; Stat surfaces a missing key before streaming starts
+	_, err = object.Stat()
+	if err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			err = ErrNotFound
+		}
+		return
+	}
+	return object, nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) (err error) {
+	err = s.Client.RemoveObject(ctx, s.Bucket, key, minio.RemoveObjectOptions{})
+	return
+}
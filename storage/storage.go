@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("file not found in storage")
+
+// Storage stores attachment blobs by key. Keys are slash separated paths
+// such as "attachments/1/42/abc.jpg".
+type Storage interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) (err error)
+	Get(ctx context.Context, key string) (content io.ReadCloser, err error)
+	Delete(ctx context.Context, key string) (err error)
+}